@@ -0,0 +1,77 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/triage-party/pkg/triage"
+	"github.com/google/triage-party/pkg/updater"
+)
+
+// dryRunRule is the outcome of a single rule within a dry run
+type dryRunRule struct {
+	Collection string        `json:"collection"`
+	Rule       string        `json:"rule"`
+	Matches    int           `json:"matches"`
+	Duration   time.Duration `json:"duration_ns"`
+	Error      string        `json:"error,omitempty"`
+}
+
+// printDryRun outputs per-rule match counts and durations for each collection
+func printDryRun(ctx context.Context, w io.Writer, tp *triage.Party, u *updater.Updater, format string) error {
+	cols, err := tp.ListCollections()
+	if err != nil {
+		return fmt.Errorf("list collections: %w", err)
+	}
+
+	rows := []dryRunRule{}
+	for _, c := range cols {
+		r := u.Lookup(ctx, c.ID, false)
+		if r == nil {
+			continue
+		}
+		for _, rr := range r.RuleResults {
+			rows = append(rows, dryRunRule{
+				Collection: c.ID,
+				Rule:       rr.Rule.ID,
+				Matches:    len(rr.Items),
+				Duration:   rr.Duration,
+				Error:      rr.Error,
+			})
+		}
+	}
+
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "COLLECTION\tRULE\tMATCHES\tDURATION\tERROR")
+		for _, r := range rows {
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\n", r.Collection, r.Rule, r.Matches, r.Duration.Round(time.Millisecond), r.Error)
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unknown format %q, expected table or json", format)
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// healthcheck queries the readiness endpoint of a running server, returning an exit code for a Docker HEALTHCHECK
+func healthcheck(url string, timeout time.Duration) int {
+	if url == "" {
+		p := os.Getenv("PORT")
+		if p == "" {
+			p = fmt.Sprintf("%d", *port)
+		}
+		url = fmt.Sprintf("http://localhost:%s/readyz", p)
+	}
+
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unhealthy: %v\n", err)
+		return 1
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unhealthy: read: %v\n", err)
+		return 1
+	}
+
+	msg := strings.TrimSpace(string(body))
+	if resp.StatusCode != http.StatusOK {
+		fmt.Fprintf(os.Stderr, "unhealthy: %s returned %d: %s\n", url, resp.StatusCode, msg)
+		return 1
+	}
+
+	fmt.Println(msg)
+	return 0
+}
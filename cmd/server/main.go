@@ -29,6 +29,8 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"html/template"
+	"io"
 	"net/http"
 	"os"
 	"os/signal"
@@ -39,8 +41,12 @@ import (
 	"time"
 
 	"github.com/google/triage-party/pkg/constants"
+	"github.com/google/triage-party/pkg/events"
+	"github.com/google/triage-party/pkg/metrics"
 	"github.com/google/triage-party/pkg/provider"
 
+	"golang.org/x/oauth2"
+	githuboauth "golang.org/x/oauth2/github"
 	"k8s.io/klog/v2"
 
 	"github.com/google/triage-party/pkg/persist"
@@ -54,31 +60,141 @@ var (
 	gitHubAPIURL = flag.String("github-api-url", "", "GitHub API url to connect.  Please set this when you use GitHub Enterprise. This often is your GitHub Enterprise hostname. If the URL does not have the suffix \"/api/v3/\", it will be added automatically.")
 
 	// shared with tester
-	configPath     = flag.String("config", "", "configuration path (defaults to searching for config.yaml)")
-	persistBackend = flag.String("persist-backend", "", "Cache persistence backend (disk, mysql, cloudsql)")
+	configPath     = flag.String("config", "", "configuration path, or - for stdin (defaults to searching for config.yaml)")
+	persistBackend = flag.String("persist-backend", "", "Cache persistence backend (disk, memory, mysql, cloudsql, postgres, redis)")
 	persistPath    = flag.String("persist-path", "", "Where to persist cache to (automatic)")
+	persistNaming  = flag.String("persist-naming", persist.NamingConfig, "How automatic disk cache files are named: config, path (adds a hash of the config path), or content (adds a hash of the config)")
 
 	reposOverride   = flag.String("repos", "", "Override configured repos with this repository (comma separated)")
+	reposFile       = flag.String("repos-file", "", "Override configured repos with those listed in this file, one per line")
 	gitHubTokenFile = flag.String("github-token-file", "", "github token secret file, also settable via "+constants.GitHubTokenEnvVar)
-	gitLabTokenFile = flag.String("gitlab-token-file", "", "github token secret file, also settable via "+constants.GitLabTokenEnvVar)
+	gitLabTokenFile = flag.String("gitlab-token-file", "", "gitlab token secret file, also settable via "+constants.GitLabTokenEnvVar)
 
 	// server specific
-	siteDir       = flag.String("site", "site/", "path to site files")
-	thirdPartyDir = flag.String("3p", "third_party/", "path to 3rd party files")
-	dryRun        = flag.Bool("dry-run", false, "run queries, don't start a server")
-	port          = flag.Int("port", 8080, "port to run server at")
-	siteName      = flag.String("name", "", "override site name from config file")
-	numbers       = flag.String("nums", "", "only display results for these comma-delimited issue/PR numbers (debug)")
+	siteDir         = flag.String("site", "site/", "path to site files")
+	thirdPartyDir   = flag.String("3p", "third_party/", "path to 3rd party files")
+	dryRun          = flag.Bool("dry-run", false, "run queries, don't start a server")
+	noRefresh       = flag.Bool("no-refresh", false, "serve results from the cache only: no token is required, and GitHub is never polled")
+	dryRunFormat    = flag.String("dry-run-format", "table", "output format for --dry-run results: table or json")
+	printSchema     = flag.Bool("print-schema", false, "print the JSON schema for the configuration file and exit")
+	healthCheck     = flag.Bool("healthcheck", false, "check the readiness of a running server and exit 0 (ready) or 1, for use as a Docker HEALTHCHECK")
+	healthCheckURL  = flag.String("healthcheck-url", "", "readiness URL to query for --healthcheck (defaults to /readyz on the local port)")
+	port            = flag.Int("port", 8080, "port to run server at")
+	siteName        = flag.String("name", "", "override site name from config file")
+	logoURL         = flag.String("logo-url", "", "URL or path of a logo to show next to the site name")
+	faviconURL      = flag.String("favicon-url", "", "URL or path of a custom favicon")
+	sortBySize      = flag.Bool("sort-by-size", false, "order collections by their number of items, largest first, rather than config order")
+	footerHTML      = flag.String("footer-html", "", "trusted HTML to show at the bottom of each page")
+	numbers         = flag.String("nums", "", "only display results for these comma-delimited issue/PR numbers (debug)")
+	accessLog       = flag.Bool("access-log", false, "log method, path, status, size, and latency for each request")
+	accessLogStatic = flag.Bool("access-log-static", false, "include static assets in the access log")
+	compress        = flag.Bool("compress", true, "gzip or deflate HTML, JSON, CSV, and other text responses for clients which accept it")
+	allowCIDRs      = flag.String("allow-cidrs", "", "only serve clients within these comma-separated CIDR ranges or addresses, rejecting others with a 403")
+	trustedProxies  = flag.String("trusted-proxies", "", "comma-separated CIDR ranges of proxies whose X-Forwarded-For header is trusted by --allow-cidrs and --rate-limit")
+	rateLimit       = flag.Float64("rate-limit", 0, "requests per second to allow each client, rejecting others with a 429 (0 for unlimited)")
+	rateLimitBurst  = flag.Int("rate-limit-burst", 20, "requests each client may make at once before --rate-limit applies")
+	statsdAddr      = flag.String("statsd-addr", "", "host:port of a StatsD or Datadog agent to send metrics to over UDP")
+	statsdPrefix    = flag.String("statsd-prefix", "triage_party.", "prefix for metric names sent to StatsD")
+
+	prometheusMetrics = flag.Bool("prometheus", false, "serve Prometheus metrics at /metrics, which is not access controlled")
+
+	pubsubTopic     = flag.String("pubsub-topic", "", "Google Cloud Pub/Sub topic to publish triage events to, as projects/<project>/topics/<topic>")
+	pubsubCredsFile = flag.String("pubsub-credentials-file", "", "service account JSON for --pubsub-topic (default: application default credentials)")
+	ghConcurrency   = flag.Int("github-concurrency", 8, "maximum number of in-flight GitHub API requests, shared by all collections (0 for unlimited)")
+	userAgent       = flag.String("user-agent", "", "identifier to append to the User-Agent sent to GitHub, such as your organization or contact address")
+
+	pagerDutyKeyFile     = flag.String("pagerduty-routing-key-file", "", "file containing a PagerDuty Events API v2 routing key, to page when collections breach their SLA")
+	pagerDutyCollections = flag.String("pagerduty-collections", "", "comma-separated collection IDs to page for (default: every collection with an SLA)")
+	pagerDutyMinBreached = flag.Int("pagerduty-min-breached", 1, "how many items in a collection must breach its SLA to trigger an incident")
+	pagerDutySeverity    = flag.String("pagerduty-severity", "critical", "severity of PagerDuty incidents: critical, error, warning, or info")
+
+	ghMaxIdleConns    = flag.Int("github-max-idle-conns-per-host", provider.DefaultMaxIdleConnsPerHost, "idle connections to keep open to each GitHub API host for reuse")
+	ghIdleConnTimeout = flag.Duration("github-idle-conn-timeout", provider.DefaultIdleConnTimeout, "how long an idle GitHub API connection is kept open")
+	ghKeepAlive       = flag.Duration("github-keep-alive", provider.DefaultKeepAlive, "interval between TCP keep-alive probes on GitHub API connections")
 
 	maxRefresh = flag.Duration("max-refresh", 60*time.Minute, "Maximum time between collection runs")
 	minRefresh = flag.Duration("min-refresh", 60*time.Second, "Minimum time between collection runs")
 	warnAge    = flag.Duration("warn-age", 90*time.Minute, "Warn when the results are older than this")
+
+	refreshTimeout = flag.Duration("refresh-timeout", 0, "Cancel an update cycle which runs longer than this, keeping completed collections (0 for no limit)")
+
+	shutdownTimeout = flag.Duration("shutdown-timeout", 20*time.Second, "how long to wait for in-flight requests to finish when shutting down")
+
+	configReloadInterval = flag.Duration("config-reload-interval", 10*time.Second, "how often to check the config file for changes, reloading it when modified (0 to disable)")
+
+	basePath = flag.String("base-path", "", "URL path to serve the site under, such as /triage")
+	density  = flag.String("density", site.ComfortableDensity, "default item layout: comfortable or compact (overridable with ?density=)")
+	ages     = flag.String("ages", site.HumanizedAges, "how ages are displayed: humanized buckets, such as 5wk, or exact days, such as 37d")
+
+	cacheSaveInterval = flag.Duration("cache-save-interval", 0, "Minimum time between cache saves when data has changed (default: --max-refresh)")
+	cacheBodyLength   = flag.Int("cache-body-length", 0, "truncate issue and PR bodies to this many bytes when persisting the cache (0 keeps full bodies, -1 omits them)")
+
+	initCache = flag.String("init-cache", "", "comma-separated disk cache files to merge into the cache before serving, later files winning on conflicting keys")
+
+	// write mode
+	writeMode             = flag.Bool("write-mode", false, "allow logged in users with write access to label and close items")
+	oauthClientID         = flag.String("oauth-client-id", "", "GitHub OAuth application client ID, required for write mode and involves: @me")
+	refreshTokenFile      = flag.String("refresh-token-file", "", "secret file for authenticating POST /refresh requests, also settable via REFRESH_TOKEN")
+	authFile              = flag.String("auth-file", "", "htpasswd-style file of user:password lines, requiring HTTP basic auth for every page")
+	authTokenFile         = flag.String("auth-token-file", "", "file of bearer tokens, one per line, accepted instead of basic auth, also settable via AUTH_TOKENS")
+	authExempt            = flag.String("auth-exempt", strings.Join(site.DefaultAuthExempt, ","), "comma-separated paths served without authentication, such as /metrics")
+	webhookSecretFile     = flag.String("webhook-secret-file", "", "secret file for verifying GitHub webhooks sent to POST /webhook, one secret per line, also settable via WEBHOOK_SECRET")
+	refreshInterval       = flag.Duration("refresh-interval", time.Minute, "minimum time between manual refreshes of a collection")
+	configRequiresAuth    = flag.Bool("config-requires-auth", false, "only serve /config to logged in users, or requests with the --refresh-token-file secret")
+	onDemandAge           = flag.Duration("on-demand-age", 0, "fetch shown items again in the background when a collection is viewed with their data older than this (0 disables)")
+	oauthClientSecretFile = flag.String("oauth-client-secret-file", "", "GitHub OAuth application client secret file, also settable via OAUTH_CLIENT_SECRET")
 )
 
 func main() {
 	klog.InitFlags(nil)
 	flag.Parse()
 
+	if *printSchema {
+		s, err := triage.ConfigSchema()
+		if err != nil {
+			klog.Exitf("schema: %v", err)
+		}
+		fmt.Println(string(s))
+		os.Exit(0)
+	}
+
+	if *healthCheck {
+		os.Exit(healthcheck(*healthCheckURL, 5*time.Second))
+	}
+
+	b := site.Build()
+	klog.Infof("triage-party %s (commit %s, built %s, %s)", b.Version, b.GitCommit, b.BuildDate, b.GoVersion)
+
+	if *statsdAddr != "" {
+		s, err := metrics.NewStatsD(*statsdAddr, *statsdPrefix)
+		if err != nil {
+			klog.Exitf("statsd: %v", err)
+		}
+		metrics.AddSink(s)
+	}
+
+	var prom *metrics.Prometheus
+	if *prometheusMetrics {
+		prom = metrics.NewPrometheus("triage_party_")
+		metrics.AddSink(prom)
+	}
+
+	if *pubsubTopic != "" {
+		s, err := events.NewPubSub(context.Background(), *pubsubTopic, *pubsubCredsFile)
+		if err != nil {
+			klog.Exitf("pubsub: %v", err)
+		}
+		events.AddSink(s)
+	}
+
+	if *pagerDutyKeyFile != "" {
+		s, err := events.NewPagerDuty(provider.ReadToken(*pagerDutyKeyFile, ""), strings.Split(*pagerDutyCollections, ","), *pagerDutyMinBreached, *pagerDutySeverity)
+		if err != nil {
+			klog.Exitf("pagerduty: %v", err)
+		}
+		events.AddSink(s)
+	}
+
 	cp := *configPath
 	if cp == "" {
 		cp = os.Getenv("CONFIG_PATH")
@@ -90,12 +206,23 @@ func main() {
 
 	ctx := context.Background()
 
-	f, err := os.Open(findPath(cp))
+	f, err := openConfig(cp)
 	if err != nil {
 		klog.Exitf("open %s: %v", cp, err)
 	}
 
-	c, err := persist.FromEnv(*persistBackend, *persistPath, cp, *reposOverride)
+	configFile := cp
+	if cp != persist.StdinConfig {
+		configFile = findPath(cp)
+	}
+
+	if !persist.IsNaming(*persistNaming) {
+		klog.Exitf("unknown --persist-naming %q, expected config, path, or content", *persistNaming)
+	}
+
+	persist.MaxBodyLength = *cacheBodyLength
+	persist.DiskNaming = *persistNaming
+	c, err := persist.FromEnv(*persistBackend, *persistPath, configFile, *reposOverride)
 	if err != nil {
 		klog.Exitf("unable to create persistence layer: %v", err)
 	}
@@ -104,6 +231,12 @@ func main() {
 		klog.Exitf("persist initialize for %s: %v", c, err)
 	}
 
+	if *initCache != "" {
+		if err := persist.LoadInit(c, strings.Split(*initCache, ",")); err != nil {
+			klog.Exitf("init cache: %v", err)
+		}
+	}
+
 	var debugNums []int
 	for _, n := range strings.Split(*numbers, ",") {
 		i, err := strconv.Atoi(n)
@@ -116,8 +249,22 @@ func main() {
 		Cache:        c,
 		DebugNumbers: debugNums,
 		GitHubAPIURL: *gitHubAPIURL,
-		GitHubToken:  provider.ReadToken(*gitHubTokenFile, "GITHUB_TOKEN"),
+		GitHubTokens: provider.ReadTokens(*gitHubTokenFile, "GITHUB_TOKEN"),
 		GitLabToken:  provider.ReadToken(*gitLabTokenFile, "GITLAB_TOKEN"),
+		ReposFile:    *reposFile,
+		UserAgent:    fmt.Sprintf("triage-party/%s", site.VERSION),
+		Offline:      *noRefresh,
+
+		GitHubConcurrency: *ghConcurrency,
+		Transport: provider.Transport{
+			MaxIdleConnsPerHost: *ghMaxIdleConns,
+			IdleConnTimeout:     *ghIdleConnTimeout,
+			KeepAlive:           *ghKeepAlive,
+		},
+	}
+
+	if *userAgent != "" {
+		cfg.UserAgent = fmt.Sprintf("%s (%s)", cfg.UserAgent, *userAgent)
 	}
 
 	if *reposOverride != "" {
@@ -151,57 +298,175 @@ func main() {
 	}
 
 	u := updater.New(updater.Config{
-		Party:       tp,
-		MinRefresh:  *minRefresh,
-		MaxRefresh:  *maxRefresh,
-		PersistFunc: c.Cleanup,
+		Party:           tp,
+		MinRefresh:      *minRefresh,
+		MaxRefresh:      *maxRefresh,
+		PersistFunc:     c.Cleanup,
+		PersistInterval: *cacheSaveInterval,
+		NoRefresh:       *noRefresh,
+		CycleTimeout:    *refreshTimeout,
+		HistoryCache:    c,
 	})
 
 	if *dryRun {
+		if *dryRunFormat != "table" && *dryRunFormat != "json" {
+			klog.Exitf("unknown --dry-run-format %q, expected table or json", *dryRunFormat)
+		}
+
 		klog.Infof("Updating ...")
-		if _, err := u.RunOnce(ctx, true); err != nil {
-			klog.Exitf("run failed: %v", err)
+		_, runErr := u.RunOnce(ctx, true)
+		if err := printDryRun(ctx, os.Stdout, tp, u, *dryRunFormat); err != nil {
+			klog.Exitf("print: %v", err)
+		}
+		if runErr != nil {
+			klog.Exitf("run failed: %v", runErr)
 		}
 		os.Exit(0)
 	}
 
 	klog.Infof("Starting update loop: %+v", u)
+	srv := &http.Server{}
+	stopped := make(chan struct{})
 	sigc := make(chan os.Signal, 1)
 	signal.Notify(sigc, os.Interrupt, syscall.SIGTERM)
 	go func() {
 		sig := <-sigc
-		klog.Infof("signal caught: %v (saving!)", sig)
-		u.Persist()
-		klog.Infof("Exiting by signal as requested.")
-		os.Exit(1)
-	}()
+		klog.Infof("signal caught: %v (draining requests for up to %s, then saving!)", sig, *shutdownTimeout)
+		u.Drain()
 
-	go func() {
-		if err := u.Loop(ctx); err == nil {
-			klog.Exitf("loop failed: %v", err)
+		sctx, cancel := context.WithTimeout(context.Background(), *shutdownTimeout)
+		defer cancel()
+		if err := srv.Shutdown(sctx); err != nil {
+			klog.Warningf("shutdown: %v", err)
 		}
+
+		if err := u.Flush(); err != nil {
+			klog.Errorf("persist failed: %v", err)
+		}
+		close(stopped)
 	}()
 
+	if *noRefresh {
+		go func() {
+			if err := u.Snapshot(ctx); err != nil {
+				klog.Errorf("snapshot incomplete: %v", err)
+			}
+		}()
+	} else {
+		go func() {
+			if err := u.Loop(ctx); err == nil {
+				klog.Exitf("loop failed: %v", err)
+			}
+		}()
+	}
+
+	var oc *oauth2.Config
+	if *writeMode && *oauthClientID == "" {
+		klog.Exitf("--write-mode requires --oauth-client-id")
+	}
+	if *oauthClientID != "" {
+		oc = &oauth2.Config{
+			ClientID:     *oauthClientID,
+			ClientSecret: provider.ReadToken(*oauthClientSecretFile, "OAUTH_CLIENT_SECRET"),
+			Endpoint:     githuboauth.Endpoint,
+		}
+	}
+
+	refreshToken := os.Getenv("REFRESH_TOKEN")
+	if *refreshTokenFile != "" {
+		refreshToken = provider.ReadToken(*refreshTokenFile, "REFRESH_TOKEN")
+	}
+
+	webhookSecret := os.Getenv("WEBHOOK_SECRET")
+	if *webhookSecretFile != "" {
+		webhookSecret = provider.ReadToken(*webhookSecretFile, "WEBHOOK_SECRET")
+	}
+	webhookSecrets := []string{}
+	for _, s := range strings.Split(webhookSecret, "\n") {
+		if s = strings.TrimSpace(s); s != "" {
+			webhookSecrets = append(webhookSecrets, s)
+		}
+	}
+
+	if !site.IsDensity(*density) {
+		klog.Exitf("unknown --density %q, expected comfortable or compact", *density)
+	}
+
+	if !site.IsAgeDisplay(*ages) {
+		klog.Exitf("unknown --ages %q, expected humanized or exact", *ages)
+	}
+
+	bp := strings.TrimSuffix(*basePath, "/")
+	if bp != "" && !strings.HasPrefix(bp, "/") {
+		bp = "/" + bp
+	}
+
 	s := site.New(&site.Config{
 		BaseDirectory: findPath(*siteDir),
 		Updater:       u,
 		Party:         tp,
 		WarnAge:       *warnAge,
 		Name:          sn,
+		WriteMode:     *writeMode,
+		OAuth:         oc,
+		Branding: site.Branding{
+			LogoURL:    *logoURL,
+			FaviconURL: *faviconURL,
+			Footer:     template.HTML(*footerHTML),
+		},
+		SortBySize:      *sortBySize,
+		RefreshToken:    refreshToken,
+		RefreshInterval: *refreshInterval,
+		WebhookSecrets:  webhookSecrets,
+		OnDemandAge:     *onDemandAge,
+		Cache:           c,
+		BasePath:        bp,
+		Density:         *density,
+		Ages:            *ages,
+
+		ConfigRequiresAuth: *configRequiresAuth,
 	})
 
-	http.Handle("/third_party/", http.StripPrefix("/third_party/", http.FileServer(http.Dir(findPath(*thirdPartyDir)))))
-	http.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.Dir(filepath.Join(findPath(*siteDir), "static")))))
-	http.HandleFunc("/s/", s.Collection())
-	http.HandleFunc("/k/", s.Kanban())
-	http.HandleFunc("/healthz", s.Healthz())
-	http.HandleFunc("/threadz", s.Threadz())
+	var cr *configReloader
+	if cp != persist.StdinConfig {
+		cr = newConfigReloader(configFile, tp, u, s)
+		if *configReloadInterval > 0 {
+			go cr.watch(*configReloadInterval)
+		}
+	}
+
+	hupc := make(chan os.Signal, 1)
+	signal.Notify(hupc, syscall.SIGHUP)
+	go func() {
+		for range hupc {
+			klog.Infof("SIGHUP caught, reloading config and repos")
+			if cr != nil {
+				if err := cr.reload(); err != nil {
+					klog.Errorf("reload %s failed, keeping the previous config: %v", configFile, err)
+				}
+			}
+			if err := tp.ReloadRepos(); err != nil {
+				klog.Errorf("reload repos: %v", err)
+			}
+		}
+	}()
 
-	// In case the previous handlers are removed by errant security systems
-	http.HandleFunc("/health", s.Healthz())
-	http.HandleFunc("/threads", s.Threadz())
+	addRoutes(http.DefaultServeMux, s)
+	if prom != nil {
+		http.Handle("/metrics", prom)
+	}
+	http.HandleFunc("/webhook", s.Webhook())
 
-	http.HandleFunc("/", s.Root())
+	for _, b := range tp.Boards() {
+		if site.IsReservedBoardID(b.ID) {
+			klog.Exitf("board %q conflicts with a built-in path, please choose another id", b.ID)
+		}
+		klog.Infof("serving board %q under %s/%s/", b.Name, bp, b.ID)
+		mux := http.NewServeMux()
+		addRoutes(mux, s.Board(b))
+		http.Handle("/"+b.ID+"/", http.StripPrefix("/"+b.ID, mux))
+		http.Handle("/"+b.ID, http.RedirectHandler(bp+"/"+b.ID+"/", http.StatusMovedPermanently))
+	}
 
 	listenAddr := fmt.Sprintf(":%s", os.Getenv("PORT"))
 	if listenAddr == ":" {
@@ -209,10 +474,75 @@ func main() {
 	}
 
 	fmt.Printf("\n\n*** teaparty is listening at %s ... ***\n\n", listenAddr)
-	err = http.ListenAndServe(listenAddr, nil)
-	if err != nil {
+	var handler http.Handler = http.DefaultServeMux
+	if *authFile != "" || *authTokenFile != "" || os.Getenv("AUTH_TOKENS") != "" {
+		users := map[string]string{}
+		if *authFile != "" {
+			users, err = site.ReadAuthFile(*authFile)
+			if err != nil {
+				klog.Exitf("auth file: %v", err)
+			}
+		}
+
+		tokens := []string{}
+		if *authTokenFile != "" || os.Getenv("AUTH_TOKENS") != "" {
+			tokens = provider.ReadTokens(*authTokenFile, "AUTH_TOKENS")
+		}
+		// Scripts which call /refresh already hold this secret
+		if refreshToken != "" {
+			tokens = append(tokens, refreshToken)
+		}
+
+		a, err := site.NewAuthenticator(users, tokens, strings.Split(*authExempt, ","))
+		if err != nil {
+			klog.Exitf("auth: %v", err)
+		}
+		klog.Infof("requiring authentication for everything except %s (%d users, %d tokens)", *authExempt, len(users), len(tokens))
+		handler = a.Handler(handler)
+	}
+	if *rateLimit > 0 {
+		rl, err := site.NewRateLimiter(*rateLimit, *rateLimitBurst, strings.Split(*trustedProxies, ","))
+		if err != nil {
+			klog.Exitf("rate limit: %v", err)
+		}
+		klog.Infof("limiting each client to %v requests per second (burst %d)", *rateLimit, *rateLimitBurst)
+		handler = rl.Handler(handler)
+	}
+	if *allowCIDRs != "" {
+		al, err := site.NewAllowlist(strings.Split(*allowCIDRs, ","), strings.Split(*trustedProxies, ","))
+		if err != nil {
+			klog.Exitf("allowlist: %v", err)
+		}
+		klog.Infof("only serving clients within %s", *allowCIDRs)
+		handler = al.Handler(handler)
+	}
+	if *compress {
+		handler = site.Compress(handler)
+	}
+	if *accessLog {
+		handler = site.AccessLog(handler, *accessLogStatic)
+	}
+
+	if bp != "" {
+		klog.Infof("serving under %s/", bp)
+		mux := http.NewServeMux()
+		mux.Handle(bp+"/", http.StripPrefix(bp, handler))
+		mux.Handle(bp, http.RedirectHandler(bp+"/", http.StatusMovedPermanently))
+		// Health checks are often configured against the root, bypassing any ingress
+		mux.HandleFunc("/healthz", s.Healthz())
+		mux.HandleFunc("/readyz", s.Readyz())
+		handler = mux
+	}
+
+	srv.Addr = listenAddr
+	srv.Handler = handler
+	err = srv.ListenAndServe()
+	if err != http.ErrServerClosed {
 		panic(err)
 	}
+
+	<-stopped
+	klog.Infof("Exiting by signal as requested.")
 }
 
 // calculates a user-friendly site name based on repositories
@@ -232,6 +562,14 @@ func calculateSiteName(ts []triage.Rule) string {
 	return strings.Join(names, " + ")
 }
 
+// openConfig opens the configuration file, or stdin if the path is "-"
+func openConfig(path string) (io.Reader, error) {
+	if path == persist.StdinConfig {
+		return os.Stdin, nil
+	}
+	return os.Open(findPath(path))
+}
+
 // findPath tries to find the right place for a file
 func findPath(p string) string {
 	// Running from triage-party/
@@ -259,3 +597,36 @@ func findPath(p string) string {
 
 	return p
 }
+
+// addRoutes registers the site handlers within a mux
+func addRoutes(mux *http.ServeMux, s *site.Handlers) {
+	mux.Handle("/third_party/", http.StripPrefix("/third_party/", http.FileServer(http.Dir(findPath(*thirdPartyDir)))))
+	mux.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.Dir(filepath.Join(findPath(*siteDir), "static")))))
+	mux.HandleFunc("/s/", s.Collection())
+	mux.HandleFunc("/k/", s.Kanban())
+	mux.HandleFunc("/healthz", s.Healthz())
+	mux.HandleFunc("/readyz", s.Readyz())
+	mux.HandleFunc("/threadz", s.Threadz())
+	mux.HandleFunc("/version", s.Version())
+	mux.HandleFunc("/config", s.Config())
+	mux.HandleFunc("/api/rules", s.Rules())
+	mux.HandleFunc("/api/snapshot", s.Snapshot())
+	mux.HandleFunc("/api/s/", s.CollectionJSON())
+	mux.HandleFunc("/stats", s.Stats())
+	mux.HandleFunc("/debug/issue", s.DebugIssue())
+	mux.HandleFunc("/sla", s.SLA())
+	mux.HandleFunc("/all", s.All())
+	mux.HandleFunc("/sla.json", s.SLA())
+	mux.HandleFunc("/login", s.Login())
+	mux.HandleFunc("/oauth/callback", s.OAuthCallback())
+	mux.HandleFunc("/action", s.Action())
+	mux.HandleFunc("/suggest-assignee", s.SuggestAssignee())
+	mux.HandleFunc("/refresh", s.Refresh())
+	mux.HandleFunc("/admin/cache/clear", s.ClearCache())
+
+	// In case the previous handlers are removed by errant security systems
+	mux.HandleFunc("/health", s.Healthz())
+	mux.HandleFunc("/threads", s.Threadz())
+
+	mux.HandleFunc("/", s.Root())
+}
@@ -0,0 +1,100 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/triage-party/pkg/site"
+	"github.com/google/triage-party/pkg/triage"
+	"github.com/google/triage-party/pkg/updater"
+	"k8s.io/klog/v2"
+)
+
+// configReloader replaces the running configuration whenever the config file changes
+type configReloader struct {
+	path    string
+	party   *triage.Party
+	updater *updater.Updater
+	site    *site.Handlers
+
+	modTime time.Time
+	size    int64
+}
+
+func newConfigReloader(path string, tp *triage.Party, u *updater.Updater, s *site.Handlers) *configReloader {
+	cr := &configReloader{path: path, party: tp, updater: u, site: s}
+	if fi, err := os.Stat(path); err == nil {
+		cr.modTime = fi.ModTime()
+		cr.size = fi.Size()
+	}
+	return cr
+}
+
+// changed returns whether the config file was modified since it was last loaded
+func (cr *configReloader) changed() bool {
+	fi, err := os.Stat(cr.path)
+	if err != nil {
+		klog.Warningf("stat %s: %v", cr.path, err)
+		return false
+	}
+	if fi.ModTime().Equal(cr.modTime) && fi.Size() == cr.size {
+		return false
+	}
+	cr.modTime = fi.ModTime()
+	cr.size = fi.Size()
+	return true
+}
+
+// reload replaces the configuration, keeping the previous one if the file is invalid
+func (cr *configReloader) reload() error {
+	f, err := os.Open(cr.path)
+	if err != nil {
+		return fmt.Errorf("open: %w", err)
+	}
+	defer f.Close()
+
+	if err := cr.updater.Reload(func() error { return cr.party.Reload(f) }); err != nil {
+		return err
+	}
+
+	if *siteName == "" {
+		sn := cr.party.Name()
+		if sn == "" {
+			ts, err := cr.party.ListRules()
+			if err != nil {
+				return fmt.Errorf("list rules: %w", err)
+			}
+			sn = calculateSiteName(ts)
+		}
+		cr.site.SetName(sn)
+	}
+	return nil
+}
+
+// watch reloads the configuration each time the config file changes
+func (cr *configReloader) watch(interval time.Duration) {
+	for range time.Tick(interval) {
+		if !cr.changed() {
+			continue
+		}
+		klog.Infof("%s changed, reloading config", cr.path)
+		if err := cr.reload(); err != nil {
+			klog.Errorf("reload %s failed, keeping the previous config: %v", cr.path, err)
+		}
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
@@ -37,17 +38,20 @@ var (
 	gitHubAPIURL = flag.String("github-api-url", "", "base URL for GitHub API.  Please set this when you use GitHub Enterprise. This often is your GitHub Enterprise hostname. If the base URL does not have the suffix \"/api/v3/\", it will be added automatically.")
 
 	// shared with server
-	configPath      = flag.String("config", "", "configuration path")
-	persistBackend  = flag.String("persist-backend", "", "Cache persistence backend (disk, mysql, cloudsql)")
+	configPath      = flag.String("config", "", "configuration path, or - for stdin")
+	persistBackend  = flag.String("persist-backend", "", "Cache persistence backend (disk, memory, mysql, cloudsql, postgres, redis)")
 	persistPath     = flag.String("persist-path", "", "Where to persist cache to (automatic)")
+	persistNaming   = flag.String("persist-naming", persist.NamingConfig, "How automatic disk cache files are named: config, path (adds a hash of the config path), or content (adds a hash of the config)")
 	reposOverride   = flag.String("repos", "", "Override configured repos with this repository (comma separated)")
+	reposFile       = flag.String("repos-file", "", "Override configured repos with those listed in this file, one per line")
 	gitHubTokenFile = flag.String("github-token-file", "", "github token secret file, also settable via "+constants.GitHubTokenEnvVar)
-	gitLabTokenFile = flag.String("gitlab-token-file", "", "github token secret file, also settable via "+constants.GitLabTokenEnvVar)
+	gitLabTokenFile = flag.String("gitlab-token-file", "", "gitlab token secret file, also settable via "+constants.GitLabTokenEnvVar)
 	numbers         = flag.String("nums", "", "only display results for these comma-delimited issue/PR numbers (debug)")
 
 	// tester specific
 	collection = flag.String("collection", "", "collection")
 	rule       = flag.String("rule", "", "rule")
+	checkRules = flag.Bool("check-rules", false, "execute every collection, and report rules which match no items")
 )
 
 func main() {
@@ -58,17 +62,22 @@ func main() {
 		klog.Exitf("--config is required")
 	}
 
-	if *collection == "" && *rule == "" {
-		klog.Exitf("--collection or --rule is required")
+	if *collection == "" && *rule == "" && !*checkRules {
+		klog.Exitf("--collection, --rule, or --check-rules is required")
 	}
 
 	ctx := context.Background()
 
-	f, err := os.Open(*configPath)
-	if err != nil {
-		klog.Exitf("open %s: %v", *configPath, err)
+	var f io.Reader = os.Stdin
+	if *configPath != persist.StdinConfig {
+		cf, err := os.Open(*configPath)
+		if err != nil {
+			klog.Exitf("open %s: %v", *configPath, err)
+		}
+		f = cf
 	}
 
+	persist.DiskNaming = *persistNaming
 	c, err := persist.FromEnv(*persistBackend, *persistPath, *configPath, *reposOverride)
 	if err != nil {
 		klog.Exitf("unable to create persistence layer: %v", err)
@@ -90,8 +99,9 @@ func main() {
 		Cache:        c,
 		DebugNumbers: debugNums,
 		GitHubAPIURL: *gitHubAPIURL,
-		GitHubToken:  provider.ReadToken(*gitHubTokenFile, "GITHUB_TOKEN"),
+		GitHubTokens: provider.ReadTokens(*gitHubTokenFile, "GITHUB_TOKEN"),
 		GitLabToken:  provider.ReadToken(*gitLabTokenFile, "GITLAB_TOKEN"),
+		ReposFile:    *reposFile,
 	}
 
 	if *reposOverride != "" {
@@ -108,13 +118,38 @@ func main() {
 		klog.Exitf("load %s: %v", *configPath, err)
 	}
 
-	if *collection != "" {
+	switch {
+	case *checkRules:
+		reportEmptyRules(ctx, tp)
+	case *collection != "":
 		executeCollection(ctx, tp)
-	} else {
+	default:
 		executeRule(ctx, tp)
 	}
 }
 
+func reportEmptyRules(ctx context.Context, tp *triage.Party) {
+	sts, err := tp.ListCollections()
+	if err != nil {
+		klog.Exitf("collections: %v", err)
+	}
+
+	crs := []*triage.CollectionResult{}
+	for _, s := range sts {
+		r, err := tp.ExecuteCollection(ctx, s, time.Now())
+		if err != nil {
+			klog.Errorf("collection %q: %v", s.ID, err)
+		}
+		crs = append(crs, r)
+	}
+
+	ids := triage.EmptyRules(crs)
+	fmt.Printf("// %d rules matched no items\n", len(ids))
+	for _, id := range ids {
+		fmt.Println(tp.DescribeEmptyRule(ctx, id))
+	}
+}
+
 func executeCollection(ctx context.Context, tp *triage.Party) {
 	s, err := tp.LookupCollection(*collection)
 	if err != nil {
@@ -0,0 +1,120 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package events publishes triage deltas, such as items entering or leaving a collection, to any configured sinks.
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// Event types shared by all sinks
+const (
+	// ItemEntered is sent when an item becomes part of a collection
+	ItemEntered = "item_entered"
+	// ItemLeft is sent when an item is no longer part of a collection
+	ItemLeft = "item_left"
+	// SLABreached is sent when an item within a collection breaches its SLA
+	SLABreached = "sla_breached"
+	// SLAStatus is sent with the number of items breaching the SLA of a collection, whenever it changes
+	SLAStatus = "sla_status"
+)
+
+// publishTimeout bounds how long a sink may take to publish a batch
+const publishTimeout = 30 * time.Second
+
+// queueSize is how many batches may await publishing before new batches are dropped
+const queueSize = 64
+
+// Event is a single triage delta
+type Event struct {
+	Type       string    `json:"type"`
+	Time       time.Time `json:"time"`
+	Collection string    `json:"collection"`
+
+	URL          string `json:"url"`
+	Organization string `json:"org"`
+	Project      string `json:"project"`
+	ID           int    `json:"id"`
+	Title        string `json:"title"`
+
+	// Breached is the number of items breaching the collection SLA, for SLAStatus events
+	Breached int `json:"breached,omitempty"`
+}
+
+// Sink receives batches of events
+type Sink interface {
+	Publish(ctx context.Context, evs []Event) error
+}
+
+var (
+	sinksMu sync.RWMutex
+	sinks   []Sink
+
+	queue     = make(chan []Event, queueSize)
+	startOnce sync.Once
+)
+
+// AddSink registers a sink to receive all future events
+func AddSink(s Sink) {
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+	sinks = append(sinks, s)
+}
+
+// Enabled returns whether any sink is registered, so that callers may skip computing events
+func Enabled() bool {
+	sinksMu.RLock()
+	defer sinksMu.RUnlock()
+	return len(sinks) > 0
+}
+
+// Emit queues events to be published to every sink in the background. If the queue is full,
+// the batch is dropped and logged, as a slow sink should never hold up triage.
+func Emit(evs []Event) {
+	if len(evs) == 0 {
+		return
+	}
+
+	startOnce.Do(func() { go worker() })
+	select {
+	case queue <- evs:
+	default:
+		klog.Warningf("event queue is full, dropping %d events", len(evs))
+	}
+}
+
+// worker publishes queued batches, one at a time
+func worker() {
+	for evs := range queue {
+		publish(context.Background(), evs)
+	}
+}
+
+// publish sends events to every sink. Failures are logged, as a sink should never affect triage.
+func publish(ctx context.Context, evs []Event) {
+	sinksMu.RLock()
+	defer sinksMu.RUnlock()
+	for _, s := range sinks {
+		pctx, cancel := context.WithTimeout(ctx, publishTimeout)
+		if err := s.Publish(pctx, evs); err != nil {
+			klog.Errorf("publish %d events: %v", len(evs), err)
+		}
+		cancel()
+	}
+}
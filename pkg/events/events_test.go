@@ -0,0 +1,55 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type blockingSink struct {
+	release chan struct{}
+}
+
+func (b *blockingSink) Publish(ctx context.Context, evs []Event) error {
+	<-b.release
+	return nil
+}
+
+func TestEmitDropsWhenFull(t *testing.T) {
+	b := &blockingSink{release: make(chan struct{})}
+	AddSink(b)
+	defer func() {
+		close(b.release)
+		sinksMu.Lock()
+		sinks = nil
+		sinksMu.Unlock()
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < queueSize*2; i++ {
+			Emit([]Event{{Type: ItemEntered}})
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Emit blocked on a stuck sink")
+	}
+}
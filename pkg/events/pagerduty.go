@@ -0,0 +1,180 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	pagerDutyEndpoint = "https://events.pagerduty.com/v2/enqueue"
+
+	// pagerDutyDedupPrefix prefixes the collection ID to form the dedup key of its incident
+	pagerDutyDedupPrefix = "triage-party-sla-"
+)
+
+// PagerDutySeverities are the severities accepted by the PagerDuty Events API
+var PagerDutySeverities = []string{"critical", "error", "warning", "info"}
+
+// PagerDuty triggers a PagerDuty incident for each collection with too many items breaching its SLA,
+// and resolves it once the breaches clear, using the Events API v2.
+type PagerDuty struct {
+	client      *http.Client
+	endpoint    string
+	routingKey  string
+	collections map[string]bool
+	minBreached int
+	severity    string
+
+	// pending are events which failed to send, by dedup key, retried with the next batch
+	mu      sync.Mutex
+	pending map[string]pagerDutyEvent
+}
+
+// NewPagerDuty returns a PagerDuty sink which pages once at least minBreached items in a collection breach its SLA.
+// If collections is empty, every collection with an SLA is watched.
+func NewPagerDuty(routingKey string, collections []string, minBreached int, severity string) (*PagerDuty, error) {
+	if routingKey == "" {
+		return nil, fmt.Errorf("routing key is empty")
+	}
+	if minBreached < 1 {
+		return nil, fmt.Errorf("minimum breached items must be at least 1, got %d", minBreached)
+	}
+
+	known := false
+	for _, s := range PagerDutySeverities {
+		if s == severity {
+			known = true
+		}
+	}
+	if !known {
+		return nil, fmt.Errorf("unknown severity %q, expected one of %v", severity, PagerDutySeverities)
+	}
+
+	p := &PagerDuty{
+		client:      http.DefaultClient,
+		endpoint:    pagerDutyEndpoint,
+		routingKey:  routingKey,
+		collections: map[string]bool{},
+		minBreached: minBreached,
+		severity:    severity,
+		pending:     map[string]pagerDutyEvent{},
+	}
+	for _, c := range collections {
+		if c = strings.TrimSpace(c); c != "" {
+			p.collections[c] = true
+		}
+	}
+	return p, nil
+}
+
+type pagerDutyPayload struct {
+	Summary       string                 `json:"summary"`
+	Source        string                 `json:"source"`
+	Severity      string                 `json:"severity"`
+	CustomDetails map[string]interface{} `json:"custom_details,omitempty"`
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+}
+
+// Publish triggers or resolves the incident of each watched collection whose SLA status changed.
+// Events which fail to send are retried with the next batch, unless superseded.
+func (p *PagerDuty) Publish(ctx context.Context, evs []Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, e := range evs {
+		if e.Type != SLAStatus || (len(p.collections) > 0 && !p.collections[e.Collection]) {
+			continue
+		}
+
+		pe := pagerDutyEvent{
+			RoutingKey:  p.routingKey,
+			EventAction: "resolve",
+			DedupKey:    pagerDutyDedupPrefix + e.Collection,
+		}
+
+		if e.Breached >= p.minBreached {
+			pe.EventAction = "trigger"
+			pe.Payload = &pagerDutyPayload{
+				Summary:  fmt.Sprintf("%d items in collection %q have breached their SLA", e.Breached, e.Collection),
+				Source:   "triage-party",
+				Severity: p.severity,
+				CustomDetails: map[string]interface{}{
+					"collection": e.Collection,
+					"breached":   e.Breached,
+				},
+			}
+		}
+
+		p.pending[pe.DedupKey] = pe
+	}
+
+	keys := []string{}
+	for k := range p.pending {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var firstErr error
+	for _, k := range keys {
+		if err := p.send(ctx, p.pending[k]); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		delete(p.pending, k)
+	}
+	return firstErr
+}
+
+func (p *PagerDuty) send(ctx context.Context, pe pagerDutyEvent) error {
+	body, err := json.Marshal(pe)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+
+	hr, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	hr.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(hr)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", pe.EventAction, pe.DedupKey, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("%s %s: %s: %s", pe.EventAction, pe.DedupKey, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
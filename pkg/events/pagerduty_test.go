@@ -0,0 +1,69 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPagerDutyPublish(t *testing.T) {
+	var got []pagerDutyEvent
+	fail := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		var pe pagerDutyEvent
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&pe))
+		got = append(got, pe)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	p, err := NewPagerDuty("key", []string{"security"}, 2, "critical")
+	assert.Nil(t, err)
+	p.client = srv.Client()
+	p.endpoint = srv.URL
+
+	ctx := context.Background()
+	assert.Nil(t, p.Publish(ctx, []Event{
+		{Type: SLAStatus, Collection: "security", Breached: 3},
+		{Type: SLAStatus, Collection: "daily", Breached: 9},
+		{Type: SLABreached, Collection: "security", ID: 1},
+	}))
+	assert.Equal(t, 1, len(got))
+	assert.Equal(t, "trigger", got[0].EventAction)
+	assert.Equal(t, "triage-party-sla-security", got[0].DedupKey)
+	assert.Equal(t, "critical", got[0].Payload.Severity)
+
+	// Failed resolutions are retried with the next batch
+	fail = true
+	assert.Error(t, p.Publish(ctx, []Event{{Type: SLAStatus, Collection: "security", Breached: 1}}))
+	fail = false
+	assert.Nil(t, p.Publish(ctx, []Event{{Type: ItemEntered, Collection: "security", ID: 2}}))
+	assert.Equal(t, 2, len(got))
+	assert.Equal(t, "resolve", got[1].EventAction)
+	assert.Nil(t, got[1].Payload)
+
+	_, err = NewPagerDuty("key", nil, 1, "urgent")
+	assert.Error(t, err)
+}
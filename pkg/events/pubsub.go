@@ -0,0 +1,134 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	pubsubEndpoint = "https://pubsub.googleapis.com"
+	pubsubScope    = "https://www.googleapis.com/auth/pubsub"
+
+	// pubsubBatch is how many messages are sent per publish request, well under the Pub/Sub limit of 1000
+	pubsubBatch = 500
+)
+
+// PubSub publishes events to a Google Cloud Pub/Sub topic as JSON messages, using the REST API
+type PubSub struct {
+	client   *http.Client
+	endpoint string
+	topic    string
+}
+
+// NewPubSub returns a Pub/Sub sink for a topic, in the form projects/<project>/topics/<topic>.
+// If credsFile is empty, application default credentials are used.
+func NewPubSub(ctx context.Context, topic string, credsFile string) (*PubSub, error) {
+	parts := strings.Split(topic, "/")
+	if len(parts) != 4 || parts[0] != "projects" || parts[2] != "topics" || parts[1] == "" || parts[3] == "" {
+		return nil, fmt.Errorf("invalid topic %q, expected projects/<project>/topics/<topic>", topic)
+	}
+
+	var client *http.Client
+	if credsFile != "" {
+		bs, err := ioutil.ReadFile(credsFile)
+		if err != nil {
+			return nil, fmt.Errorf("read credentials: %w", err)
+		}
+		creds, err := google.CredentialsFromJSON(ctx, bs, pubsubScope)
+		if err != nil {
+			return nil, fmt.Errorf("credentials: %w", err)
+		}
+		client = oauth2.NewClient(ctx, creds.TokenSource)
+	} else {
+		var err error
+		client, err = google.DefaultClient(ctx, pubsubScope)
+		if err != nil {
+			return nil, fmt.Errorf("default credentials: %w", err)
+		}
+	}
+
+	return &PubSub{client: client, endpoint: pubsubEndpoint, topic: topic}, nil
+}
+
+type pubsubMessage struct {
+	Data       string            `json:"data"`
+	Attributes map[string]string `json:"attributes"`
+}
+
+type pubsubRequest struct {
+	Messages []pubsubMessage `json:"messages"`
+}
+
+// Publish sends events in batches, with the event type and collection as message attributes for subscription filters
+func (p *PubSub) Publish(ctx context.Context, evs []Event) error {
+	for start := 0; start < len(evs); start += pubsubBatch {
+		end := start + pubsubBatch
+		if end > len(evs) {
+			end = len(evs)
+		}
+		if err := p.publish(ctx, evs[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *PubSub) publish(ctx context.Context, evs []Event) error {
+	req := pubsubRequest{}
+	for _, e := range evs {
+		bs, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("marshal: %w", err)
+		}
+		req.Messages = append(req.Messages, pubsubMessage{
+			Data:       base64.StdEncoding.EncodeToString(bs),
+			Attributes: map[string]string{"type": e.Type, "collection": e.Collection},
+		})
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+
+	hr, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/v1/%s:publish", p.endpoint, p.topic), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	hr.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(hr)
+	if err != nil {
+		return fmt.Errorf("publish to %s: %w", p.topic, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("publish to %s: %s: %s", p.topic, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPubSubPublish(t *testing.T) {
+	var got pubsubRequest
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&got))
+		w.Write([]byte(`{"messageIds": ["1"]}`))
+	}))
+	defer srv.Close()
+
+	p := &PubSub{client: srv.Client(), endpoint: srv.URL, topic: "projects/p/topics/t"}
+	ev := Event{Type: ItemEntered, Collection: "daily", URL: "https://github.com/org/project/issues/1", ID: 1}
+	assert.Nil(t, p.Publish(context.Background(), []Event{ev}))
+
+	assert.Equal(t, "/v1/projects/p/topics/t:publish", path)
+	assert.Equal(t, 1, len(got.Messages))
+	assert.Equal(t, "daily", got.Messages[0].Attributes["collection"])
+
+	bs, err := base64.StdEncoding.DecodeString(got.Messages[0].Data)
+	assert.Nil(t, err)
+	var decoded Event
+	assert.Nil(t, json.Unmarshal(bs, &decoded))
+	assert.Equal(t, ev, decoded)
+
+	_, err = NewPubSub(context.Background(), "my-topic", "")
+	assert.Error(t, err)
+}
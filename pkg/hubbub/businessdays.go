@@ -0,0 +1,122 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubbub
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// businessDayRegexp matches business day durations, such as 3bd
+var businessDayRegexp = regexp.MustCompile(`^(\d+)bd$`)
+
+// holidayFormat is the date format for holidays
+const holidayFormat = "2006-01-02"
+
+// BusinessCalendar defines which days are not counted as business days
+type BusinessCalendar struct {
+	nonWorking map[time.Weekday]bool
+	holidays   map[string]bool
+}
+
+var (
+	calendarMu sync.RWMutex
+	calendar   = &BusinessCalendar{
+		nonWorking: map[time.Weekday]bool{time.Saturday: true, time.Sunday: true},
+		holidays:   map[string]bool{},
+	}
+)
+
+// NewBusinessCalendar returns a calendar from weekday names and YYYY-MM-DD holidays.
+// If nonWorking is empty, Saturday and Sunday are non-working days.
+func NewBusinessCalendar(nonWorking []string, holidays []string) (*BusinessCalendar, error) {
+	c := &BusinessCalendar{nonWorking: map[time.Weekday]bool{}, holidays: map[string]bool{}}
+	if len(nonWorking) == 0 {
+		nonWorking = []string{"saturday", "sunday"}
+	}
+
+	for _, n := range nonWorking {
+		found := false
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			if strings.EqualFold(d.String(), n) {
+				c.nonWorking[d] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown weekday %q", n)
+		}
+	}
+
+	if len(c.nonWorking) == 7 {
+		return nil, fmt.Errorf("every day is a non-working day")
+	}
+
+	for _, h := range holidays {
+		if _, err := time.Parse(holidayFormat, h); err != nil {
+			return nil, fmt.Errorf("holiday %q: expected YYYY-MM-DD", h)
+		}
+		c.holidays[h] = true
+	}
+	return c, nil
+}
+
+// SetBusinessCalendar sets the calendar used by business day durations
+func SetBusinessCalendar(c *BusinessCalendar) {
+	calendarMu.Lock()
+	defer calendarMu.Unlock()
+	calendar = c
+}
+
+func businessCalendar() *BusinessCalendar {
+	calendarMu.RLock()
+	defer calendarMu.RUnlock()
+	return calendar
+}
+
+// isBusinessDay returns whether the day containing t is a business day
+func (c *BusinessCalendar) isBusinessDay(t time.Time) bool {
+	return !c.nonWorking[t.Weekday()] && !c.holidays[t.Format(holidayFormat)]
+}
+
+// Before returns the time n business days before now. Non-working days are skipped, so
+// an item created on a Friday is 1 business day old on Monday at the same time of day.
+func (c *BusinessCalendar) Before(now time.Time, n int) time.Time {
+	t := now
+	for n > 0 {
+		t = t.AddDate(0, 0, -1)
+		if c.isBusinessDay(t) {
+			n--
+		}
+	}
+	return t
+}
+
+// businessDuration returns the duration covering n business days back from now, if ds is a business day duration
+func businessDuration(ds string, now time.Time) (time.Duration, bool) {
+	m := businessDayRegexp.FindStringSubmatch(ds)
+	if m == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, false
+	}
+	return now.Sub(businessCalendar().Before(now, n)), true
+}
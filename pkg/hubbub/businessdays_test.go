@@ -0,0 +1,68 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubbub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBusinessCalendarBefore(t *testing.T) {
+	c, err := NewBusinessCalendar(nil, []string{"2026-12-25"})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+
+	day := func(s string) time.Time {
+		d, err := time.Parse(time.RFC3339, s+"T17:00:00Z")
+		if err != nil {
+			t.Fatalf("parse: %v", err)
+		}
+		return d
+	}
+
+	// Monday back over the weekend to Friday
+	assert.Equal(t, day("2026-10-09"), c.Before(day("2026-10-12"), 1))
+	// Sunday is not a business day, so 2bd reaches back to Thursday
+	assert.Equal(t, day("2026-10-08"), c.Before(day("2026-10-11"), 2))
+	// Monday 2026-12-28 skips the weekend and the Friday holiday
+	assert.Equal(t, day("2026-12-24"), c.Before(day("2026-12-28"), 1))
+	assert.Equal(t, day("2026-10-14"), c.Before(day("2026-10-14"), 0))
+}
+
+func TestMatchBusinessDays(t *testing.T) {
+	friday, err := time.Parse(time.RFC3339, "2026-10-09T17:00:00Z")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	sunday := friday.Add(48 * time.Hour)
+	tuesday := friday.Add(97 * time.Hour)
+
+	// A Friday item does not breach a 2 business day response time by Sunday
+	assert.False(t, matchDuration(sunday, friday, "+2bd"))
+	assert.True(t, matchDuration(sunday, friday, "-2bd"))
+	assert.True(t, matchDuration(tuesday, friday, "+2bd"))
+}
+
+func TestNewBusinessCalendarErrors(t *testing.T) {
+	_, err := NewBusinessCalendar([]string{"caturday"}, nil)
+	assert.Error(t, err)
+	_, err = NewBusinessCalendar(nil, []string{"12/25/2026"})
+	assert.Error(t, err)
+	_, err = NewBusinessCalendar([]string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}, nil)
+	assert.Error(t, err)
+}
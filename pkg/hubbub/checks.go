@@ -0,0 +1,184 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubbub
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/triage-party/pkg/provider"
+	"k8s.io/klog/v2"
+)
+
+var (
+	// requiredChecksMaxAge is how long branch protection settings are cached for
+	requiredChecksMaxAge = time.Hour
+
+	// reportedChecksMaxAge is how long the checks reported for a commit are cached for
+	reportedChecksMaxAge = 10 * time.Minute
+)
+
+// Values for the mergeable filter
+const (
+	MergeableTrue    = "true"
+	MergeableFalse   = "false"
+	MergeableUnknown = "unknown"
+)
+
+// Mergeable states which require the head branch to be rebased (or merged with the base) before merging
+var rebaseStates = map[string]bool{
+	"dirty":  true,
+	"behind": true,
+}
+
+// NeedsRebase returns whether a PR has conflicts or is behind its base branch, or "unknown" if not yet computed
+func NeedsRebase(co *Conversation) string {
+	if co.MergeableState == "" || co.MergeableState == MergeableUnknown {
+		return MergeableUnknown
+	}
+	return strconv.FormatBool(rebaseStates[co.MergeableState])
+}
+
+// needMergeState returns true if the filters need the mergeable state of a PR
+func needMergeState(fs []provider.Filter) bool {
+	for _, f := range fs {
+		if f.Mergeable != "" || f.NeedsRebase != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// needChecks returns true if the filters need the required checks of a PR
+func needChecks(fs []provider.Filter) bool {
+	for _, f := range fs {
+		if f.MissingRequiredChecks != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// addMergeState adds the mergeable state and missing required checks of a PR to a conversation, if necessary
+func (h *Engine) addMergeState(ctx context.Context, sp provider.SearchParams, co *Conversation, pr *provider.PullRequest) {
+	if !needMergeState(sp.Filters) && !needChecks(sp.Filters) {
+		return
+	}
+
+	sp.IssueNumber = pr.GetNumber()
+	sp.NewerThan = h.mtime(pr)
+	sp.Fetch = true
+
+	detail, _, err := h.cachedPR(ctx, sp)
+	if err != nil {
+		klog.Errorf("pr #%d: %v", pr.GetNumber(), err)
+		return
+	}
+
+	if needMergeState(sp.Filters) {
+		co.Mergeable, co.MergeableState = h.mergeable(sp, detail)
+	}
+
+	if needChecks(sp.Filters) {
+		missing, err := h.missingRequiredChecks(ctx, sp, detail)
+		if err != nil {
+			klog.Errorf("required checks for #%d: %v", pr.GetNumber(), err)
+			return
+		}
+		co.MissingRequiredChecks = missing
+	}
+}
+
+// mergeable returns the mergeable and detailed merge state of a PR.
+// GitHub computes these in the background, so an unknown state is refetched on the next refresh rather than waited for.
+func (h *Engine) mergeable(sp provider.SearchParams, pr *provider.PullRequest) (string, string) {
+	if pr.Mergeable != nil {
+		return strconv.FormatBool(*pr.Mergeable), pr.GetMergeableState()
+	}
+
+	if pr.GetState() == "open" {
+		klog.V(1).Infof("mergeable state for #%d is not yet computed, will recheck on the next refresh", pr.GetNumber())
+		key := fmt.Sprintf("%s-%s-%d-pr", sp.Repo.Organization, sp.Repo.Project, pr.GetNumber())
+		if err := h.cache.DeleteOlderThan(key, h.now()); err != nil {
+			klog.Errorf("delete %q: %v", key, err)
+		}
+	}
+	return MergeableUnknown, MergeableUnknown
+}
+
+// missingRequiredChecks returns the required checks for a PR's base branch which have not been reported for its head
+func (h *Engine) missingRequiredChecks(ctx context.Context, sp provider.SearchParams, pr *provider.PullRequest) ([]string, error) {
+	base := pr.GetBase().GetRef()
+	head := pr.GetHead().GetSHA()
+	if base == "" || head == "" {
+		return nil, fmt.Errorf("base or head is unknown")
+	}
+
+	required, err := h.cachedChecks(fmt.Sprintf("%s-%s-%s-required-checks", sp.Repo.Organization, sp.Repo.Project, base), requiredChecksMaxAge, func() ([]string, *provider.Response, error) {
+		return h.provider(sp.Repo.Host).RepositoriesGetRequiredStatusChecks(ctx, sp, base)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("required: %w", err)
+	}
+
+	if len(required) == 0 {
+		return nil, nil
+	}
+
+	reported, err := h.cachedChecks(fmt.Sprintf("%s-%s-%s-reported-checks", sp.Repo.Organization, sp.Repo.Project, head), reportedChecksMaxAge, func() ([]string, *provider.Response, error) {
+		return h.provider(sp.Repo.Host).RepositoriesListReportedChecks(ctx, sp, head)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reported: %w", err)
+	}
+
+	missing := []string{}
+	for c := range required {
+		if !reported[c] {
+			missing = append(missing, c)
+		}
+	}
+	sort.Strings(missing)
+	return missing, nil
+}
+
+// cachedChecks returns a set of check names, fetching them if the cached copy is older than maxAge
+func (h *Engine) cachedChecks(key string, maxAge time.Duration, fetch func() ([]string, *provider.Response, error)) (map[string]bool, error) {
+	if x := h.cache.GetNewerThan(key, h.now().Add(-maxAge)); x != nil {
+		return x.StringBool, nil
+	}
+
+	klog.V(1).Infof("cache miss for %s", key)
+	names, resp, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	if resp != nil {
+		h.logRate(resp.Rate)
+	}
+
+	set := map[string]bool{}
+	for _, n := range names {
+		set[n] = true
+	}
+
+	if err := h.cache.Set(key, &provider.Thing{StringBool: set}); err != nil {
+		klog.Errorf("set %q failed: %v", key, err)
+	}
+	return set, nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubbub
+
+import (
+	"time"
+
+	"github.com/google/triage-party/pkg/provider"
+	"k8s.io/klog/v2"
+)
+
+// ClosedWindow returns how recently closed items must have been updated to match the filters, or 0 if unbounded.
+// Creating or closing an item updates it, so created, updated, and closed durations all bound the update time.
+func ClosedWindow(fs []provider.Filter) time.Duration {
+	window := time.Duration(0)
+	for _, f := range fs {
+		for _, fd := range []string{f.Created, f.Updated, f.Closed} {
+			if fd == "" {
+				continue
+			}
+
+			d, within, _ := ParseDuration(fd)
+			if !within || d == 0 {
+				continue
+			}
+
+			if window == 0 || d < window {
+				window = d
+			}
+		}
+	}
+	return window
+}
+
+// closedUpdateAge returns how far back to list closed items for a search. A narrower window than
+// MaxClosedUpdateAge is only used if the wider listing is not already cached, so that rules share it when possible.
+func (h *Engine) closedUpdateAge(sp provider.SearchParams, key func(provider.SearchParams) string) time.Duration {
+	if sp.ClosedWindow == 0 || sp.ClosedWindow >= h.MaxClosedUpdateAge {
+		return h.MaxClosedUpdateAge
+	}
+
+	sp.UpdateAge = h.MaxClosedUpdateAge
+	if h.cache.GetNewerThan(key(sp), sp.NewerThan) != nil {
+		return h.MaxClosedUpdateAge
+	}
+
+	klog.V(1).Infof("listing closed items for %s/%s updated within %s, rather than %s", sp.Repo.Organization, sp.Repo.Project, sp.ClosedWindow, h.MaxClosedUpdateAge)
+	return sp.ClosedWindow
+}
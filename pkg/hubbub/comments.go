@@ -0,0 +1,60 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubbub
+
+import (
+	"github.com/google/triage-party/pkg/provider"
+	"github.com/google/triage-party/pkg/tag"
+)
+
+// commentsPerPage is the page size used when listing comments
+const commentsPerPage = 100
+
+// NeedsAllComments returns whether the filters depend on every comment, rather than just the most recent
+func NeedsAllComments(fs []provider.Filter) bool {
+	for _, f := range fs {
+		if f.Responded != "" || f.FirstResponse != "" {
+			return true
+		}
+
+		if f.Commenters != "" || f.CommentersPerMonth != "" || f.ClosedComments != "" || f.ClosedCommenters != "" || f.Participants != "" {
+			return true
+		}
+
+		// Some tags are only added once every comment has been seen
+		if f.TagRegex() != nil {
+			if ok, t := matchTag(tag.Tags, f.TagRegex(), f.TagNegate()); ok && t.NeedsComments {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// commentLimit returns how many of the most recent comments to fetch for a search, or 0 for all of them
+func (h *Engine) commentLimit(sp provider.SearchParams) int {
+	if h.maxComments <= 0 || sp.CommentCount <= h.maxComments || NeedsAllComments(sp.Filters) {
+		return 0
+	}
+	return h.maxComments
+}
+
+// firstCommentPage returns the first page containing the most recent limit comments, or 0 to start at the beginning
+func firstCommentPage(count int, limit int) int {
+	if limit <= 0 || count <= limit {
+		return 0
+	}
+	return (count-limit)/commentsPerPage + 1
+}
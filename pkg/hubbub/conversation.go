@@ -52,14 +52,63 @@ type Conversation struct {
 	// When did this item reach the current priority?
 	Prioritized time.Time `json:"prioritized"`
 
+	// When did this item enter its current state (open or closed)?
+	StateChanged time.Time `json:"state_changed"`
+
+	// When was this item last reopened? Zero if it never was.
+	Reopened time.Time `json:"reopened"`
+
 	SelfInflicted bool `json:"self_inflicted"`
 
+	// Locked is true if the conversation has been locked, with an optional reason such as "resolved"
+	Locked     bool   `json:"locked,omitempty"`
+	LockReason string `json:"lock_reason,omitempty"`
+
 	ReviewState string `json:"review_state"`
 
+	// Mergeable is "true", "false", or "unknown" for PR's, if requested by a filter
+	Mergeable string `json:"mergeable,omitempty"`
+	// MergeableState is GitHub's detailed merge state for PR's, such as "dirty" or "behind", if requested by a filter
+	MergeableState string `json:"mergeable_state,omitempty"`
+	// MissingRequiredChecks are required checks which have not been reported for a PR, if requested by a filter
+	MissingRequiredChecks []string `json:"missing_required_checks,omitempty"`
+
+	// Additions, Deletions, and ChangedFiles are the size of a PR, if requested by a filter
+	Additions    int `json:"additions,omitempty"`
+	Deletions    int `json:"deletions,omitempty"`
+	ChangedFiles int `json:"changed_files,omitempty"`
+
+	// BaseRef is the branch a PR targets, and DefaultBranch the default branch of its repository, if known
+	BaseRef       string `json:"base_ref,omitempty"`
+	DefaultBranch string `json:"default_branch,omitempty"`
+
+	// ClosingRefs are the issues a PR closes via keywords such as "fixes #12", as org/project#12
+	ClosingRefs []string `json:"closing_refs,omitempty"`
+	// LinkedIssue is true if an issue has been manually linked to a PR, and not since unlinked
+	LinkedIssue bool `json:"linked_issue,omitempty"`
+
+	// DiscussionLinks are the GitHub Discussions linked from the description or comments
+	DiscussionLinks []string `json:"discussion_links,omitempty"`
+	// ConvertedToDiscussion is true if the timeline shows the issue was converted to a discussion
+	ConvertedToDiscussion bool `json:"converted_to_discussion,omitempty"`
+
 	LatestAuthorResponse   time.Time `json:"latest_author_response"`
 	LatestAssigneeResponse time.Time `json:"latest_assignee_response"`
 	LatestMemberResponse   time.Time `json:"latest_member_response"`
 
+	// LatestHumanActivity is the latest comment, review, or event by someone other than a bot, or creation if none
+	LatestHumanActivity time.Time `json:"latest_human_activity"`
+
+	// Awaiting is who is expected to respond next: "reporter", "maintainer", or unknown
+	Awaiting string `json:"awaiting"`
+
+	// FirstMemberResponse is when a member other than the author first commented
+	FirstMemberResponse time.Time `json:"first_member_response"`
+	// FirstResponseTime is how long the first member response took, or how long the item has waited for one so far
+	FirstResponseTime time.Duration `json:"first_response_time"`
+	// AwaitingFirstResponse is true for open items which no member has responded to yet
+	AwaitingFirstResponse bool `json:"awaiting_first_response"`
+
 	AccumulatedHoldTime time.Duration `json:"accumulated_hold_time"`
 	CurrentHoldTime     time.Duration `json:"current_hold_time"`
 
@@ -70,12 +119,16 @@ type Conversation struct {
 	Reactions         map[string]int `json:"reactions"`
 	ReactionsPerMonth float64        `json:"reactions_per_month"`
 
+	// Heat is a weighted score of recency, comments, reactions, and participants, if configured
+	Heat float64 `json:"heat,omitempty"`
+
 	Commenters         []*provider.User `json:"commenters"`
 	LastCommentBody    string           `json:"last_comment_body"`
 	LastCommentAuthor  *provider.User   `json:"last_comment_author"`
 	CommentsTotal      int              `json:"comments_total"`
 	CommentersTotal    int              `json:"commenters_total"`
 	CommentersPerMonth float64          `json:"commenters_per_month"`
+	ParticipantsTotal  int              `json:"participants_total"`
 
 	ClosedCommentsTotal   int            `json:"closed_comments_total"`
 	ClosedCommentersTotal int            `json:"closed_commenters_total"`
@@ -85,6 +138,12 @@ type Conversation struct {
 	TimelineTotal int `json:"timeline_total"`
 	ReviewsTotal  int `json:"reviews_total"`
 
+	// Reviewers are users who have submitted a review for a PR
+	Reviewers []*provider.User `json:"reviewers,omitempty"`
+	// RequestedReviewers and RequestedTeams (as org/team) have been asked to review a PR
+	RequestedReviewers []*provider.User `json:"requested_reviewers,omitempty"`
+	RequestedTeams     []string         `json:"requested_teams,omitempty"`
+
 	IssueRefs       []*RelatedConversation `json:"issue_refs"`
 	PullRequestRefs []*RelatedConversation `json:"pull_request_refs"`
 
@@ -131,3 +190,11 @@ func makeRelated(c *Conversation) *RelatedConversation {
 		Seen:    c.Seen,
 	}
 }
+
+// Opened returns when the item was last reopened, or when it was created if it never was
+func (co *Conversation) Opened() time.Time {
+	if co.Reopened.After(co.Created) {
+		return co.Reopened
+	}
+	return co.Created
+}
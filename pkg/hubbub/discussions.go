@@ -0,0 +1,46 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubbub
+
+import (
+	"regexp"
+)
+
+// discussionRe matches GitHub Discussions URLs, for repositories or organizations, such as https://github.com/org/project/discussions/12
+var discussionRe = regexp.MustCompile(`https?://[^/\s]+/(?:orgs/)?[\w.-]+(?:/[\w.-]+)?/discussions/\d+\b`)
+
+// appendDiscussionLinks appends the discussions text links to, ignoring code samples and duplicates
+func appendDiscussionLinks(links []string, text string) []string {
+	text = codeRe.ReplaceAllString(text, "<code></code>")
+
+	for _, u := range discussionRe.FindAllString(text, -1) {
+		found := false
+		for _, l := range links {
+			if l == u {
+				found = true
+				break
+			}
+		}
+		if !found {
+			links = append(links, u)
+		}
+	}
+	return links
+}
+
+// LinkedDiscussion returns whether an issue was converted to a discussion, or links to one
+func LinkedDiscussion(co *Conversation) bool {
+	return co.ConvertedToDiscussion || len(co.DiscussionLinks) > 0
+}
@@ -0,0 +1,57 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubbub
+
+import (
+	"time"
+
+	"github.com/google/triage-party/pkg/constants"
+)
+
+// setFirstResponse calculates the time to first member response, given whether all comments were seen
+func setFirstResponse(co *Conversation, allSeen bool, now time.Time) {
+	// Without all comments, an earlier response may have been missed
+	if !allSeen {
+		return
+	}
+
+	if !co.FirstMemberResponse.IsZero() {
+		co.FirstResponseTime = co.FirstMemberResponse.Sub(co.Created)
+		return
+	}
+
+	if co.State == constants.ClosedState {
+		return
+	}
+
+	co.AwaitingFirstResponse = true
+	co.FirstResponseTime = now.Sub(co.Created)
+}
+
+// matchFirstResponse returns whether the time to first response, including time waiting so far, matches a duration filter
+func matchFirstResponse(co *Conversation, ds string, now time.Time) bool {
+	if co.FirstResponseTime == 0 {
+		return false
+	}
+
+	d, within, over := parseDuration(ds, now)
+	if within && co.FirstResponseTime < d {
+		return true
+	}
+	if over && co.FirstResponseTime > d {
+		return true
+	}
+	return false
+}
@@ -16,12 +16,14 @@
 package hubbub
 
 import (
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/google/triage-party/pkg/constants"
 	"github.com/google/triage-party/pkg/persist"
 	"github.com/google/triage-party/pkg/provider"
+	"golang.org/x/time/rate"
 	"k8s.io/klog/v2"
 )
 
@@ -33,6 +35,24 @@ type Config struct {
 	// MinSimilarity is how close two items need to be to each other to be called similar
 	MinSimilarity float64
 
+	// SimilarityExcludeBots excludes items opened by bots from similarity matching
+	SimilarityExcludeBots bool
+
+	// SimilarityExcludeDrafts excludes draft PRs from similarity matching
+	SimilarityExcludeDrafts bool
+
+	// SimilaritySameRepo only considers items within the same repository to be similar
+	SimilaritySameRepo bool
+
+	// SimilarityWorkers is how many titles are compared in parallel (default: number of CPUs)
+	SimilarityWorkers int
+
+	// LabelAliases maps labels to interchangeable labels for filter matching, in both directions
+	LabelAliases map[string][]string
+
+	// CountedReactions are the reaction types which count towards reaction totals (default: all)
+	CountedReactions []string
+
 	// The furthest we will query back for information on closed issues
 	MaxClosedUpdateAge time.Duration
 
@@ -46,9 +66,24 @@ type Config struct {
 	// Members are which specific users to consider as members
 	Members []string
 
+	// Bots are usernames or username suffixes whose comments should be ignored
+	Bots []string
+
+	// MaxComments limits the comments fetched per item to the most recent, unless the filters need them all (0: no limit)
+	MaxComments int
+
+	// RepoListMaxAge is how long the repositories listed for owner/* are cached for (default: 1h)
+	RepoListMaxAge time.Duration
+
+	// Now returns the current time, and may be overridden for deterministic tests (default: time.Now)
+	Now func() time.Time
+
 	// Providers
 	GitHub provider.Provider
 	GitLab provider.Provider
+
+	// Hosts are providers for additional GitHub hosts, by hostname
+	Hosts map[string]provider.Provider
 }
 
 // Engine is the search engine interface for hubbub
@@ -58,6 +93,13 @@ type Engine struct {
 	// Must be settable from config
 	MinSimilarity float64
 
+	similarityExcludeBots   bool
+	similarityExcludeDrafts bool
+	countedReactions        map[string]bool
+	labelAliases            map[string][]string
+	similaritySameRepo      bool
+	similarityWorkers       int
+
 	// The furthest we will query back for information on closed issues
 	MaxClosedUpdateAge time.Duration
 
@@ -65,27 +107,100 @@ type Engine struct {
 
 	titleToURLs   sync.Map
 	similarTitles sync.Map
+	// similarMu serializes updates to the similarity tables
+	similarMu sync.Mutex
 
 	memberRoles map[string]bool
 	members     map[string]bool
+	bots        []string
+
+	maxComments int
+
+	// repoListMaxAge is how long the repositories of an owner are cached for
+	repoListMaxAge time.Duration
 
 	// Data source providers
 	github provider.Provider
 	gitlab provider.Provider
+	hosts  map[string]provider.Provider
+
+	// Throttles requests to the GitHub Search API
+	searchLimiter *rate.Limiter
+
+	// now returns the time used for age-based filters
+	now func() time.Time
 
 	// Workaround because GitHub doesn't update issues if cross-references occur
-	updatedAt map[string]time.Time
+	updatedAt   map[string]time.Time
+	updatedAtMu sync.RWMutex
+
+	// repoUpdated is when each repository was last known to change, such as by a webhook
+	repoUpdated   map[string]time.Time
+	repoUpdatedMu sync.RWMutex
 
 	// indexes used for similarity matching & conversation caching
-	seen map[string]*Conversation
+	seen   map[string]*Conversation
+	seenMu sync.RWMutex
 }
 
 // ConversationsTotal returns the number of conversations we've seen so far
 func (e *Engine) ConversationsTotal() int {
+	e.seenMu.RLock()
+	defer e.seenMu.RUnlock()
 	return len(e.seen)
 }
 
+// Lookup returns the conversation last seen for an item URL, or nil if it has not been seen
+func (e *Engine) Lookup(url string) *Conversation {
+	e.seenMu.RLock()
+	defer e.seenMu.RUnlock()
+	return e.seen[url]
+}
+
+// storeConversation records the latest conversation for an item URL
+func (e *Engine) storeConversation(url string, co *Conversation) *Conversation {
+	e.seenMu.Lock()
+	defer e.seenMu.Unlock()
+	e.seen[url] = co
+	return co
+}
+
+// Forget drops the conversations seen for a repository, or every conversation if org is empty
+func (e *Engine) Forget(org string, project string) int {
+	e.seenMu.Lock()
+	defer e.seenMu.Unlock()
+
+	n := 0
+	for url, co := range e.seen {
+		if org == "" || (strings.EqualFold(co.Organization, org) && strings.EqualFold(co.Project, project)) {
+			delete(e.seen, url)
+			n++
+		}
+	}
+	return n
+}
+
+// SeenRepos returns the repositories of every conversation seen
+func (e *Engine) SeenRepos() []provider.Repo {
+	e.seenMu.RLock()
+	defer e.seenMu.RUnlock()
+
+	seen := map[provider.Repo]bool{}
+	rs := []provider.Repo{}
+	for _, co := range e.seen {
+		r := provider.Repo{Organization: co.Organization, Project: co.Project}
+		if !seen[r] {
+			seen[r] = true
+			rs = append(rs, r)
+		}
+	}
+	return rs
+}
+
 func (e *Engine) provider(hostname string) provider.Provider {
+	if p, ok := e.hosts[hostname]; ok {
+		return p
+	}
 	if hostname == constants.GitLabProviderHost {
 		return e.gitlab
 	}
@@ -101,12 +216,35 @@ func New(cfg Config) *Engine {
 		MinSimilarity:      cfg.MinSimilarity,
 		debug:              cfg.DebugNumbers,
 
+		similarityExcludeBots:   cfg.SimilarityExcludeBots,
+		similarityExcludeDrafts: cfg.SimilarityExcludeDrafts,
+		similaritySameRepo:      cfg.SimilaritySameRepo,
+		similarityWorkers:       cfg.SimilarityWorkers,
+		labelAliases:            labelAliasMap(cfg.LabelAliases),
+
 		updatedAt:   map[string]time.Time{},
+		repoUpdated: map[string]time.Time{},
 		memberRoles: map[string]bool{},
 		members:     map[string]bool{},
+		bots:        cfg.Bots,
+		maxComments: cfg.MaxComments,
+
+		repoListMaxAge: cfg.RepoListMaxAge,
 
 		github: cfg.GitHub,
 		gitlab: cfg.GitLab,
+		hosts:  cfg.Hosts,
+
+		searchLimiter: rate.NewLimiter(searchRate, 1),
+		now:           cfg.Now,
+	}
+
+	if e.now == nil {
+		e.now = time.Now
+	}
+
+	if e.repoListMaxAge == 0 {
+		e.repoListMaxAge = defaultRepoListMaxAge
 	}
 
 	klog.Infof("considering users as members: %v", cfg.Members)
@@ -119,6 +257,14 @@ func New(cfg Config) *Engine {
 		e.memberRoles[role] = true
 	}
 
+	if len(cfg.CountedReactions) > 0 {
+		klog.Infof("counting reactions: %v", cfg.CountedReactions)
+		e.countedReactions = map[string]bool{}
+		for _, r := range cfg.CountedReactions {
+			e.countedReactions[r] = true
+		}
+	}
+
 	if len(e.members) == 0 && len(e.memberRoles) == 0 {
 		e.memberRoles = map[string]bool{"collaborator": true, "member": true, "owner": true}
 		klog.Warningf("No memberships defined, using default: %v", e.memberRoles)
@@ -131,3 +277,40 @@ func New(cfg Config) *Engine {
 
 	return e
 }
+
+// Inherit copies what a previous engine has learned about items, such as after the config is reloaded,
+// so that similarity matching and update tracking continue where they left off.
+func (e *Engine) Inherit(old *Engine) {
+	if old == nil {
+		return
+	}
+
+	old.seenMu.RLock()
+	for url, co := range old.seen {
+		e.seen[url] = co
+	}
+	old.seenMu.RUnlock()
+
+	old.repoUpdatedMu.RLock()
+	for k, t := range old.repoUpdated {
+		e.repoUpdated[k] = t
+	}
+	old.repoUpdatedMu.RUnlock()
+
+	old.updatedAtMu.RLock()
+	for k, t := range old.updatedAt {
+		e.updatedAt[k] = t
+	}
+	old.updatedAtMu.RUnlock()
+
+	old.similarMu.Lock()
+	defer old.similarMu.Unlock()
+	old.titleToURLs.Range(func(k, v interface{}) bool {
+		e.titleToURLs.Store(k, v)
+		return true
+	})
+	old.similarTitles.Range(func(k, v interface{}) bool {
+		e.similarTitles.Store(k, v)
+		return true
+	})
+}
@@ -0,0 +1,90 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubbub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/triage-party/pkg/persist"
+	"github.com/google/triage-party/pkg/provider"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForget(t *testing.T) {
+	e := &Engine{seen: map[string]*Conversation{}}
+	e.storeConversation("a", &Conversation{Organization: "org", Project: "project"})
+	e.storeConversation("b", &Conversation{Organization: "org", Project: "project-foo"})
+
+	assert.ElementsMatch(t, []provider.Repo{{Organization: "org", Project: "project"}, {Organization: "org", Project: "project-foo"}}, e.SeenRepos())
+	assert.Equal(t, 1, e.Forget("ORG", "Project"))
+	assert.Equal(t, 0, e.Forget("org", "project"))
+	assert.Equal(t, 1, e.Forget("", ""))
+}
+
+// numberProvider serves issues by number, and fails any listing
+type numberProvider struct {
+	provider.Provider
+	listed bool
+}
+
+func (n *numberProvider) IssuesListByRepo(context.Context, provider.SearchParams) ([]*provider.Issue, *provider.Response, error) {
+	n.listed = true
+	return nil, nil, provider.ErrOffline
+}
+
+func (n *numberProvider) IssuesGet(_ context.Context, sp provider.SearchParams) (*provider.Issue, *provider.Response, error) {
+	if sp.IssueNumber == 404 {
+		return nil, nil, errors.New("not found")
+	}
+	open := "open"
+	url := fmt.Sprintf("https://github.com/org/project/issues/%d", sp.IssueNumber)
+	num := sp.IssueNumber
+	now := time.Now()
+	return &provider.Issue{Number: &num, State: &open, HTMLURL: &url, CreatedAt: &now, UpdatedAt: &now}, &provider.Response{}, nil
+}
+
+func TestSearchIssuesByNumber(t *testing.T) {
+	c, err := persist.NewMemory(persist.Config{})
+	if err != nil {
+		t.Fatalf("memory: %v", err)
+	}
+	assert.Nil(t, c.Initialize())
+
+	p := &numberProvider{Provider: provider.Offline()}
+	e := New(Config{Cache: c, GitHub: p})
+
+	f := provider.Filter{RawNumber: "12,404,20-21"}
+	assert.Nil(t, f.LoadNumbers())
+	sp := provider.SearchParams{Repo: provider.Repo{Organization: "org", Project: "project", Host: "github.com"}, Filters: []provider.Filter{f}}
+
+	cos, _, err := e.SearchIssues(context.Background(), sp)
+	assert.Nil(t, err)
+	assert.False(t, p.listed)
+
+	ids := []int{}
+	for _, co := range cos {
+		ids = append(ids, co.ID)
+	}
+	assert.Equal(t, []int{12, 20, 21}, ids)
+
+	// Too many numbers to fetch one at a time
+	wide := provider.Filter{RawNumber: "1-1000"}
+	assert.Nil(t, wide.LoadNumbers())
+	assert.Nil(t, directNumbers([]provider.Filter{wide}))
+}
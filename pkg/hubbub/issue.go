@@ -32,6 +32,7 @@ import (
 // cachedIssues returns issues, cached if possible
 func (h *Engine) cachedIssues(ctx context.Context, sp provider.SearchParams) ([]*provider.Issue, time.Time, error) {
 	sp.SearchKey = issueSearchKey(sp)
+	sp.NewerThan = h.repoNewerThan(sp)
 
 	if x := h.cache.GetNewerThan(sp.SearchKey, sp.NewerThan); x != nil {
 		// Normally the similarity tables are only updated when fresh data is encountered.
@@ -128,13 +129,22 @@ func (h *Engine) cachedIssueComments(ctx context.Context, sp provider.SearchPara
 		return x.IssueComments, x.Created, nil
 	}
 
+	// Recent comments are cached separately, so that they are never mistaken for the full set
+	limit := h.commentLimit(sp)
+	if limit > 0 {
+		sp.SearchKey = fmt.Sprintf("%s-last-%d", sp.SearchKey, limit)
+		if x := h.cache.GetNewerThan(sp.SearchKey, sp.NewerThan); x != nil {
+			return x.IssueComments, x.Created, nil
+		}
+	}
+
 	if !sp.Fetch {
 		return nil, time.Time{}, nil
 	}
 
 	klog.V(1).Infof("cache miss for %s newer than %s", sp.SearchKey, logu.STime(sp.NewerThan))
 
-	comments, created, err := h.updateIssueComments(ctx, sp)
+	comments, created, err := h.updateIssueComments(ctx, sp, limit)
 	if err != nil {
 		klog.Warningf("Retrieving stale results for %s due to error: %v", sp.SearchKey, err)
 		x := h.cache.GetNewerThan(sp.SearchKey, time.Time{})
@@ -146,12 +156,13 @@ func (h *Engine) cachedIssueComments(ctx context.Context, sp provider.SearchPara
 	return comments, created, err
 }
 
-func (h *Engine) updateIssueComments(ctx context.Context, sp provider.SearchParams) ([]*provider.IssueComment, time.Time, error) {
+// updateIssueComments fetches comments for an issue, only the most recent if limit is set
+func (h *Engine) updateIssueComments(ctx context.Context, sp provider.SearchParams, limit int) ([]*provider.IssueComment, time.Time, error) {
 	klog.V(1).Infof("Downloading issue comments for %s/%s #%d", sp.Repo.Organization, sp.Repo.Project, sp.IssueNumber)
 	start := time.Now()
 
 	sp.IssueListCommentsOptions = provider.IssueListCommentsOptions{
-		ListOptions: provider.ListOptions{PerPage: 100},
+		ListOptions: provider.ListOptions{PerPage: commentsPerPage, Page: firstCommentPage(sp.CommentCount, limit)},
 	}
 
 	var allComments []*provider.IssueComment
@@ -173,6 +184,10 @@ func (h *Engine) updateIssueComments(ctx context.Context, sp provider.SearchPara
 		sp.IssueListCommentsOptions.Page = resp.NextPage
 	}
 
+	if limit > 0 && len(allComments) > limit {
+		allComments = allComments[len(allComments)-limit:]
+	}
+
 	if err := h.cache.Set(sp.SearchKey, &provider.Thing{IssueComments: allComments}); err != nil {
 		klog.Errorf("set %q failed: %v", sp.SearchKey, err)
 	}
@@ -215,7 +230,7 @@ func (h *Engine) createIssueSummary(i *provider.Issue, cs []*provider.IssueComme
 
 	co := h.createConversation(i, cl, age)
 	r := i.GetReactions()
-	co.ReactionsTotal += r.GetTotalCount()
+	co.ReactionsTotal += h.reactionsTotal(r)
 	for k, v := range reactions(r) {
 		co.Reactions[k] += v
 	}
@@ -227,11 +242,11 @@ func (h *Engine) createIssueSummary(i *provider.Issue, cs []*provider.IssueComme
 // IssueSummary returns a cached conversation for an issue
 func (h *Engine) IssueSummary(i *provider.Issue, cs []*provider.IssueComment, age time.Time) *Conversation {
 	key := i.GetHTMLURL()
-	cached, ok := h.seen[key]
-	if ok {
+	cached := h.Lookup(key)
+	if cached != nil {
 		minAge := h.mtime(i)
 		if !cached.Seen.Before(minAge) && cached.CommentsSeen >= len(cs) {
-			return h.seen[key]
+			return cached
 		}
 		if cached.CommentsSeen < len(cs) {
 			klog.V(2).Infof("%s in issue cache, but is missing comments. Live @ %s (%d comments), cached @ %s (%d comments)  ", i.GetHTMLURL(), minAge, len(cs), cached.Seen, cached.CommentsSeen)
@@ -240,12 +255,24 @@ func (h *Engine) IssueSummary(i *provider.Issue, cs []*provider.IssueComment, ag
 		}
 	}
 
-	h.seen[key] = h.createIssueSummary(i, cs, age)
-	return h.seen[key]
+	return h.storeConversation(key, h.createIssueSummary(i, cs, age))
 }
 
-func isBot(u *provider.User) bool {
-	if u.GetType() == "bot" {
+func (h *Engine) isBot(u *provider.User) bool {
+	return IsBot(u, h.bots)
+}
+
+// IsBot returns true if a user is a bot: a GitHub App, an account which looks like a bot,
+// or one matching a configured username suffix
+func IsBot(u *provider.User, bots []string) bool {
+	for _, b := range bots {
+		if hasSuffixFold(u.GetLogin(), b) {
+			klog.V(3).Infof("%s matches configured bot %q", u.GetLogin(), b)
+			return true
+		}
+	}
+
+	if strings.EqualFold(u.GetType(), "bot") {
 		klog.V(3).Infof("%s type=bot", u.GetLogin())
 		return true
 	}
@@ -255,9 +282,26 @@ func isBot(u *provider.User) bool {
 		return true
 	}
 
-	if strings.HasSuffix(u.GetLogin(), "-bot") || strings.HasSuffix(u.GetLogin(), "-robot") || strings.HasSuffix(u.GetLogin(), "_bot") || strings.HasSuffix(u.GetLogin(), "_robot") {
-		return true
+	for _, s := range []string{"-bot", "-robot", "_bot", "_robot"} {
+		if hasSuffixFold(u.GetLogin(), s) {
+			return true
+		}
 	}
 
 	return false
 }
+
+// hasSuffixFold is strings.HasSuffix, ignoring case as GitHub logins do
+func hasSuffixFold(s string, suffix string) bool {
+	return len(s) >= len(suffix) && strings.EqualFold(s[len(s)-len(suffix):], suffix)
+}
+
+// humanActivity advances the latest human activity of a conversation, ignoring bots
+func (h *Engine) humanActivity(co *Conversation, u *provider.User, t time.Time) {
+	if u == nil || h.isBot(u) {
+		return
+	}
+	if t.After(co.LatestHumanActivity) {
+		co.LatestHumanActivity = t
+	}
+}
@@ -66,6 +66,7 @@ func (h *Engine) createConversation(i provider.IItem, cs []*provider.Comment, ag
 		CommentsSeen:         len(cs),
 		ClosedAt:             i.GetClosedAt(),
 		SelfInflicted:        authorIsMember,
+		StateChanged:         i.GetCreatedAt(),
 		LatestAuthorResponse: i.GetCreatedAt(),
 		Milestone:            i.GetMilestone(),
 		Reactions:            map[string]int{},
@@ -78,11 +79,19 @@ func (h *Engine) createConversation(i provider.IItem, cs []*provider.Comment, ag
 		co.CommentsTotal = len(cs)
 	}
 
+	co.Locked = i.GetLocked()
+	co.LockReason = i.GetActiveLockReason()
+
+	if !co.ClosedAt.IsZero() && co.State == constants.ClosedState {
+		co.StateChanged = co.ClosedAt
+	}
+
 	// "https://github.com/kubernetes/minikube/issues/7179",
 	urlParts := strings.Split(i.GetHTMLURL(), "/")
 	co.Organization = urlParts[3]
 	co.Project = urlParts[4]
 	h.parseRefs(i.GetBody(), co, i.GetUpdatedAt())
+	co.DiscussionLinks = appendDiscussionLinks(co.DiscussionLinks, i.GetBody())
 
 	if i.GetAssignee() != nil {
 		co.Assignees = append(co.Assignees, i.GetAssignee())
@@ -93,7 +102,11 @@ func (h *Engine) createConversation(i provider.IItem, cs []*provider.Comment, ag
 		co.LatestMemberResponse = i.GetCreatedAt()
 	}
 
+	co.LatestHumanActivity = i.GetCreatedAt()
+
 	lastQuestion := time.Time{}
+	lastMemberComment := time.Time{}
+	lastReporterComment := i.GetCreatedAt()
 	seenCommenters := map[string]bool{}
 	seenClosedCommenters := map[string]bool{}
 	seenMemberComment := false
@@ -104,21 +117,23 @@ func (h *Engine) createConversation(i provider.IItem, cs []*provider.Comment, ag
 
 	for _, c := range cs {
 		h.parseRefs(c.Body, co, c.Updated)
+		co.DiscussionLinks = appendDiscussionLinks(co.DiscussionLinks, c.Body)
 		if h.debug[co.ID] {
 			klog.Errorf("debug conversation comment: %s", formatStruct(c))
 		}
 
 		// We don't like their kind around here
-		if isBot(c.User) {
+		if h.isBot(c.User) {
 			continue
 		}
 
 		co.LastCommentBody = c.Body
 		co.LastCommentAuthor = c.User
+		h.humanActivity(co, c.User, c.Updated)
 
 		r := c.Reactions
 		if r.GetTotalCount() > 0 {
-			co.ReactionsTotal += r.GetTotalCount()
+			co.ReactionsTotal += h.reactionsTotal(r)
 			for k, v := range reactions(r) {
 				co.Reactions[k] += v
 			}
@@ -132,13 +147,19 @@ func (h *Engine) createConversation(i provider.IItem, cs []*provider.Comment, ag
 
 		if c.User.GetLogin() == i.GetUser().GetLogin() {
 			co.LatestAuthorResponse = c.Created
+			lastReporterComment = c.Created
+		} else if h.isMember(c.User.GetLogin(), c.AuthorAssoc) {
+			lastMemberComment = c.Created
+			if co.FirstMemberResponse.IsZero() {
+				co.FirstMemberResponse = c.Created
+			}
 		}
 
 		if c.User.GetLogin() == i.GetAssignee().GetLogin() {
 			co.LatestAssigneeResponse = c.Created
 		}
 
-		if h.isMember(c.User.GetLogin(), c.AuthorAssoc) && !isBot(c.User) {
+		if h.isMember(c.User.GetLogin(), c.AuthorAssoc) {
 			if !co.LatestMemberResponse.After(co.LatestAuthorResponse) && !authorIsMember {
 				co.AccumulatedHoldTime += c.Created.Sub(co.LatestAuthorResponse)
 			}
@@ -176,30 +197,45 @@ func (h *Engine) createConversation(i provider.IItem, cs []*provider.Comment, ag
 	}
 
 	// Only add these tags if we've seen all the comments
-	if len(cs) >= co.CommentsTotal {
+	allSeen := len(cs) >= co.CommentsTotal
+	if allSeen {
 		if co.LatestMemberResponse.After(co.LatestAuthorResponse) {
 			co.Tags[tag.Send] = true
 			co.CurrentHoldTime = 0
 		} else if !authorIsMember {
 			co.Tags[tag.Recv] = true
-			co.CurrentHoldTime += time.Since(co.LatestAuthorResponse)
-			co.AccumulatedHoldTime += time.Since(co.LatestAuthorResponse)
+			co.CurrentHoldTime += h.now().Sub(co.LatestAuthorResponse)
+			co.AccumulatedHoldTime += h.now().Sub(co.LatestAuthorResponse)
 		}
 
 		if lastQuestion.After(co.LatestMemberResponse) {
 			co.Tags[tag.RecvQ] = true
 		}
+
+		// Members talking amongst themselves are not awaiting anyone
+		if !authorIsMember {
+			co.Awaiting = provider.AwaitingMaintainer
+			if lastMemberComment.After(lastReporterComment) {
+				co.Awaiting = provider.AwaitingReporter
+			}
+		}
+	}
+
+	if !authorIsMember {
+		setFirstResponse(co, allSeen, h.now())
 	}
 
 	if len(cs) > 0 {
 		last := cs[len(cs)-1]
-		assoc := strings.ToLower(last.AuthorAssoc)
-		if assoc == "none" {
-			if last.User.GetLogin() == i.GetUser().GetLogin() {
-				co.Tags[tag.AuthorLast] = true
+		if human := h.lastHumanComment(cs); human != nil {
+			assoc := strings.ToLower(human.AuthorAssoc)
+			if assoc == "none" {
+				if human.User.GetLogin() == i.GetUser().GetLogin() {
+					co.Tags[tag.AuthorLast] = true
+				}
+			} else {
+				co.Tags[tag.RoleLast(assoc)] = true
 			}
-		} else {
-			co.Tags[tag.RoleLast(assoc)] = true
 		}
 
 		if last.Updated.After(co.Updated) {
@@ -211,15 +247,23 @@ func (h *Engine) createConversation(i provider.IItem, cs []*provider.Comment, ag
 		co.Tags[tag.Closed] = true
 	}
 
-	co.CommentersTotal = len(seenCommenters)
-	co.ClosedCommentersTotal = len(seenClosedCommenters)
+	// Counts from only the most recent comments would be too low, so they are left unset
+	if allSeen {
+		co.CommentersTotal = len(seenCommenters)
+		co.ParticipantsTotal = co.CommentersTotal
+		if i.GetUser() != nil && !h.isBot(i.GetUser()) && !seenCommenters[i.GetUser().GetLogin()] {
+			co.ParticipantsTotal++
+		}
+		co.ClosedCommentersTotal = len(seenClosedCommenters)
+	}
 
-	if co.AccumulatedHoldTime > time.Since(co.Created) {
-		panic(fmt.Sprintf("accumulated %s is more than age %s", co.AccumulatedHoldTime, time.Since(co.Created)))
+	itemAge := h.now().Sub(co.Created)
+	if co.AccumulatedHoldTime > itemAge {
+		panic(fmt.Sprintf("accumulated %s is more than age %s", co.AccumulatedHoldTime, itemAge))
 	}
 
 	// Loose, but good enough
-	months := time.Since(co.Created).Hours() / 24 / 30
+	months := itemAge.Hours() / 24 / 30
 	co.CommentersPerMonth = float64(co.CommentersTotal) / months
 	co.ReactionsPerMonth = float64(co.ReactionsTotal) / months
 
@@ -234,6 +278,16 @@ func (h *Engine) createConversation(i provider.IItem, cs []*provider.Comment, ag
 	return co
 }
 
+// lastHumanComment returns the most recent comment not made by a bot
+func (h *Engine) lastHumanComment(cs []*provider.Comment) *provider.Comment {
+	for i := len(cs) - 1; i >= 0; i-- {
+		if !h.isBot(cs[i].User) {
+			return cs[i]
+		}
+	}
+	return nil
+}
+
 // Return if a user or role should be considered a member
 func (h *Engine) isMember(user string, role string) bool {
 	if h.members[user] {
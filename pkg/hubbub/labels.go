@@ -0,0 +1,65 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubbub
+
+import (
+	"strings"
+
+	"github.com/google/triage-party/pkg/provider"
+)
+
+// labelAliasMap returns a map of each lowercased label to the labels it is interchangeable with
+func labelAliasMap(aliases map[string][]string) map[string][]string {
+	groups := map[string]map[string]bool{}
+
+	for name, as := range aliases {
+		all := append([]string{name}, as...)
+		for _, a := range all {
+			k := strings.ToLower(a)
+			if groups[k] == nil {
+				groups[k] = map[string]bool{}
+			}
+			for _, b := range all {
+				if !strings.EqualFold(a, b) {
+					groups[k][b] = true
+				}
+			}
+		}
+	}
+
+	m := map[string][]string{}
+	for k, g := range groups {
+		for l := range g {
+			m[k] = append(m[k], l)
+		}
+	}
+	return m
+}
+
+// withAliases returns labels along with any configured aliases, for use when matching label filters
+func (h *Engine) withAliases(labels []*provider.Label) []*provider.Label {
+	if len(h.labelAliases) == 0 {
+		return labels
+	}
+
+	ls := labels
+	for _, l := range labels {
+		for _, a := range h.labelAliases[strings.ToLower(l.GetName())] {
+			name := a
+			ls = append(ls, &provider.Label{Name: &name})
+		}
+	}
+	return ls
+}
@@ -0,0 +1,36 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubbub
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/google/triage-party/pkg/provider"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithAliases(t *testing.T) {
+	h := &Engine{labelAliases: labelAliasMap(map[string][]string{"bug": {"type/bug"}})}
+	label := func(s string) []*provider.Label { return []*provider.Label{{Name: &s}} }
+
+	bug := regexp.MustCompile("^bug$")
+	typeBug := regexp.MustCompile("^type/bug$")
+
+	assert.True(t, matchLabel(h.withAliases(label("type/bug")), bug, false))
+	assert.True(t, matchLabel(h.withAliases(label("Bug")), typeBug, false))
+	assert.False(t, matchLabel(h.withAliases(label("feature")), bug, false))
+	assert.False(t, matchLabel(h.withAliases(label("type/bug")), bug, true))
+}
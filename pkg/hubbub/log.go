@@ -17,12 +17,17 @@ package hubbub
 import (
 	"fmt"
 
+	"github.com/google/triage-party/pkg/metrics"
 	"github.com/google/triage-party/pkg/provider"
 
 	"k8s.io/klog/v2"
 )
 
 func (h *Engine) logRate(r provider.Rate) {
+	metrics.Gauge(metrics.RateLimitRemaining, float64(r.Remaining))
+	if !r.Reset.IsZero() {
+		metrics.Gauge(metrics.RateLimitReset, float64(r.Reset.Unix()))
+	}
 	msg := fmt.Sprintf("GitHub API hourly quota remaining: %d of %d, resets at %s", r.Remaining, r.Limit, r.Reset)
 
 	if r.Remaining < 25 {
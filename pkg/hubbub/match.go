@@ -21,6 +21,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/google/triage-party/pkg/constants"
 	"github.com/google/triage-party/pkg/provider"
 
 	"github.com/google/triage-party/pkg/tag"
@@ -28,8 +29,12 @@ import (
 )
 
 // Check if an item matches the filters, pre-comment fetch
-func preFetchMatch(i provider.IItem, labels []*provider.Label, fs []provider.Filter) bool {
+func preFetchMatch(i provider.IItem, labels []*provider.Label, fs []provider.Filter, now time.Time) bool {
 	for _, f := range fs {
+		// Cheapest check first, avoiding any further work for unrelated items
+		if f.HasNumbers() && !f.MatchNumber(i.GetNumber()) {
+			return false
+		}
 
 		if f.State != "" && f.State != "all" {
 			if i.GetState() != f.State {
@@ -43,29 +48,39 @@ func preFetchMatch(i provider.IItem, labels []*provider.Label, fs []provider.Fil
 			}
 		}
 
+		if f.AuthorType != "" && authorType(i.GetUser()) != f.AuthorType {
+			klog.V(2).Infof("#%d did not pass author-type: %q vs %q", i.GetNumber(), i.GetUser().GetType(), f.AuthorType)
+			return false
+		}
+
+		if f.Locked != "" && strconv.FormatBool(i.GetLocked()) != f.Locked {
+			klog.V(2).Infof("#%d did not pass locked: %v vs %q", i.GetNumber(), i.GetLocked(), f.Locked)
+			return false
+		}
+
 		if f.Closed != "" {
-			if ok := matchDuration(i.GetClosedAt(), f.Closed); !ok {
+			if ok := matchDuration(now, i.GetClosedAt(), f.Closed); !ok {
 				klog.V(2).Infof("#%d closed at %s does not meet %s", i.GetNumber(), i.GetClosedAt(), f.Closed)
 				return false
 			}
 		}
 
 		if f.Updated != "" {
-			if ok := matchDuration(i.GetUpdatedAt(), f.Updated); !ok {
+			if ok := matchDuration(now, i.GetUpdatedAt(), f.Updated); !ok {
 				klog.V(2).Infof("#%d update at %s does not meet %s", i.GetNumber(), i.GetUpdatedAt(), f.Updated)
 				return false
 			}
 		}
 
 		if f.Responded != "" {
-			if ok := matchDuration(i.GetUpdatedAt(), f.Responded); !ok {
+			if ok := matchDuration(now, i.GetUpdatedAt(), f.Responded); !ok {
 				klog.V(2).Infof("#%d update at %s does not meet responded %s", i.GetNumber(), i.GetUpdatedAt(), f.Responded)
 				return false
 			}
 		}
 
 		if f.Created != "" {
-			if ok := matchDuration(i.GetCreatedAt(), f.Created); !ok {
+			if ok := matchDuration(now, i.GetCreatedAt(), f.Created); !ok {
 				klog.V(2).Infof("#%d Created at %s does not meet %s", i.GetNumber(), i.GetCreatedAt(), f.Created)
 				return false
 			}
@@ -78,6 +93,34 @@ func preFetchMatch(i provider.IItem, labels []*provider.Label, fs []provider.Fil
 			}
 		}
 
+		if f.BodyMatches() != nil && !f.BodyMatches().MatchString(i.GetBody()) {
+			klog.V(2).Infof("#%d body does not match %s", i.GetNumber(), f.BodyMatches())
+			return false
+		}
+
+		if f.BodyMissing() != nil && f.BodyMissing().MatchString(i.GetBody()) {
+			klog.V(2).Infof("#%d body matches %s", i.GetNumber(), f.BodyMissing())
+			return false
+		}
+
+		if f.SectionRegex() != nil && hasSection(i.GetBody(), f.SectionRegex()) == f.SectionNegate() {
+			klog.V(2).Infof("#%d section %s does not meet negate=%v", i.GetNumber(), f.SectionRegex(), f.SectionNegate())
+			return false
+		}
+
+		if f.References != "" {
+			org, project := urlRepo(i.GetHTMLURL())
+			if !mentions(i.GetBody(), org, project, f.References) {
+				klog.V(2).Infof("#%d body does not reference %s", i.GetNumber(), f.References)
+				return false
+			}
+		}
+
+		if (f.TasksComplete != "" || f.TasksRemaining != "") && !matchTasks(i.GetBody(), f) {
+			klog.V(2).Infof("#%d task list does not meet tasks-complete=%q tasks-remaining=%q", i.GetNumber(), f.TasksComplete, f.TasksRemaining)
+			return false
+		}
+
 		if f.LabelRegex() != nil {
 			if ok := matchLabel(labels, f.LabelRegex(), f.LabelNegate()); !ok {
 				klog.V(2).Infof("#%d labels do not meet %s", i.GetNumber(), f.LabelRegex())
@@ -85,6 +128,21 @@ func preFetchMatch(i provider.IItem, labels []*provider.Label, fs []provider.Fil
 			}
 		}
 
+		if f.LabelNone() && (len(labels) == 0) == f.LabelNegate() {
+			klog.V(2).Infof("#%d labels do not meet label: none (negate=%v)", i.GetNumber(), f.LabelNegate())
+			return false
+		}
+
+		if f.MilestoneNone() && (i.GetMilestone() == nil) == f.MilestoneNegate() {
+			klog.V(2).Infof("#%d milestone does not meet milestone: none (negate=%v)", i.GetNumber(), f.MilestoneNegate())
+			return false
+		}
+
+		if none, negate := f.AssigneeNone(); none && (i.GetAssignee() == nil) == negate {
+			klog.V(2).Infof("#%d assignee does not meet assignee: none (negate=%v)", i.GetNumber(), negate)
+			return false
+		}
+
 		if f.MilestoneRegex() != nil {
 			if ok := matchNegateRegex(i.GetMilestone().GetTitle(), f.MilestoneRegex(), f.MilestoneNegate()); !ok {
 				klog.V(2).Infof("#%d milestone does not meet %s", i.GetNumber(), f.MilestoneRegex())
@@ -92,6 +150,13 @@ func preFetchMatch(i provider.IItem, labels []*provider.Label, fs []provider.Fil
 			}
 		}
 
+		if f.MilestoneState != "" {
+			if ok := matchMilestoneState(i.GetMilestone(), f.MilestoneState); !ok {
+				klog.V(2).Infof("#%d milestone state does not meet %s", i.GetNumber(), f.MilestoneState)
+				return false
+			}
+		}
+
 		// This state can be performed without downloading comments
 		if f.TagRegex() != nil && f.TagRegex().String() == "^assigned$" {
 			// If assigned and no assignee, fail
@@ -116,16 +181,75 @@ func preFetchMatch(i provider.IItem, labels []*provider.Label, fs []provider.Fil
 }
 
 // Check if an issue matches the summarized version
-func postFetchMatch(co *Conversation, fs []provider.Filter) bool {
+func postFetchMatch(co *Conversation, fs []provider.Filter, now time.Time) bool {
 	for _, f := range fs {
 		klog.V(2).Infof("post-fetch matching item #%d against filter: %+v", co.ID, f)
 
 		if f.Responded != "" {
-			if ok := matchDuration(co.LatestMemberResponse, f.Responded); !ok {
+			if ok := matchDuration(now, co.LatestMemberResponse, f.Responded); !ok {
 				klog.V(4).Infof("#%d did not pass matchDuration: %s vs %s", co.ID, co.LatestMemberResponse, f.Responded)
 				return false
 			}
 		}
+		if f.FirstResponse != "" && !matchFirstResponse(co, f.FirstResponse, now) {
+			klog.V(2).Infof("#%d did not pass first-response: %s (awaiting=%v) vs %s", co.ID, co.FirstResponseTime, co.AwaitingFirstResponse, f.FirstResponse)
+			return false
+		}
+
+		if f.Review != "" && ReviewDecision(co) != f.Review {
+			klog.V(2).Infof("#%d did not pass review: %q (%s) vs %q", co.ID, ReviewDecision(co), co.ReviewState, f.Review)
+			return false
+		}
+
+		if f.Mergeable != "" && (co.Type != PullRequest || co.Mergeable != f.Mergeable) {
+			klog.V(2).Infof("#%d did not pass mergeable: %q vs %q", co.ID, co.Mergeable, f.Mergeable)
+			return false
+		}
+
+		if f.NeedsRebase != "" && (co.Type != PullRequest || NeedsRebase(co) != f.NeedsRebase) {
+			klog.V(2).Infof("#%d did not pass needs-rebase: %q (%s) vs %q", co.ID, NeedsRebase(co), co.MergeableState, f.NeedsRebase)
+			return false
+		}
+
+		if f.MissingRequiredChecks != "" {
+			missing := strconv.FormatBool(len(co.MissingRequiredChecks) > 0)
+			if co.Type != PullRequest || missing != f.MissingRequiredChecks {
+				klog.V(2).Infof("#%d did not pass missing-required-checks: %v vs %s", co.ID, co.MissingRequiredChecks, f.MissingRequiredChecks)
+				return false
+			}
+		}
+
+		if f.ClosesIssue != "" && (co.Type != PullRequest || strconv.FormatBool(ClosesIssue(co)) != f.ClosesIssue) {
+			klog.V(2).Infof("#%d did not pass closes-issue: %v (linked=%v) vs %s", co.ID, co.ClosingRefs, co.LinkedIssue, f.ClosesIssue)
+			return false
+		}
+
+		if f.RawBaseRef != "" && co.Type == PullRequest && !matchBaseRef(co, f) {
+			klog.V(2).Infof("#%d did not pass base-ref: %q (default %q) vs %s", co.ID, co.BaseRef, co.DefaultBranch, f.RawBaseRef)
+			return false
+		}
+
+		if f.Size != "" && (co.Type != PullRequest || !matchSize(co.Additions+co.Deletions, f.Size)) {
+			klog.V(2).Infof("#%d did not pass size: %d+%d vs %s", co.ID, co.Additions, co.Deletions, f.Size)
+			return false
+		}
+
+		if f.ChangedFiles != "" && (co.Type != PullRequest || !matchRange(float64(co.ChangedFiles), f.ChangedFiles)) {
+			klog.V(2).Infof("#%d did not pass changed-files: %d vs %s", co.ID, co.ChangedFiles, f.ChangedFiles)
+			return false
+		}
+
+		// @me depends on the viewer, so is applied when the results are displayed
+		if f.Involves != "" && f.Involves != provider.InvolvesViewer && !Involves(co, f.Involves) {
+			klog.V(2).Infof("#%d does not involve %q", co.ID, f.Involves)
+			return false
+		}
+
+		if f.Awaiting != "" && co.Awaiting != f.Awaiting {
+			klog.V(2).Infof("#%d did not pass awaiting: %q vs %q", co.ID, co.Awaiting, f.Awaiting)
+			return false
+		}
+
 		if f.Reactions != "" {
 			if ok := matchRange(float64(co.ReactionsTotal), f.Reactions); !ok {
 				klog.V(2).Infof("#%d did not pass reactions matchRange: %d vs %s", co.ID, co.ReactionsTotal, f.Reactions)
@@ -147,6 +271,13 @@ func postFetchMatch(co *Conversation, fs []provider.Filter) bool {
 			}
 		}
 
+		if f.Participants != "" {
+			if ok := matchRange(float64(co.ParticipantsTotal), f.Participants); !ok {
+				klog.V(2).Infof("#%d did not pass participants matchRange: %d vs %s", co.ID, co.ParticipantsTotal, f.Participants)
+				return false
+			}
+		}
+
 		if f.CommentersPerMonth != "" {
 			if ok := matchRange(co.CommentersPerMonth, f.CommentersPerMonth); !ok {
 				klog.V(2).Infof("#%d did not pass commenters per-month matchRange: %f vs %s", co.ID, co.CommentersPerMonth, f.CommentersPerMonth)
@@ -178,8 +309,14 @@ func postFetchMatch(co *Conversation, fs []provider.Filter) bool {
 }
 
 // Check if an issue matches the summarized version, after events have been loaded
-func postEventsMatch(co *Conversation, fs []provider.Filter) bool {
+func postEventsMatch(co *Conversation, fs []provider.Filter, now time.Time) bool {
 	for _, f := range fs {
+		// Reviews and timeline events count as human activity, so this waits until they are added
+		if f.HumanActivity != "" && !matchDuration(now, co.LatestHumanActivity, f.HumanActivity) {
+			klog.V(2).Infof("#%d did not pass human-activity: %s vs %s", co.ID, co.LatestHumanActivity, f.HumanActivity)
+			return false
+		}
+
 		if f.TagRegex() != nil {
 			if ok, _ := matchTag(co.Tags, f.TagRegex(), f.TagNegate()); !ok {
 				klog.V(4).Infof("#%d did not pass matchTag: %s vs %s %v", co.ID, co.Tags, f.TagRegex(), f.TagNegate())
@@ -188,15 +325,121 @@ func postEventsMatch(co *Conversation, fs []provider.Filter) bool {
 		}
 
 		if f.Prioritized != "" {
-			if ok := matchDuration(co.Prioritized, f.Prioritized); !ok {
+			if ok := matchDuration(now, co.Prioritized, f.Prioritized); !ok {
 				klog.V(4).Infof("#%d did not pass prioritized duration: %s vs %s", co.ID, co.LatestMemberResponse, f.Prioritized)
 				return false
 			}
 		}
+
+		if f.AgeInState != "" {
+			if ok := matchDuration(now, co.StateChanged, f.AgeInState); !ok {
+				klog.V(4).Infof("#%d did not pass age-in-state duration: %s vs %s", co.ID, co.StateChanged, f.AgeInState)
+				return false
+			}
+		}
+
+		if f.Opened != "" {
+			if ok := matchDuration(now, co.Opened(), f.Opened); !ok {
+				klog.V(4).Infof("#%d did not pass opened duration: %s vs %s", co.ID, co.Opened(), f.Opened)
+				return false
+			}
+		}
+
+		if f.Discussion != "" && strconv.FormatBool(LinkedDiscussion(co)) != f.Discussion {
+			klog.V(2).Infof("#%d did not pass discussion: %v (converted=%v) vs %s", co.ID, co.DiscussionLinks, co.ConvertedToDiscussion, f.Discussion)
+			return false
+		}
 	}
 	return true
 }
 
+// MatchConversation returns whether a conversation matches filters, using only its summarized data.
+// Filters which require the original item, such as body-matches, are ignored.
+func MatchConversation(co *Conversation, fs []provider.Filter, now time.Time) bool {
+	for _, f := range fs {
+		if f.State != "" && f.State != "all" && co.State != f.State {
+			return false
+		}
+
+		if f.HasNumbers() && !f.MatchNumber(co.ID) {
+			return false
+		}
+
+		if f.Locked != "" && strconv.FormatBool(co.Locked) != f.Locked {
+			return false
+		}
+
+		if f.AuthorType != "" && authorType(co.Author) != f.AuthorType {
+			return false
+		}
+
+		if f.Created != "" && !matchDuration(now, co.Created, f.Created) {
+			return false
+		}
+
+		if f.Updated != "" && !matchDuration(now, co.Updated, f.Updated) {
+			return false
+		}
+
+		if f.HumanActivity != "" && !matchDuration(now, co.LatestHumanActivity, f.HumanActivity) {
+			return false
+		}
+
+		if f.TitleRegex() != nil && !matchNegateRegex(co.Title, f.TitleRegex(), f.TitleNegate()) {
+			return false
+		}
+
+		if f.LabelRegex() != nil && !matchLabel(co.Labels, f.LabelRegex(), f.LabelNegate()) {
+			return false
+		}
+
+		if f.LabelNone() && (len(co.Labels) == 0) == f.LabelNegate() {
+			return false
+		}
+
+		if f.MilestoneRegex() != nil && !matchNegateRegex(co.Milestone.GetTitle(), f.MilestoneRegex(), f.MilestoneNegate()) {
+			return false
+		}
+
+		if f.MilestoneNone() && (co.Milestone == nil) == f.MilestoneNegate() {
+			return false
+		}
+
+		if none, negate := f.AssigneeNone(); none && (len(co.Assignees) == 0) == negate {
+			return false
+		}
+	}
+
+	return postFetchMatch(co, fs, now) && postEventsMatch(co, fs, now)
+}
+
+// matchBaseRef returns whether a PR's base branch passes a base-ref filter. PRs whose branches are unknown, such as search results, always pass.
+func matchBaseRef(co *Conversation, f provider.Filter) bool {
+	if co.BaseRef == "" {
+		return true
+	}
+
+	if f.BaseRefDefault() {
+		if co.DefaultBranch == "" {
+			return true
+		}
+		return (co.BaseRef == co.DefaultBranch) != f.BaseRefNegate()
+	}
+
+	if f.BaseRefRegex() == nil {
+		return true
+	}
+	return f.BaseRefRegex().MatchString(co.BaseRef) != f.BaseRefNegate()
+}
+
+// authorType returns whether a user is a GitHub App or bot account, or a user, based on the type GitHub reports
+func authorType(u *provider.User) string {
+	if strings.EqualFold(u.GetType(), "bot") {
+		return provider.AuthorTypeBot
+	}
+	return provider.AuthorTypeUser
+}
+
 func matchLabel(labels []*provider.Label, re *regexp.Regexp, negate bool) bool {
 	for _, l := range labels {
 		if re.MatchString(*l.Name) {
@@ -207,6 +450,20 @@ func matchLabel(labels []*provider.Label, re *regexp.Regexp, negate bool) bool {
 	return negate
 }
 
+// matchMilestoneState matches the state of a milestone. Items without a milestone never match.
+func matchMilestoneState(m *provider.Milestone, state string) bool {
+	if m == nil {
+		return false
+	}
+
+	s := m.GetState()
+	// GitLab calls open milestones "active"
+	if s == "active" {
+		s = constants.OpenState
+	}
+	return s == state
+}
+
 // matchNegateRegex matches a value against a negatable regex
 func matchNegateRegex(value string, re *regexp.Regexp, negate bool) bool {
 	if value == "" && re.String() != "" && re.String() != "^$" {
@@ -231,6 +488,11 @@ func matchTag(tags map[tag.Tag]bool, re *regexp.Regexp, negate bool) (bool, tag.
 }
 
 func ParseDuration(ds string) (time.Duration, bool, bool) {
+	return parseDuration(ds, time.Now())
+}
+
+// parseDuration parses a duration as of a point in time, which business day durations are relative to
+func parseDuration(ds string, now time.Time) (time.Duration, bool, bool) {
 	// fscking stdlib
 	matches := dayRegexp.FindStringSubmatch(ds)
 	if len(matches) > 0 {
@@ -264,6 +526,10 @@ func ParseDuration(ds string) (time.Duration, bool, bool) {
 		over = true
 	}
 
+	if d, ok := businessDuration(ds, now); ok {
+		return d, within, over
+	}
+
 	d, err := time.ParseDuration(ds)
 	if err != nil {
 		klog.Errorf("unable to parse duration %s: %v", ds, err)
@@ -272,18 +538,18 @@ func ParseDuration(ds string) (time.Duration, bool, bool) {
 	return d, within, over
 }
 
-func matchDuration(t time.Time, ds string) bool {
+func matchDuration(now time.Time, t time.Time, ds string) bool {
 	if t.IsZero() {
 		klog.Warningf("matchDuration against zero time for %s (returning false)", ds)
 		return false
 	}
 
-	d, within, over := ParseDuration(ds)
+	d, within, over := parseDuration(ds, now)
 
-	if within && time.Since(t) < d {
+	if within && now.Sub(t) < d {
 		return true
 	}
-	if over && time.Since(t) > d {
+	if over && now.Sub(t) > d {
 		return true
 	}
 	return false
@@ -318,3 +584,18 @@ func matchRange(i float64, r string) bool {
 		return false
 	}
 }
+
+// Involves returns true if a user authored, is assigned to, commented on, or reviewed an item
+func Involves(co *Conversation, login string) bool {
+	users := []*provider.User{co.Author}
+	users = append(users, co.Assignees...)
+	users = append(users, co.Commenters...)
+	users = append(users, co.Reviewers...)
+
+	for _, u := range users {
+		if u != nil && strings.EqualFold(u.GetLogin(), login) {
+			return true
+		}
+	}
+	return false
+}
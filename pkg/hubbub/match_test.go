@@ -0,0 +1,396 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubbub
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/triage-party/pkg/provider"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchDuration(t *testing.T) {
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	created := now.Add(-10 * 24 * time.Hour)
+
+	tests := []struct {
+		ds   string
+		want bool
+	}{
+		{"+7d", true},
+		{"-7d", false},
+		{"+14d", false},
+		{"-14d", true},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.want, matchDuration(now, created, tc.ds), tc.ds)
+	}
+
+	assert.False(t, matchDuration(now, time.Time{}, "+1d"), "zero time")
+}
+
+func TestPreFetchMatchBody(t *testing.T) {
+	jira := provider.Filter{RawBodyMatches: `PROJ-\d+`}
+	missing := provider.Filter{RawBodyMissing: `PROJ-\d+`}
+	for _, f := range []*provider.Filter{&jira, &missing} {
+		if err := f.LoadBodyRegex(); err != nil {
+			t.Fatalf("load: %v", err)
+		}
+	}
+
+	linkedBody := "Tracked in PROJ-123"
+	unlinkedBody := "No ticket yet"
+	linked := &provider.Issue{Body: &linkedBody}
+	unlinked := &provider.Issue{Body: &unlinkedBody}
+	now := time.Now()
+
+	assert.True(t, preFetchMatch(linked, nil, []provider.Filter{jira}, now))
+	assert.False(t, preFetchMatch(unlinked, nil, []provider.Filter{jira}, now))
+	assert.False(t, preFetchMatch(linked, nil, []provider.Filter{missing}, now))
+	assert.True(t, preFetchMatch(unlinked, nil, []provider.Filter{missing}, now))
+}
+
+func TestPostFetchMatchInvolves(t *testing.T) {
+	login := func(s string) *provider.User { return &provider.User{Login: &s} }
+	co := &Conversation{
+		Author:     login("author"),
+		Assignees:  []*provider.User{login("assignee")},
+		Commenters: []*provider.User{login("Commenter")},
+		Reviewers:  []*provider.User{login("reviewer")},
+	}
+	now := time.Now()
+
+	for _, u := range []string{"author", "assignee", "commenter", "reviewer"} {
+		assert.True(t, postFetchMatch(co, []provider.Filter{{Involves: u}}, now), u)
+	}
+	assert.False(t, postFetchMatch(co, []provider.Filter{{Involves: "bystander"}}, now))
+	assert.True(t, postFetchMatch(co, []provider.Filter{{Involves: provider.InvolvesViewer}}, now), "@me is applied by the site")
+}
+
+func TestPreFetchMatchNone(t *testing.T) {
+	noLabels := provider.Filter{RawLabel: "none"}
+	anyLabel := provider.Filter{RawLabel: "!none"}
+	noMilestone := provider.Filter{RawMilestone: "none"}
+	for _, f := range []*provider.Filter{&noLabels, &anyLabel} {
+		if err := f.LoadLabelRegex(); err != nil {
+			t.Fatalf("load: %v", err)
+		}
+	}
+	if err := noMilestone.LoadMilestoneRegex(); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	name := "bug"
+	labels := []*provider.Label{{Name: &name}}
+	bare := &provider.Issue{}
+	planned := &provider.Issue{Milestone: &provider.Milestone{}, Assignee: &provider.User{}}
+	now := time.Now()
+
+	assert.True(t, preFetchMatch(bare, nil, []provider.Filter{noLabels}, now))
+	assert.False(t, preFetchMatch(bare, labels, []provider.Filter{noLabels}, now))
+	assert.True(t, preFetchMatch(bare, labels, []provider.Filter{anyLabel}, now))
+	assert.True(t, preFetchMatch(bare, nil, []provider.Filter{noMilestone}, now))
+	assert.False(t, preFetchMatch(planned, nil, []provider.Filter{noMilestone}, now))
+	assert.True(t, preFetchMatch(bare, nil, []provider.Filter{{Assignee: "none"}}, now))
+	assert.False(t, preFetchMatch(planned, nil, []provider.Filter{{Assignee: "none"}}, now))
+	assert.True(t, preFetchMatch(planned, nil, []provider.Filter{{Assignee: "!none"}}, now))
+}
+
+func TestPreFetchMatchLocked(t *testing.T) {
+	yes := true
+	locked := &provider.Issue{Locked: &yes}
+	open := &provider.Issue{}
+	now := time.Now()
+
+	assert.True(t, preFetchMatch(locked, nil, []provider.Filter{{Locked: "true"}}, now))
+	assert.False(t, preFetchMatch(open, nil, []provider.Filter{{Locked: "true"}}, now))
+	assert.False(t, preFetchMatch(locked, nil, []provider.Filter{{Locked: "false"}}, now))
+	assert.True(t, preFetchMatch(open, nil, []provider.Filter{{Locked: "false"}}, now))
+	assert.False(t, MatchConversation(&Conversation{Locked: true}, []provider.Filter{{Locked: "false"}}, now))
+}
+
+func TestPreFetchMatchAuthorType(t *testing.T) {
+	botType, userType := "Bot", "User"
+	app := &provider.Issue{User: &provider.User{Type: &botType}}
+	human := &provider.Issue{User: &provider.User{Type: &userType}}
+	now := time.Now()
+
+	bots := []provider.Filter{{AuthorType: provider.AuthorTypeBot}}
+	users := []provider.Filter{{AuthorType: provider.AuthorTypeUser}}
+	assert.True(t, preFetchMatch(app, nil, bots, now))
+	assert.False(t, preFetchMatch(human, nil, bots, now))
+	assert.True(t, preFetchMatch(human, nil, users, now))
+	assert.True(t, preFetchMatch(&provider.Issue{}, nil, users, now), "gitlab users have no type")
+}
+
+func TestPostFetchMatchSize(t *testing.T) {
+	small := &Conversation{Type: PullRequest, Additions: 20, Deletions: 5, ChangedFiles: 1}
+	huge := &Conversation{Type: PullRequest, Additions: 900, Deletions: 400, ChangedFiles: 40}
+	issue := &Conversation{Type: Issue}
+	now := time.Now()
+
+	assert.True(t, postFetchMatch(small, []provider.Filter{{Size: "<50"}}, now))
+	assert.False(t, postFetchMatch(huge, []provider.Filter{{Size: "<50"}}, now))
+	assert.True(t, postFetchMatch(small, []provider.Filter{{Size: "s"}}, now))
+	assert.True(t, postFetchMatch(huge, []provider.Filter{{Size: "XXL"}}, now))
+	assert.True(t, postFetchMatch(huge, []provider.Filter{{ChangedFiles: ">=40"}}, now))
+	assert.False(t, postFetchMatch(issue, []provider.Filter{{Size: "XS"}}, now))
+
+	assert.True(t, ValidSize("<=100"))
+	assert.False(t, ValidSize("huge"))
+}
+
+func TestPostFetchMatchNeedsRebase(t *testing.T) {
+	conflicted := &Conversation{Type: PullRequest, Mergeable: MergeableFalse, MergeableState: "dirty"}
+	behind := &Conversation{Type: PullRequest, Mergeable: MergeableTrue, MergeableState: "behind"}
+	clean := &Conversation{Type: PullRequest, Mergeable: MergeableTrue, MergeableState: "clean"}
+	pending := &Conversation{Type: PullRequest, Mergeable: MergeableUnknown, MergeableState: MergeableUnknown}
+	now := time.Now()
+
+	rebase := []provider.Filter{{NeedsRebase: "true"}}
+	assert.True(t, postFetchMatch(conflicted, rebase, now))
+	assert.True(t, postFetchMatch(behind, rebase, now))
+	assert.False(t, postFetchMatch(clean, rebase, now))
+	assert.False(t, postFetchMatch(pending, rebase, now))
+	assert.True(t, postFetchMatch(clean, []provider.Filter{{NeedsRebase: "false"}}, now))
+	assert.True(t, postFetchMatch(pending, []provider.Filter{{NeedsRebase: "unknown"}}, now))
+}
+
+func TestFirstResponse(t *testing.T) {
+	now := time.Now()
+	created := now.Add(-96 * time.Hour)
+
+	answered := &Conversation{Created: created, FirstMemberResponse: created.Add(2 * time.Hour)}
+	setFirstResponse(answered, true, now)
+	assert.Equal(t, 2*time.Hour, answered.FirstResponseTime)
+	assert.False(t, answered.AwaitingFirstResponse)
+
+	waiting := &Conversation{Created: created, State: "open"}
+	setFirstResponse(waiting, true, now)
+	assert.True(t, waiting.AwaitingFirstResponse)
+
+	partial := &Conversation{Created: created, State: "open"}
+	setFirstResponse(partial, false, now)
+	assert.False(t, partial.AwaitingFirstResponse)
+
+	// The only member response seen may not have been the first
+	recent := &Conversation{Created: created, FirstMemberResponse: created.Add(48 * time.Hour)}
+	setFirstResponse(recent, false, now)
+	assert.Equal(t, time.Duration(0), recent.FirstResponseTime)
+
+	slow := []provider.Filter{{FirstResponse: "+3d"}}
+	assert.False(t, postFetchMatch(answered, slow, now))
+	assert.True(t, postFetchMatch(waiting, slow, now))
+	assert.False(t, postFetchMatch(partial, slow, now))
+	assert.True(t, postFetchMatch(answered, []provider.Filter{{FirstResponse: "-1d"}}, now))
+}
+
+func TestClosedWindow(t *testing.T) {
+	assert.Equal(t, time.Duration(0), ClosedWindow([]provider.Filter{{State: "closed"}}))
+	assert.Equal(t, time.Duration(0), ClosedWindow([]provider.Filter{{State: "closed"}, {Updated: "+30d"}}))
+	assert.Equal(t, 24*time.Hour, ClosedWindow([]provider.Filter{{State: "closed"}, {Updated: "-1d"}}))
+	assert.Equal(t, 48*time.Hour, ClosedWindow([]provider.Filter{{Created: "-90d"}, {Closed: "-2d"}}))
+}
+
+func TestFirstCommentPage(t *testing.T) {
+	assert.Equal(t, 0, firstCommentPage(350, 0))
+	assert.Equal(t, 0, firstCommentPage(20, 50))
+	assert.Equal(t, 4, firstCommentPage(350, 50))
+	assert.Equal(t, 3, firstCommentPage(350, 100))
+	assert.True(t, NeedsAllComments([]provider.Filter{{Commenters: ">3"}}))
+	assert.True(t, NeedsAllComments([]provider.Filter{{Responded: "+7d"}}))
+	assert.False(t, NeedsAllComments([]provider.Filter{{Awaiting: "maintainer"}}))
+}
+
+func TestParticipants(t *testing.T) {
+	now := time.Now()
+	author, other, bot := "author", "other", "k8s-ci-robot"
+	url := "https://github.com/org/project/issues/1"
+	i := &provider.Issue{User: &provider.User{Login: &author}, CreatedAt: &now, HTMLURL: &url}
+	cs := []*provider.Comment{
+		{User: &provider.User{Login: &author}, Created: now},
+		{User: &provider.User{Login: &other}, Created: now},
+		{User: &provider.User{Login: &other}, Created: now},
+		{User: &provider.User{Login: &bot}, Created: now},
+	}
+
+	h := &Engine{now: time.Now}
+	co := h.createConversation(i, cs, now)
+	assert.Equal(t, 2, co.CommentersTotal)
+	assert.Equal(t, 2, co.ParticipantsTotal)
+	assert.Equal(t, 1, h.createConversation(i, nil, now).ParticipantsTotal)
+
+	// Only the most recent comments were fetched
+	total := 10
+	i.Comments = &total
+	assert.Equal(t, 0, h.createConversation(i, cs, now).ParticipantsTotal)
+
+	assert.True(t, MatchConversation(co, []provider.Filter{{Participants: ">1"}}, now))
+	assert.False(t, MatchConversation(co, []provider.Filter{{Participants: ">2"}}, now))
+}
+
+func TestIsBot(t *testing.T) {
+	user := func(login string, kind string) *provider.User {
+		return &provider.User{Login: &login, Type: &kind}
+	}
+	assert.True(t, IsBot(user("dependabot[bot]", "Bot"), nil))
+	assert.True(t, IsBot(user("k8s-ci-robot", "User"), nil))
+	assert.True(t, IsBot(user("ci-helper", "User"), []string{"-helper"}))
+	assert.False(t, IsBot(user("ci-helper", "User"), nil))
+	assert.False(t, IsBot(user("octocat", "User"), []string{"-helper"}))
+
+	// Logins are matched regardless of case
+	assert.True(t, IsBot(user("CI-Helper", "User"), []string{"-helper"}))
+	assert.True(t, IsBot(user("K8s-CI-Robot", "User"), nil))
+}
+
+func TestLatestHumanActivity(t *testing.T) {
+	now := time.Now()
+	created := now.Add(-60 * 24 * time.Hour)
+	human := now.Add(-40 * 24 * time.Hour)
+	author, other, bot := "author", "other", "k8s-ci-robot"
+	url := "https://github.com/org/project/issues/1"
+	i := &provider.Issue{User: &provider.User{Login: &author}, CreatedAt: &created, UpdatedAt: &now, HTMLURL: &url}
+	cs := []*provider.Comment{
+		{User: &provider.User{Login: &other}, Created: human, Updated: human},
+		{User: &provider.User{Login: &bot}, Created: now, Updated: now},
+	}
+
+	h := &Engine{now: time.Now}
+	co := h.createConversation(i, cs, now)
+	assert.Equal(t, human, co.LatestHumanActivity)
+	assert.Equal(t, created, h.createConversation(i, nil, now).LatestHumanActivity)
+
+	assert.True(t, MatchConversation(co, []provider.Filter{{HumanActivity: "+30d"}}, now))
+	assert.False(t, MatchConversation(co, []provider.Filter{{Updated: "+30d"}}, now))
+	assert.False(t, postEventsMatch(co, []provider.Filter{{HumanActivity: "-30d"}}, now))
+	assert.True(t, postFetchMatch(co, []provider.Filter{{HumanActivity: "-30d"}}, now))
+
+	closed := "closed"
+	i.State = &closed
+	assert.True(t, needTimeline(i, []provider.Filter{{HumanActivity: "-30d"}}, false, true))
+	assert.True(t, needReviews(i, []provider.Filter{{HumanActivity: "-30d"}}, true))
+
+	// Only the timeline of a closed issue shows that it was converted to a discussion
+	assert.True(t, needTimeline(i, []provider.Filter{{Discussion: "true"}}, false, true))
+	assert.False(t, needTimeline(i, []provider.Filter{{Locked: "true"}}, false, true))
+}
+
+func TestOpened(t *testing.T) {
+	now := time.Now()
+	co := &Conversation{Created: now.Add(-60 * 24 * time.Hour)}
+	assert.True(t, postEventsMatch(co, []provider.Filter{{Opened: "+30d"}}, now))
+
+	co.Reopened = now.Add(-2 * 24 * time.Hour)
+	assert.Equal(t, co.Reopened, co.Opened())
+	assert.False(t, postEventsMatch(co, []provider.Filter{{Opened: "+30d"}}, now))
+	assert.True(t, postEventsMatch(co, []provider.Filter{{Opened: "-7d"}}, now))
+}
+
+func TestReviewDecision(t *testing.T) {
+	now := time.Now()
+	for state, want := range map[string]string{
+		Approved:            ReviewApproved,
+		ChangesRequested:    ReviewChangesRequested,
+		Unreviewed:          ReviewRequired,
+		Commented:           ReviewRequired,
+		PushedAfterApproval: ReviewRequired,
+		Merged:              "",
+	} {
+		co := &Conversation{Type: PullRequest, ReviewState: state}
+		assert.Equal(t, want, ReviewDecision(co), state)
+		assert.Equal(t, want == ReviewApproved, postFetchMatch(co, []provider.Filter{{Review: ReviewApproved}}, now), state)
+	}
+
+	assert.False(t, postFetchMatch(&Conversation{Type: Issue, ReviewState: Approved}, []provider.Filter{{Review: ReviewApproved}}, now))
+}
+
+func TestPreFetchMatchReferences(t *testing.T) {
+	url := "https://github.com/kubernetes/minikube/issues/7179"
+	body := "Blocked on #1234, see also kubernetes/kubernetes#99 and https://github.com/google/triage-party/pull/42.\n```\n#555\n```"
+	i := &provider.Issue{HTMLURL: &url, Body: &body}
+	now := time.Now()
+
+	for _, ref := range []string{"1234", "#1234", "kubernetes/minikube#1234", "https://github.com/kubernetes/minikube/issues/1234", "kubernetes/kubernetes#99", "google/triage-party#42"} {
+		assert.True(t, preFetchMatch(i, nil, []provider.Filter{{References: ref}}, now), ref)
+	}
+	for _, ref := range []string{"123", "99", "kubernetes/kubernetes#1234", "555"} {
+		assert.False(t, preFetchMatch(i, nil, []provider.Filter{{References: ref}}, now), ref)
+	}
+
+	assert.False(t, ValidReference("tracking issue"))
+}
+
+func TestClosesIssue(t *testing.T) {
+	body := "Fixes #12, closes: Other/Repo#3 and resolves https://github.com/org/project/issues/7.\nRelated to #99.\n```\nfixes #555\n```"
+	assert.Equal(t, []string{"org/project#12", "other/repo#3", "org/project#7"}, closingRefs(body, "org", "project"))
+	assert.Empty(t, closingRefs("fixed the prefix#3 typo, see #4", "org", "project"))
+
+	closing := &Conversation{Type: PullRequest, ClosingRefs: []string{"org/project#12"}}
+	linked := &Conversation{Type: PullRequest, LinkedIssue: true}
+	neither := &Conversation{Type: PullRequest}
+	now := time.Now()
+
+	for _, co := range []*Conversation{closing, linked} {
+		assert.True(t, postFetchMatch(co, []provider.Filter{{ClosesIssue: "true"}}, now))
+		assert.False(t, postFetchMatch(co, []provider.Filter{{ClosesIssue: "false"}}, now))
+	}
+	assert.True(t, postFetchMatch(neither, []provider.Filter{{ClosesIssue: "false"}}, now))
+	assert.False(t, postFetchMatch(&Conversation{Type: Issue}, []provider.Filter{{ClosesIssue: "false"}}, now))
+
+	connected, disconnected := "connected", "disconnected"
+	assert.True(t, linkedIssue([]*provider.Timeline{{Event: &connected}}))
+	assert.False(t, linkedIssue([]*provider.Timeline{{Event: &connected}, {Event: &disconnected}}))
+}
+
+func TestLinkedDiscussion(t *testing.T) {
+	body := "Moved to https://github.com/org/project/discussions/12, see also https://github.com/orgs/org/discussions/3.\n```\nhttps://github.com/org/project/discussions/99\n```"
+	links := appendDiscussionLinks(nil, body)
+	assert.Equal(t, []string{"https://github.com/org/project/discussions/12", "https://github.com/orgs/org/discussions/3"}, links)
+	assert.Len(t, appendDiscussionLinks(links, "https://github.com/org/project/discussions/12"), 2)
+
+	now := time.Now()
+	linked := &Conversation{DiscussionLinks: links}
+	converted := &Conversation{ConvertedToDiscussion: true}
+	for _, co := range []*Conversation{linked, converted} {
+		assert.True(t, postEventsMatch(co, []provider.Filter{{Discussion: "true"}}, now))
+		assert.False(t, postEventsMatch(co, []provider.Filter{{Discussion: "false"}}, now))
+	}
+	assert.True(t, postEventsMatch(&Conversation{}, []provider.Filter{{Discussion: "false"}}, now))
+}
+
+func TestPostFetchMatchBaseRef(t *testing.T) {
+	main := &Conversation{Type: PullRequest, BaseRef: "main", DefaultBranch: "main"}
+	release := &Conversation{Type: PullRequest, BaseRef: "release-1.2", DefaultBranch: "main"}
+	searched := &Conversation{Type: PullRequest}
+	now := time.Now()
+
+	load := func(ref string) []provider.Filter {
+		f := provider.Filter{RawBaseRef: ref}
+		if err := f.LoadBaseRefRegex(); err != nil {
+			t.Fatalf("load %q: %v", ref, err)
+		}
+		return []provider.Filter{f}
+	}
+
+	assert.True(t, postFetchMatch(main, load("default"), now))
+	assert.False(t, postFetchMatch(release, load("default"), now))
+	assert.True(t, postFetchMatch(release, load("!default"), now))
+	assert.True(t, postFetchMatch(release, load("release-.*"), now))
+	assert.False(t, postFetchMatch(main, load("release-.*"), now))
+	assert.True(t, postFetchMatch(searched, load("default"), now), "unknown branches pass")
+}
@@ -0,0 +1,100 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubbub
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/triage-party/pkg/provider"
+
+	"k8s.io/klog/v2"
+)
+
+// maxDirectNumbers is the most item numbers a number filter may list for them to be fetched one at a time
+const maxDirectNumbers = 50
+
+// directNumbers returns the item numbers to fetch one at a time, rather than listing every item,
+// if a number filter restricts the search to few enough of them
+func directNumbers(fs []provider.Filter) []int {
+	for _, f := range fs {
+		if ns := f.Numbers(maxDirectNumbers); ns != nil {
+			return ns
+		}
+	}
+	return nil
+}
+
+// issuesByNumber gets issues by number, skipping PRs and numbers which cannot be fetched
+func (h *Engine) issuesByNumber(ctx context.Context, sp provider.SearchParams, ns []int) ([]*provider.Issue, time.Time) {
+	age := time.Now()
+	is := []*provider.Issue{}
+	for _, n := range ns {
+		sp.IssueNumber = n
+		i, created, err := h.cachedIssue(ctx, sp)
+		if err != nil {
+			klog.V(1).Infof("skipping %s/%s #%d: %v", sp.Repo.Organization, sp.Repo.Project, n, err)
+			continue
+		}
+		if i.IsPullRequest() {
+			continue
+		}
+		if created.Before(age) {
+			age = created
+		}
+		is = append(is, i)
+	}
+	return is, age
+}
+
+// prsByNumber gets PRs by number, skipping numbers which cannot be fetched
+func (h *Engine) prsByNumber(ctx context.Context, sp provider.SearchParams, ns []int) ([]*provider.PullRequest, time.Time) {
+	age := time.Now()
+	prs := []*provider.PullRequest{}
+	for _, n := range ns {
+		sp.IssueNumber = n
+		sp.Fetch = true
+		pr, created, err := h.cachedPR(ctx, sp)
+		if err != nil || pr == nil {
+			klog.V(1).Infof("skipping %s/%s #%d: %v", sp.Repo.Organization, sp.Repo.Project, n, err)
+			continue
+		}
+		if created.Before(age) {
+			age = created
+		}
+		prs = append(prs, pr)
+	}
+	return prs, age
+}
+
+// cachedIssue gets a single issue, from the cache if it is newer than sp.NewerThan
+func (h *Engine) cachedIssue(ctx context.Context, sp provider.SearchParams) (*provider.Issue, time.Time, error) {
+	key := fmt.Sprintf("%s-%s-%d-issue", sp.Repo.Organization, sp.Repo.Project, sp.IssueNumber)
+	if x := h.cache.GetNewerThan(key, sp.NewerThan); x != nil && len(x.Issues) > 0 {
+		return x.Issues[0], x.Created, nil
+	}
+
+	start := time.Now()
+	i, err := h.updateIssue(ctx, sp)
+	if err != nil {
+		return nil, start, err
+	}
+
+	if err := h.cache.Set(key, &provider.Thing{Issues: []*provider.Issue{i}}); err != nil {
+		klog.Errorf("set %q failed: %v", key, err)
+	}
+	return i, start, nil
+}
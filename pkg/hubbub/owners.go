@@ -0,0 +1,70 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubbub
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/triage-party/pkg/provider"
+	"k8s.io/klog/v2"
+)
+
+// defaultRepoListMaxAge is how long the repositories of an owner are cached for, unless configured
+const defaultRepoListMaxAge = time.Hour
+
+// OwnerRepos returns the names of the non-archived repositories with issues enabled, owned by sp.Repo.Organization
+func (h *Engine) OwnerRepos(ctx context.Context, sp provider.SearchParams) ([]string, error) {
+	org := sp.Repo.Organization
+	key := fmt.Sprintf("repos-%s-%s", sp.Repo.Host, strings.ToLower(org))
+
+	set := map[string]bool{}
+	if x := h.cache.GetNewerThan(key, h.now().Add(-h.repoListMaxAge)); x != nil {
+		set = x.StringBool
+	} else {
+		klog.Infof("Listing repositories owned by %s", org)
+		sp.ListOptions = provider.ListOptions{PerPage: 100}
+		for {
+			names, resp, err := h.provider(sp.Repo.Host).RepositoriesListByOwner(ctx, sp)
+			if err != nil {
+				return nil, fmt.Errorf("repositories of %s: %w", org, err)
+			}
+			h.logRate(resp.Rate)
+
+			for _, n := range names {
+				set[n] = true
+			}
+
+			if resp.NextPage == 0 || sp.ListOptions.Page == resp.NextPage {
+				break
+			}
+			sp.ListOptions.Page = resp.NextPage
+		}
+
+		if err := h.cache.Set(key, &provider.Thing{StringBool: set}); err != nil {
+			klog.Errorf("set %q failed: %v", key, err)
+		}
+	}
+
+	names := []string{}
+	for n := range set {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names, nil
+}
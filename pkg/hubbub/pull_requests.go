@@ -42,6 +42,7 @@ const (
 // cachedPRs returns a list of cached PR's if possible
 func (h *Engine) cachedPRs(ctx context.Context, sp provider.SearchParams) ([]*provider.PullRequest, time.Time, error) {
 	sp.SearchKey = prSearchKey(sp)
+	sp.NewerThan = h.repoNewerThan(sp)
 	if x := h.cache.GetNewerThan(sp.SearchKey, sp.NewerThan); x != nil {
 		// Normally the similarity tables are only updated when fresh data is encountered.
 		if sp.NewerThan.IsZero() {
@@ -268,8 +269,26 @@ func (h *Engine) createPRSummary(ctx context.Context, sp provider.SearchParams,
 	timeline []*provider.Timeline, reviews []*provider.PullRequestReview) *Conversation {
 	co := h.createConversation(pr, cs, sp.Age)
 	co.Type = PullRequest
+	co.BaseRef = pr.GetBase().GetRef()
+	co.DefaultBranch = pr.GetBase().GetRepo().GetDefaultBranch()
 	co.ReviewsTotal = len(reviews)
 	co.TimelineTotal = len(timeline)
+	co.ClosingRefs = closingRefs(pr.GetBody(), co.Organization, co.Project)
+	co.LinkedIssue = linkedIssue(timeline)
+
+	co.RequestedReviewers = pr.RequestedReviewers
+	for _, t := range pr.RequestedTeams {
+		co.RequestedTeams = append(co.RequestedTeams, co.Organization+"/"+t.GetSlug())
+	}
+
+	seenReviewers := map[string]bool{}
+	for _, r := range reviews {
+		h.humanActivity(co, r.User, r.GetSubmittedAt())
+		if r.User != nil && !seenReviewers[r.User.GetLogin()] {
+			co.Reviewers = append(co.Reviewers, r.User)
+			seenReviewers[r.User.GetLogin()] = true
+		}
+	}
 	h.addEvents(ctx, sp, co, timeline)
 
 	co.ReviewState = reviewState(pr, timeline, reviews)
@@ -292,10 +311,10 @@ func (h *Engine) createPRSummary(ctx context.Context, sp provider.SearchParams,
 func (h *Engine) PRSummary(ctx context.Context, sp provider.SearchParams, pr *provider.PullRequest, cs []*provider.Comment, timeline []*provider.Timeline,
 	reviews []*provider.PullRequestReview) *Conversation {
 	key := pr.GetHTMLURL()
-	cached, ok := h.seen[key]
-	if ok {
+	cached := h.Lookup(key)
+	if cached != nil {
 		if !cached.Seen.Before(h.mtime(pr)) && cached.CommentsSeen >= len(cs) && cached.TimelineTotal >= len(timeline) && cached.ReviewsTotal >= len(reviews) {
-			return h.seen[key]
+			return cached
 		}
 		if cached.CommentsSeen < len(cs) {
 			klog.V(2).Infof("%s in issue cache, but is missing comments. Live @ %s (%d comments), cached @ %s (%d comments)  ", pr.GetHTMLURL(), h.mtime(pr), len(cs), cached.Seen, cached.CommentsSeen)
@@ -308,6 +327,19 @@ func (h *Engine) PRSummary(ctx context.Context, sp provider.SearchParams, pr *pr
 		}
 	}
 
-	h.seen[key] = h.createPRSummary(ctx, sp, pr, cs, timeline, reviews)
-	return h.seen[key]
+	return h.storeConversation(key, h.createPRSummary(ctx, sp, pr, cs, timeline, reviews))
+}
+
+// linkedIssue returns whether more issues have been manually linked to a PR than unlinked, based on its timeline
+func linkedIssue(timeline []*provider.Timeline) bool {
+	linked := 0
+	for _, t := range timeline {
+		switch t.GetEvent() {
+		case "connected":
+			linked++
+		case "disconnected":
+			linked--
+		}
+	}
+	return linked > 0
 }
@@ -37,3 +37,24 @@ func reactions(r *provider.Reactions) map[string]int {
 		reactHooray:     r.GetHooray(),
 	}
 }
+
+// IsReaction returns true if s is a known reaction type, such as "thumbs_up"
+func IsReaction(s string) bool {
+	_, ok := reactions(nil)[s]
+	return ok
+}
+
+// reactionsTotal returns the number of reactions, counting only the configured reaction types
+func (h *Engine) reactionsTotal(r *provider.Reactions) int {
+	if len(h.countedReactions) == 0 {
+		return r.GetTotalCount()
+	}
+
+	total := 0
+	for k, v := range reactions(r) {
+		if h.countedReactions[k] {
+			total += v
+		}
+	}
+	return total
+}
@@ -0,0 +1,145 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubbub
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// refURLRe matches GitHub and GitLab issue and PR URLs, such as https://github.com/org/project/pull/12
+	refURLRe = regexp.MustCompile(`https?://[^/\s]+/([\w.-]+)/([\w.-]+)/(?:-/)?(?:issues|pull|merge_requests)/(\d+)\b`)
+	// refShortRe matches #12 and org/project#12
+	refShortRe = regexp.MustCompile(`(?:^|[^\w/#])(?:([\w.-]+)/([\w.-]+))?#(\d+)\b`)
+	// refNumberRe matches a bare number, as accepted by the references filter
+	refNumberRe = regexp.MustCompile(`^#?(\d+)$`)
+	// closingRe matches GitHub closing keywords followed by a reference, such as "Fixes #12" or "closes: org/project#12"
+	closingRe = regexp.MustCompile(`(?i)\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?)\s*:?\s+(https?://[^/\s]+/[\w.-]+/[\w.-]+/(?:-/)?(?:issues|pull|merge_requests)/\d+|(?:[\w.-]+/[\w.-]+)?#\d+)\b`)
+)
+
+// reference is an issue or PR, with an empty org and project meaning the same repository
+type reference struct {
+	org     string
+	project string
+	num     int
+}
+
+// parseReference parses a references filter: 12, #12, org/project#12, or an issue or PR URL
+func parseReference(s string) (reference, bool) {
+	s = strings.TrimSpace(s)
+	if m := refNumberRe.FindStringSubmatch(s); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return reference{num: n}, true
+	}
+
+	for _, re := range []*regexp.Regexp{refURLRe, refShortRe} {
+		if m := re.FindStringSubmatch(s); m != nil && m[0] == s {
+			n, _ := strconv.Atoi(m[3])
+			return reference{org: strings.ToLower(m[1]), project: strings.ToLower(m[2]), num: n}, true
+		}
+	}
+	return reference{}, false
+}
+
+// ValidReference returns whether a references filter is valid
+func ValidReference(s string) bool {
+	_, ok := parseReference(s)
+	return ok
+}
+
+// references returns the issues and PRs mentioned within text, ignoring code samples
+func references(text string) []reference {
+	text = codeRe.ReplaceAllString(text, "<code></code>")
+	text = detailsRe.ReplaceAllString(text, "<details></details>")
+
+	refs := []reference{}
+	for _, re := range []*regexp.Regexp{refURLRe, refShortRe} {
+		for _, m := range re.FindAllStringSubmatch(text, -1) {
+			n, err := strconv.Atoi(m[3])
+			if err != nil {
+				continue
+			}
+			refs = append(refs, reference{org: strings.ToLower(m[1]), project: strings.ToLower(m[2]), num: n})
+		}
+	}
+	return refs
+}
+
+// mentions returns true if text within org/project references the issue or PR described by a references filter
+func mentions(text string, org string, project string, want string) bool {
+	w, ok := parseReference(want)
+	if !ok {
+		return false
+	}
+
+	org = strings.ToLower(org)
+	project = strings.ToLower(project)
+	if w.org == "" {
+		w.org, w.project = org, project
+	}
+
+	for _, r := range references(text) {
+		if r.org == "" {
+			r.org, r.project = org, project
+		}
+		if r == w {
+			return true
+		}
+	}
+	return false
+}
+
+// urlRepo returns the org and project of an item URL, such as https://github.com/kubernetes/minikube/issues/7179
+func urlRepo(url string) (string, string) {
+	parts := strings.Split(url, "/")
+	if len(parts) < 5 {
+		return "", ""
+	}
+	return parts[3], parts[4]
+}
+
+// closingRefs returns the issues which text within org/project closes using keywords such as "fixes #12",
+// as org/project#12, ignoring code samples
+func closingRefs(text string, org string, project string) []string {
+	text = codeRe.ReplaceAllString(text, "<code></code>")
+	text = detailsRe.ReplaceAllString(text, "<details></details>")
+
+	refs := []string{}
+	seen := map[string]bool{}
+	for _, m := range closingRe.FindAllStringSubmatch(text, -1) {
+		r, ok := parseReference(m[1])
+		if !ok {
+			continue
+		}
+		if r.org == "" {
+			r.org, r.project = strings.ToLower(org), strings.ToLower(project)
+		}
+
+		s := fmt.Sprintf("%s/%s#%d", r.org, r.project, r.num)
+		if !seen[s] {
+			refs = append(refs, s)
+			seen[s] = true
+		}
+	}
+	return refs
+}
+
+// ClosesIssue returns whether a PR closes an issue, via a closing keyword or a manually linked issue
+func ClosesIssue(co *Conversation) bool {
+	return len(co.ClosingRefs) > 0 || co.LinkedIssue
+}
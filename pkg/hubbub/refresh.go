@@ -0,0 +1,122 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubbub
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/triage-party/pkg/provider"
+	"github.com/google/triage-party/pkg/tag"
+	"k8s.io/klog/v2"
+)
+
+// RefreshItem fetches a single issue or PR, updating the conversation seen for it
+func (h *Engine) RefreshItem(ctx context.Context, sp provider.SearchParams) (*Conversation, error) {
+	if !sp.PullRequest {
+		i, err := h.updateIssue(ctx, sp)
+		if err != nil {
+			return nil, err
+		}
+		if !i.IsPullRequest() {
+			return h.refreshIssue(ctx, sp, i), nil
+		}
+		sp.PullRequest = true
+	}
+
+	sp.SearchKey = fmt.Sprintf("%s-%s-%d-pr", sp.Repo.Organization, sp.Repo.Project, sp.IssueNumber)
+	pr, _, err := h.updatePR(ctx, sp)
+	if err != nil {
+		return nil, err
+	}
+	return h.refreshPR(ctx, sp, pr), nil
+}
+
+// updateIssue gets a single issue
+func (h *Engine) updateIssue(ctx context.Context, sp provider.SearchParams) (*provider.Issue, error) {
+	klog.V(1).Infof("Downloading single issue %s/%s #%d", sp.Repo.Organization, sp.Repo.Project, sp.IssueNumber)
+
+	p := h.provider(sp.Repo.Host)
+	i, resp, err := p.IssuesGet(ctx, sp)
+	if err != nil {
+		return nil, err
+	}
+
+	h.logRate(resp.Rate)
+	h.updateMtime(i, i.GetUpdatedAt())
+	return i, nil
+}
+
+// refreshIssue builds the conversation for a freshly fetched issue, fetching anything which is out of date
+func (h *Engine) refreshIssue(ctx context.Context, sp provider.SearchParams, i *provider.Issue) *Conversation {
+	sp.NewerThan = h.mtime(i)
+	sp.UpdateAt = h.mtime(i)
+	sp.Fetch = true
+	sp.CommentCount = i.GetComments()
+
+	comments, _, err := h.cachedIssueComments(ctx, sp)
+	if err != nil {
+		klog.Errorf("comments: %v", err)
+	}
+
+	co := h.IssueSummary(i, comments, time.Now())
+	co.Labels = i.Labels
+	co.Similar = h.FindSimilar(co)
+	if len(co.Similar) > 0 {
+		co.Tags[tag.Similar] = true
+	}
+
+	timeline, err := h.cachedTimeline(ctx, sp)
+	if err != nil {
+		klog.Errorf("timeline: %v", err)
+	}
+	h.addEvents(ctx, sp, co, timeline)
+	co.PullRequestRefs = h.updateLinkedPRs(ctx, sp, co)
+	return co
+}
+
+// refreshPR builds the conversation for a freshly fetched PR, fetching anything which is out of date
+func (h *Engine) refreshPR(ctx context.Context, sp provider.SearchParams, pr *provider.PullRequest) *Conversation {
+	sp.NewerThan = h.mtime(pr)
+	sp.Fetch = true
+	sp.CommentCount = pr.GetComments()
+
+	comments, _, err := h.prComments(ctx, sp)
+	if err != nil {
+		klog.Errorf("comments: %v", err)
+	}
+
+	timeline, err := h.cachedTimeline(ctx, sp)
+	if err != nil {
+		klog.Errorf("timeline: %v", err)
+	}
+
+	reviews, _, err := h.cachedReviews(ctx, sp)
+	if err != nil {
+		klog.Errorf("reviews: %v", err)
+	}
+
+	sp.Age = time.Now()
+	co := h.PRSummary(ctx, sp, pr, comments, timeline, reviews)
+	co.Labels = pr.Labels
+	h.addMergeState(ctx, sp, co, pr)
+	h.addSize(ctx, sp, co, pr)
+	co.Similar = h.FindSimilar(co)
+	if len(co.Similar) > 0 {
+		co.Tags[tag.Similar] = true
+	}
+	return co
+}
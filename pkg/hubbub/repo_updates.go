@@ -0,0 +1,49 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubbub
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/triage-party/pkg/provider"
+)
+
+func repoUpdateKey(org string, project string) string {
+	return strings.ToLower(org + "/" + project)
+}
+
+// RepoUpdated records that a repository changed at t, such as when a webhook is received,
+// so that cached lists of its issues and PRs from before then are fetched again.
+func (e *Engine) RepoUpdated(org string, project string, t time.Time) {
+	e.repoUpdatedMu.Lock()
+	defer e.repoUpdatedMu.Unlock()
+
+	key := repoUpdateKey(org, project)
+	if t.After(e.repoUpdated[key]) {
+		e.repoUpdated[key] = t
+	}
+}
+
+// repoNewerThan returns how new cached lists for a repository must be, taking any known updates into account
+func (e *Engine) repoNewerThan(sp provider.SearchParams) time.Time {
+	e.repoUpdatedMu.RLock()
+	defer e.repoUpdatedMu.RUnlock()
+
+	if t := e.repoUpdated[repoUpdateKey(sp.Repo.Organization, sp.Repo.Project)]; t.After(sp.NewerThan) {
+		return t
+	}
+	return sp.NewerThan
+}
@@ -141,6 +141,46 @@ func reviewState(pr provider.IItem, timeline []*provider.Timeline, reviews []*pr
 	return state
 }
 
+// Review decisions, as matched by the review filter
+const (
+	ReviewApproved         = "approved"
+	ReviewChangesRequested = "changes-requested"
+	ReviewRequired         = "review-required"
+)
+
+// IsReviewDecision returns true if s is a known review decision
+func IsReviewDecision(s string) bool {
+	return s == ReviewApproved || s == ReviewChangesRequested || s == ReviewRequired
+}
+
+// ReviewDecision summarizes the review state of an open PR: approved, changes-requested, or review-required.
+// It is empty for issues, and for closed or merged PRs.
+func ReviewDecision(co *Conversation) string {
+	if co.Type != PullRequest {
+		return ""
+	}
+
+	switch co.ReviewState {
+	case Approved:
+		return ReviewApproved
+	case ChangesRequested:
+		return ReviewChangesRequested
+	case Unreviewed, NewCommits, PushedAfterApproval, Commented:
+		return ReviewRequired
+	}
+	return ""
+}
+
+// needReviewDecision returns true if the filters match on review decisions
+func needReviewDecision(fs []provider.Filter) bool {
+	for _, f := range fs {
+		if f.Review != "" {
+			return true
+		}
+	}
+	return false
+}
+
 func reviewStateTag(st string) tag.Tag {
 	switch st {
 	case Approved:
@@ -60,16 +60,22 @@ func (h *Engine) SearchIssues(ctx context.Context, sp provider.SearchParams) ([]
 		sp.Filters,
 		logu.STime(sp.NewerThan),
 	)
+
+	if ns := directNumbers(sp.Filters); ns != nil {
+		is, age := h.issuesByNumber(ctx, sp, ns)
+		return h.filterIssues(ctx, sp, is, age), age, nil
+	}
 	var wg sync.WaitGroup
 
 	var open []*provider.Issue
 	var closed []*provider.Issue
-	var err error
 
+	// Each side lists with its own copy of sp, and reports its own age
 	age := time.Now()
+	openAge, closedAge := age, age
 
 	wg.Add(1)
-	go func() {
+	go func(sp provider.SearchParams) {
 		defer wg.Done()
 
 		sp.State = constants.OpenState
@@ -82,38 +88,40 @@ func (h *Engine) SearchIssues(ctx context.Context, sp provider.SearchParams) ([]
 			klog.Errorf("open issues: %v", err)
 			return
 		}
-		if ots.Before(age) {
-			age = ots
-		}
+		openAge = ots
 		open = oi
 		klog.V(1).Infof("%s/%s open issue count: %d", sp.Repo.Organization, sp.Repo.Project, len(open))
-	}()
+	}(sp)
 
 	wg.Add(1)
-	go func() {
+	go func(sp provider.SearchParams) {
 		defer wg.Done()
 		if !NeedsClosed(sp.Filters) {
 			return
 		}
 
 		sp.State = constants.ClosedState
-		sp.UpdateAge = h.MaxClosedUpdateAge
+		sp.UpdateAge = h.closedUpdateAge(sp, issueSearchKey)
 
 		ci, cts, err := h.cachedIssues(ctx, sp)
 		if err != nil {
 			klog.Errorf("closed issues: %v", err)
 		}
 
-		if cts.Before(age) {
-			age = cts
-		}
+		closedAge = cts
 		closed = ci
 
 		klog.V(1).Infof("%s/%s closed issue count: %d", sp.Repo.Organization, sp.Repo.Project, len(closed))
-	}()
+	}(sp)
 
 	wg.Wait()
 
+	for _, t := range []time.Time{openAge, closedAge} {
+		if t.Before(age) {
+			age = t
+		}
+	}
+
 	var is []*provider.Issue
 	seen := map[string]bool{}
 
@@ -134,6 +142,11 @@ func (h *Engine) SearchIssues(ctx context.Context, sp provider.SearchParams) ([]
 		is = append(is, i)
 	}
 
+	return h.filterIssues(ctx, sp, is, age), age, nil
+}
+
+// filterIssues fetches further data for issues as necessary, returning those which match the filters
+func (h *Engine) filterIssues(ctx context.Context, sp provider.SearchParams, is []*provider.Issue, age time.Time) []*Conversation {
 	var filtered []*Conversation
 	klog.V(1).Infof("%s/%s aggregate issue count: %d, filtering for:\n%s", sp.Repo.Organization, sp.Repo.Project, len(is), sp.Filters)
 
@@ -153,15 +166,13 @@ func (h *Engine) SearchIssues(ctx context.Context, sp provider.SearchParams) ([]
 			labels = append(labels, l)
 		}
 
-		if !preFetchMatch(i, labels, sp.Filters) {
+		if !preFetchMatch(i, h.withAliases(labels), sp.Filters, h.now()) {
 			klog.V(1).Infof("#%d - %q did not match item filter: %s", i.GetNumber(), i.GetTitle(), sp.Filters)
 			continue
 		}
 
 		klog.V(1).Infof("#%d - %q made it past pre-fetch: %s", i.GetNumber(), i.GetTitle(), sp.Filters)
 
-		comments := []*provider.IssueComment{}
-
 		fetchComments := false
 		if needComments(i, sp.Filters) && i.GetComments() > 0 {
 			klog.V(1).Infof("#%d - %q: need comments for final filtering", i.GetNumber(), i.GetTitle())
@@ -171,8 +182,9 @@ func (h *Engine) SearchIssues(ctx context.Context, sp provider.SearchParams) ([]
 		sp.IssueNumber = i.GetNumber()
 		sp.NewerThan = h.mtime(i)
 		sp.Fetch = fetchComments
+		sp.CommentCount = i.GetComments()
 
-		comments, _, err = h.cachedIssueComments(ctx, sp)
+		comments, _, err := h.cachedIssueComments(ctx, sp)
 		if err != nil {
 			klog.Errorf("comments: %v", err)
 		}
@@ -185,7 +197,7 @@ func (h *Engine) SearchIssues(ctx context.Context, sp provider.SearchParams) ([]
 			co.Tags[tag.Similar] = true
 		}
 
-		if !postFetchMatch(co, sp.Filters) {
+		if !postFetchMatch(co, sp.Filters, h.now()) {
 			klog.V(1).Infof("#%d - %q did not match post-fetch filter: %s", i.GetNumber(), i.GetTitle(), sp.Filters)
 			continue
 		}
@@ -218,16 +230,20 @@ func (h *Engine) SearchIssues(ctx context.Context, sp provider.SearchParams) ([]
 		sp.Fetch = fetchReviews
 		co.PullRequestRefs = h.updateLinkedPRs(ctx, sp, co)
 
-		if !postEventsMatch(co, sp.Filters) {
+		if !postEventsMatch(co, sp.Filters, h.now()) {
 			klog.V(1).Infof("#%d - %q did not match post-events filter: %s", i.GetNumber(), i.GetTitle(), sp.Filters)
 			continue
 		}
 		klog.V(1).Infof("#%d - %q made it past post-events: %s", i.GetNumber(), i.GetTitle(), sp.Filters)
 
+		if !h.peopleMatch(ctx, sp, co) {
+			continue
+		}
+
 		filtered = append(filtered, co)
 	}
 
-	return filtered, age, nil
+	return filtered
 }
 
 // NeedsClosed returns whether or not the filters require closed items
@@ -255,17 +271,23 @@ func (h *Engine) SearchPullRequests(ctx context.Context, sp provider.SearchParam
 
 	klog.V(1).Infof("Gathering raw data for %s/%s PR's matching: %s - newer than %s",
 		sp.Repo.Organization, sp.Repo.Project, sp.Filters, logu.STime(sp.NewerThan))
-	filtered := []*Conversation{}
+
+	if ns := directNumbers(sp.Filters); ns != nil {
+		prs, age := h.prsByNumber(ctx, sp, ns)
+		return h.filterPRs(ctx, sp, prs, age), age, nil
+	}
 
 	var wg sync.WaitGroup
 
 	var open []*provider.PullRequest
 	var closed []*provider.PullRequest
-	var err error
+
+	// Each side lists with its own copy of sp, and reports its own age
 	age := time.Now()
+	openAge, closedAge := age, age
 
 	wg.Add(1)
-	go func() {
+	go func(sp provider.SearchParams) {
 		defer wg.Done()
 
 		sp.State = constants.OpenState
@@ -279,23 +301,20 @@ func (h *Engine) SearchPullRequests(ctx context.Context, sp provider.SearchParam
 			klog.Errorf("open prs: %v", err)
 			return
 		}
-		if ots.Before(age) {
-			klog.Infof("setting age to %s (open PR count)", ots)
-			age = ots
-		}
+		openAge = ots
 		open = op
 		klog.V(1).Infof("open PR count: %d", len(open))
-	}()
+	}(sp)
 
 	wg.Add(1)
-	go func() {
+	go func(sp provider.SearchParams) {
 		defer wg.Done()
 		if !NeedsClosed(sp.Filters) {
 			return
 		}
 
-		sp.UpdateAge = h.MaxClosedUpdateAge
 		sp.State = constants.ClosedState
+		sp.UpdateAge = h.closedUpdateAge(sp, prSearchKey)
 
 		cp, cts, err := h.cachedPRs(ctx, sp)
 		if err != nil {
@@ -303,18 +322,21 @@ func (h *Engine) SearchPullRequests(ctx context.Context, sp provider.SearchParam
 			return
 		}
 
-		if cts.Before(age) {
-			klog.Infof("setting age to %s (open PR count)", cts)
-			age = cts
-		}
-
+		closedAge = cts
 		closed = cp
 
 		klog.V(1).Infof("closed PR count: %d", len(closed))
-	}()
+	}(sp)
 
 	wg.Wait()
 
+	for _, t := range []time.Time{openAge, closedAge} {
+		if t.Before(age) {
+			klog.Infof("setting age to %s (PR count)", t)
+			age = t
+		}
+	}
+
 	prs := []*provider.PullRequest{}
 	for _, pr := range append(open, closed...) {
 		if len(h.debug) > 0 {
@@ -328,8 +350,14 @@ func (h *Engine) SearchPullRequests(ctx context.Context, sp provider.SearchParam
 		prs = append(prs, pr)
 	}
 
+	return h.filterPRs(ctx, sp, prs, age), age, nil
+}
+
+// filterPRs fetches further data for PRs as necessary, returning those which match the filters
+func (h *Engine) filterPRs(ctx context.Context, sp provider.SearchParams, prs []*provider.PullRequest, age time.Time) []*Conversation {
+	filtered := []*Conversation{}
 	for _, pr := range prs {
-		if !preFetchMatch(pr, pr.Labels, sp.Filters) {
+		if !preFetchMatch(pr, h.withAliases(pr.Labels), sp.Filters, h.now()) {
 			continue
 		}
 
@@ -345,8 +373,9 @@ func (h *Engine) SearchPullRequests(ctx context.Context, sp provider.SearchParam
 		sp.IssueNumber = pr.GetNumber()
 		sp.NewerThan = h.mtime(pr)
 		sp.Fetch = fetchComments
+		sp.CommentCount = pr.GetComments()
 
-		comments, _, err = h.prComments(ctx, sp)
+		comments, _, err := h.prComments(ctx, sp)
 		if err != nil {
 			klog.Errorf("comments: %v", err)
 		}
@@ -389,25 +418,31 @@ func (h *Engine) SearchPullRequests(ctx context.Context, sp provider.SearchParam
 
 		co := h.PRSummary(ctx, sp, pr, comments, timeline, reviews)
 		co.Labels = pr.Labels
+		h.addMergeState(ctx, sp, co, pr)
+		h.addSize(ctx, sp, co, pr)
 		co.Similar = h.FindSimilar(co)
 		if len(co.Similar) > 0 {
 			co.Tags[tag.Similar] = true
 		}
 
-		if !postFetchMatch(co, sp.Filters) {
+		if !postFetchMatch(co, sp.Filters, h.now()) {
 			klog.V(4).Infof("PR #%d did not pass postFetchMatch with filter: %v", pr.GetNumber(), sp.Filters)
 			continue
 		}
 
-		if !postEventsMatch(co, sp.Filters) {
+		if !postEventsMatch(co, sp.Filters, h.now()) {
 			klog.V(1).Infof("#%d - %q did not match post-events filter: %s", pr.GetNumber(), pr.GetTitle(), sp.Filters)
 			continue
 		}
 
+		if !h.peopleMatch(ctx, sp, co) {
+			continue
+		}
+
 		filtered = append(filtered, co)
 	}
 
-	return filtered, age, nil
+	return filtered
 }
 
 func needComments(i provider.IItem, fs []provider.Filter) bool {
@@ -426,8 +461,18 @@ func needComments(i provider.IItem, fs []provider.Filter) bool {
 			return true
 		}
 
-		if f.Responded != "" || f.Commenters != "" {
-			klog.Infof("#%d - need comments due to responded/commenters filter", i.GetNumber())
+		if f.Awaiting != "" {
+			klog.Infof("#%d - need comments due to awaiting filter", i.GetNumber())
+			return true
+		}
+
+		if f.Discussion != "" {
+			klog.Infof("#%d - need comments due to discussion filter", i.GetNumber())
+			return true
+		}
+
+		if f.Responded != "" || f.Commenters != "" || f.Participants != "" || f.FirstResponse != "" || f.HumanActivity != "" {
+			klog.Infof("#%d - need comments due to responded/commenters/participants/first-response/human-activity filter", i.GetNumber())
 			return true
 		}
 	}
@@ -436,7 +481,8 @@ func needComments(i provider.IItem, fs []provider.Filter) bool {
 }
 
 func needTimeline(i provider.IItem, fs []provider.Filter, pr bool, hidden bool) bool {
-	if i.GetMilestone() != nil {
+	// Converted issues are closed, and only their timeline reports the conversion
+	if i.GetMilestone() != nil || needHumanActivity(fs) || needDiscussion(fs) {
 		return true
 	}
 
@@ -460,7 +506,7 @@ func needTimeline(i provider.IItem, fs []provider.Filter, pr bool, hidden bool)
 				}
 			}
 		}
-		if f.Prioritized != "" {
+		if f.Prioritized != "" || f.AgeInState != "" || f.Opened != "" {
 			return true
 		}
 	}
@@ -469,6 +515,10 @@ func needTimeline(i provider.IItem, fs []provider.Filter, pr bool, hidden bool)
 }
 
 func needReviews(i provider.IItem, fs []provider.Filter, hidden bool) bool {
+	if needHumanActivity(fs) {
+		return true
+	}
+
 	if (i.GetState() != constants.OpenState) && (i.GetState() != constants.OpenedState) {
 		return false
 	}
@@ -477,7 +527,7 @@ func needReviews(i provider.IItem, fs []provider.Filter, hidden bool) bool {
 		return false
 	}
 
-	if hidden {
+	if hidden && !needReviewDecision(fs) {
 		return false
 	}
 
@@ -495,6 +545,26 @@ func needReviews(i provider.IItem, fs []provider.Filter, hidden bool) bool {
 	return true
 }
 
+// needHumanActivity returns whether the filters match on human activity, which includes reviews and timeline events
+func needHumanActivity(fs []provider.Filter) bool {
+	for _, f := range fs {
+		if f.HumanActivity != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// needDiscussion returns whether the filters match on discussions, which includes conversion timeline events
+func needDiscussion(fs []provider.Filter) bool {
+	for _, f := range fs {
+		if f.Discussion != "" {
+			return true
+		}
+	}
+	return false
+}
+
 func formatStruct(x interface{}) string {
 	s, err := prettyjson.Marshal(x)
 	if err == nil {
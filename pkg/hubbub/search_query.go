@@ -0,0 +1,168 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubbub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v33/github"
+	"github.com/google/triage-party/pkg/constants"
+	"github.com/google/triage-party/pkg/logu"
+	"github.com/google/triage-party/pkg/provider"
+	"golang.org/x/time/rate"
+	"k8s.io/klog/v2"
+)
+
+// searchRate is the GitHub Search API limit for authenticated requests: 30 per minute
+var searchRate = rate.Every(time.Minute / 30)
+
+// maxRateLimitWait is the longest we will wait for the search rate limit to reset
+const maxRateLimitWait = 2 * time.Minute
+
+// SearchQuery finds issues and PR's matching a raw GitHub search query, then applies the filters
+func (h *Engine) SearchQuery(ctx context.Context, sp provider.SearchParams) ([]*Conversation, time.Time, error) {
+	sp.Filters = openByDefault(sp)
+	klog.V(1).Infof("Gathering raw data for search %q %v - newer than %s", sp.Query, sp.Filters, logu.STime(sp.NewerThan))
+
+	is, age, err := h.cachedSearch(ctx, sp)
+	if err != nil {
+		return nil, age, err
+	}
+
+	// Search results may span repositories, so comments and timelines are fetched per-repository
+	byRepo := map[provider.Repo][]*provider.Issue{}
+	repos := []provider.Repo{}
+	for _, i := range is {
+		r, err := itemRepo(i)
+		if err != nil {
+			klog.Errorf("search result: %v", err)
+			continue
+		}
+		if _, ok := byRepo[r]; !ok {
+			repos = append(repos, r)
+		}
+		byRepo[r] = append(byRepo[r], i)
+	}
+
+	var filtered []*Conversation
+	for _, r := range repos {
+		sp.Repo = r
+		for _, co := range h.filterIssues(ctx, sp, byRepo[r], age) {
+			if strings.Contains(co.URL, "/pull/") {
+				co.Type = PullRequest
+			}
+			filtered = append(filtered, co)
+		}
+	}
+
+	return filtered, age, nil
+}
+
+// itemRepo returns the repository an item belongs to, based on its URL
+func itemRepo(i *provider.Issue) (provider.Repo, error) {
+	// "https://github.com/kubernetes/minikube/issues/7179",
+	parts := strings.Split(i.GetHTMLURL(), "/")
+	if len(parts) < 5 {
+		return provider.Repo{}, fmt.Errorf("unable to parse repository from %q", i.GetHTMLURL())
+	}
+	return provider.Repo{Host: parts[2], Organization: parts[3], Project: parts[4]}, nil
+}
+
+func searchKey(sp provider.SearchParams) string {
+	return fmt.Sprintf("search-%s", sp.Query)
+}
+
+// cachedSearch returns search results, cached if possible
+func (h *Engine) cachedSearch(ctx context.Context, sp provider.SearchParams) ([]*provider.Issue, time.Time, error) {
+	sp.SearchKey = searchKey(sp)
+
+	if x := h.cache.GetNewerThan(sp.SearchKey, sp.NewerThan); x != nil {
+		return x.Issues, x.Created, nil
+	}
+
+	klog.V(1).Infof("cache miss for %s newer than %s", sp.SearchKey, logu.STime(sp.NewerThan))
+	issues, created, err := h.updateSearch(ctx, sp)
+	if err != nil {
+		klog.Warningf("Retrieving stale results for %s due to error: %v", sp.SearchKey, err)
+		x := h.cache.GetNewerThan(sp.SearchKey, time.Time{})
+		if x != nil {
+			return x.Issues, x.Created, nil
+		}
+	}
+	return issues, created, err
+}
+
+// updateSearch runs a search query, respecting the lower rate limit of the Search API
+func (h *Engine) updateSearch(ctx context.Context, sp provider.SearchParams) ([]*provider.Issue, time.Time, error) {
+	start := time.Now()
+	sp.ListOptions = provider.ListOptions{PerPage: 100}
+
+	var all []*provider.Issue
+	for {
+		if err := h.searchLimiter.Wait(ctx); err != nil {
+			return all, start, err
+		}
+
+		klog.Infof("Searching for %q (page %d)...", sp.Query, sp.ListOptions.Page)
+		pr := h.provider(constants.GitHubProviderHost)
+		if pr == nil {
+			return all, start, fmt.Errorf("search queries require a GitHub token")
+		}
+		is, resp, err := pr.SearchIssues(ctx, sp)
+
+		var rle *github.RateLimitError
+		if errors.As(err, &rle) {
+			wait := time.Until(rle.Rate.Reset.Time)
+			if wait > maxRateLimitWait {
+				return all, start, fmt.Errorf("search rate limit resets in %s: %w", wait, err)
+			}
+
+			klog.Warningf("search rate limit reached, waiting %s for reset", wait)
+			select {
+			case <-ctx.Done():
+				return all, start, ctx.Err()
+			case <-time.After(wait):
+			}
+			continue
+		}
+
+		if err != nil {
+			return all, start, err
+		}
+
+		h.logRate(resp.Rate)
+
+		for _, i := range is {
+			h.updateMtime(i, i.GetUpdatedAt())
+		}
+		all = append(all, is...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		sp.ListOptions.Page = resp.NextPage
+	}
+
+	if err := h.cache.Set(sp.SearchKey, &provider.Thing{Issues: all}); err != nil {
+		klog.Errorf("set %q failed: %v", sp.SearchKey, err)
+	}
+
+	klog.V(1).Infof("updateSearch %s returning %d items", sp.SearchKey, len(all))
+	return all, start, nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubbub
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// headingRe matches a markdown heading, such as "### Steps to reproduce"
+	headingRe = regexp.MustCompile(`^\s{0,3}#{1,6}\s+(.*?)[\s#]*$`)
+	// boldHeadingRe matches a line which is entirely bold, such as "**Steps to reproduce:**"
+	boldHeadingRe = regexp.MustCompile(`^\s*\*\*(.+?):?\*\*:?\s*$`)
+	// commentRe matches HTML comments, as used for hints within issue templates
+	commentRe = regexp.MustCompile(`(?s)<!--.*?-->`)
+)
+
+// noResponse is the placeholder GitHub issue forms use for optional fields left blank
+const noResponse = "_No response_"
+
+// heading returns the text of a heading line, or false if the line is not a heading
+func heading(l string) (string, bool) {
+	if m := headingRe.FindStringSubmatch(l); m != nil {
+		return m[1], true
+	}
+	if m := boldHeadingRe.FindStringSubmatch(l); m != nil {
+		return m[1], true
+	}
+	return "", false
+}
+
+// hasSection returns whether a markdown body has a non-empty section whose heading matches re
+func hasSection(body string, re *regexp.Regexp) bool {
+	body = commentRe.ReplaceAllString(strings.Replace(body, "\r\n", "\n", -1), "")
+
+	fenced := false
+	in := false
+	var content []string
+
+	for _, l := range strings.Split(body, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(l), "```") {
+			fenced = !fenced
+		} else if !fenced {
+			if h, ok := heading(l); ok {
+				if in && filled(content) {
+					return true
+				}
+				in = re.MatchString(h)
+				content = nil
+				continue
+			}
+		}
+
+		if in {
+			content = append(content, l)
+		}
+	}
+	return in && filled(content)
+}
+
+// filled returns whether section content contains anything other than whitespace or placeholders
+func filled(content []string) bool {
+	s := strings.TrimSpace(strings.Join(content, "\n"))
+	return s != "" && s != noResponse
+}
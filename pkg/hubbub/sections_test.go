@@ -0,0 +1,42 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubbub
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHasSection(t *testing.T) {
+	re := regexp.MustCompile("(?i)steps to reproduce")
+
+	var tests = []struct {
+		body string
+		want bool
+	}{
+		{"### Steps to reproduce\n\n1. run it\n\n### Expected\n\nworks", true},
+		{"**Steps to reproduce:**\r\n```\nmake\n```\r\n", true},
+		{"## Steps to Reproduce ##\n<!-- Please list the steps -->\n\n## Expected\nworks", false},
+		{"### Steps to reproduce\n\n_No response_\n\n### Version\n\n1.2", false},
+		{"It crashes, see the steps to reproduce below.", false},
+		{"### Description\n\n```\n# Steps to reproduce\n```\n", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, hasSection(tt.body, re), tt.body)
+	}
+}
@@ -16,7 +16,10 @@ package hubbub
 
 import (
 	"regexp"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/google/triage-party/pkg/provider"
 
@@ -85,35 +88,78 @@ func normalizeTitle(t string) string {
 	return strings.Join(keep, " ")
 }
 
+// similarItem is a title and URL to add to the similarity tables
+type similarItem struct {
+	title string
+	url   string
+}
+
 // updateSimilarIssues updates similarity tables, meant for background use
 func (h *Engine) updateSimilarIssues(key string, is []*provider.Issue) {
 	klog.V(1).Infof("Updating similarity table from issue cache %q (%d items)", key, len(is))
+	items := []similarItem{}
 	for _, i := range is {
-		h.updateSimilarityTables(i.GetTitle(), i.GetHTMLURL())
+		if h.similarityExcludeBots && h.isBot(i.GetUser()) {
+			continue
+		}
+		items = append(items, similarItem{title: i.GetTitle(), url: i.GetHTMLURL()})
 	}
+	h.updateSimilarityTables(items)
 }
 
 // updateSimilarPullRequests updates similarity tables, meant for background use
 func (h *Engine) updateSimilarPullRequests(key string, prs []*provider.PullRequest) {
 	klog.V(1).Infof("Updating similarity table from PR cache %q (%d items)", key, len(prs))
+	items := []similarItem{}
 	for _, i := range prs {
-		h.updateSimilarityTables(i.GetTitle(), i.GetHTMLURL())
+		if h.similarityExcludeBots && h.isBot(i.GetUser()) {
+			continue
+		}
+		if h.similarityExcludeDrafts && i.GetDraft() {
+			continue
+		}
+		items = append(items, similarItem{title: i.GetTitle(), url: i.GetHTMLURL()})
 	}
+	h.updateSimilarityTables(items)
 }
 
-func (h *Engine) updateSimilarityTables(rawTitle, url string) {
-	if h.MinSimilarity == 0 {
+// updateSimilarityTables adds items to the similarity tables. Each new title is compared
+// against the titles known before it, as if the items were added one at a time, but the
+// comparisons are spread across similarityWorkers.
+func (h *Engine) updateSimilarityTables(items []similarItem) {
+	if h.MinSimilarity == 0 || len(items) == 0 {
 		return
 	}
 
-	title := normalizeTitle(rawTitle)
+	h.similarMu.Lock()
+	defer h.similarMu.Unlock()
+
+	known := []string{}
+	h.titleToURLs.Range(func(k, v interface{}) bool {
+		title, ok := k.(string)
+		if !ok {
+			klog.V(1).Infof("key %q is not of type string", k)
+			return true
+		}
+		known = append(known, title)
+		return true
+	})
+	sort.Strings(known)
+	existing := len(known)
+
+	for _, i := range items {
+		title := normalizeTitle(i.title)
+
+		result, loaded := h.titleToURLs.LoadOrStore(title, []string{i.url})
+		if !loaded {
+			known = append(known, title)
+			continue
+		}
 
-	result, existing := h.titleToURLs.LoadOrStore(title, []string{url})
-	if existing {
 		foundURL := false
 		otherURLs := []string{}
 		for _, v := range result.([]string) {
-			if v == url {
+			if v == i.url {
 				foundURL = true
 				break
 			}
@@ -121,41 +167,67 @@ func (h *Engine) updateSimilarityTables(rawTitle, url string) {
 		}
 
 		if !foundURL {
-			klog.V(4).Infof("updating %q with %v", rawTitle, otherURLs)
-			h.titleToURLs.Store(title, append(otherURLs, url))
+			klog.V(4).Infof("updating %q with %v", i.title, otherURLs)
+			h.titleToURLs.Store(title, append(otherURLs, i.url))
 		}
+	}
+
+	fresh := known[existing:]
+	if len(fresh) == 0 {
 		return
 	}
 
 	// Update us -> them title similarity
-	similarTo := []string{}
+	similar := make([][]string, len(fresh))
+	workers := h.similarityWorkers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(fresh) {
+		workers = len(fresh)
+	}
 
-	h.titleToURLs.Range(func(k, v interface{}) bool {
-		otherTitle, ok := k.(string)
-		if !ok {
-			klog.V(1).Infof("key %q is not of type string", k)
-		}
-		if otherTitle == title {
-			return true
-		}
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range next {
+				similar[n] = h.similarTo(fresh[n], known[:existing+n])
+			}
+		}()
+	}
+	for n := range fresh {
+		next <- n
+	}
+	close(next)
+	wg.Wait()
 
-		if godice.CompareString(title, otherTitle) > h.MinSimilarity {
-			klog.V(4).Infof("%q is similar to %q", rawTitle, otherTitle)
-			similarTo = append(similarTo, otherTitle)
-		}
-		return true
-	})
+	for n, title := range fresh {
+		h.similarTitles.Store(title, similar[n])
 
-	h.similarTitles.Store(title, similarTo)
+		// Update them -> us title similarity
+		for _, other := range similar[n] {
+			klog.V(4).Infof("updating %q to map to %s", other, title)
+			others, ok := h.similarTitles.Load(other)
+			if ok {
+				h.similarTitles.Store(other, append(others.([]string), title))
+			}
+		}
+	}
+}
 
-	// Update them -> us title similarity
-	for _, other := range similarTo {
-		klog.V(4).Infof("updating %q to map to %s", other, title)
-		others, ok := h.similarTitles.Load(other)
-		if ok {
-			h.similarTitles.Store(other, append(others.([]string), title))
+// similarTo returns the titles within others which are similar to title
+func (h *Engine) similarTo(title string, others []string) []string {
+	similarTo := []string{}
+	for _, other := range others {
+		if godice.CompareString(title, other) > h.MinSimilarity {
+			klog.V(4).Infof("%q is similar to %q", title, other)
+			similarTo = append(similarTo, other)
 		}
 	}
+	return similarTo
 }
 
 // FindSimilar locates similar conversations to this one
@@ -200,7 +272,7 @@ func (h *Engine) FindSimilar(co *Conversation) []*RelatedConversation {
 			continue
 		}
 
-		oco := h.seen[url]
+		oco := h.Lookup(url)
 		if oco == nil {
 			continue
 		}
@@ -209,7 +281,11 @@ func (h *Engine) FindSimilar(co *Conversation) []*RelatedConversation {
 			continue
 		}
 
-		simco = append(simco, makeRelated(h.seen[url]))
+		if h.similaritySameRepo && (oco.Organization != co.Organization || oco.Project != co.Project) {
+			continue
+		}
+
+		simco = append(simco, makeRelated(oco))
 		added[url] = true
 	}
 	return simco
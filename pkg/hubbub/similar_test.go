@@ -0,0 +1,80 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubbub
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// similarItems returns n items, many of which have similar titles
+func similarItems(n int) []similarItem {
+	words := []string{"crash", "cluster", "start", "docker", "driver", "timeout", "windows", "proxy", "mount", "addon"}
+	items := []similarItem{}
+	for i := 0; i < n; i++ {
+		title := fmt.Sprintf("%s %s %s fails", words[i%len(words)], words[(i/3)%len(words)], words[(i/7)%len(words)])
+		items = append(items, similarItem{title: title, url: fmt.Sprintf("https://github.com/o/p/issues/%d", i)})
+	}
+	return items
+}
+
+// similarTable returns the sorted similarity table of an engine
+func similarTable(h *Engine) map[string][]string {
+	table := map[string][]string{}
+	h.similarTitles.Range(func(k, v interface{}) bool {
+		titles := append([]string{}, v.([]string)...)
+		sort.Strings(titles)
+		table[k.(string)] = titles
+		return true
+	})
+	return table
+}
+
+func TestUpdateSimilarityTablesWorkers(t *testing.T) {
+	items := similarItems(200)
+
+	// One item at a time, as titles are discovered
+	sequential := &Engine{MinSimilarity: 0.6, similarityWorkers: 1}
+	for _, i := range items {
+		sequential.updateSimilarityTables([]similarItem{i})
+	}
+
+	parallel := &Engine{MinSimilarity: 0.6, similarityWorkers: 8}
+	parallel.updateSimilarityTables(items[:50])
+	parallel.updateSimilarityTables(items[50:])
+
+	want := similarTable(sequential)
+	found := 0
+	for _, v := range want {
+		found += len(v)
+	}
+	assert.NotZero(t, found)
+	assert.Equal(t, want, similarTable(parallel))
+}
+
+func BenchmarkUpdateSimilarityTables(b *testing.B) {
+	items := similarItems(2000)
+	for _, workers := range []int{1, 4, 0} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				h := &Engine{MinSimilarity: 0.75, similarityWorkers: workers}
+				h.updateSimilarityTables(items)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubbub
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/triage-party/pkg/provider"
+	"k8s.io/klog/v2"
+)
+
+// sizeBuckets are the upper bounds of lines changed for each size label, as used by the Kubernetes size plugin
+var sizeBuckets = []struct {
+	name string
+	max  int
+}{
+	{"XS", 10},
+	{"S", 30},
+	{"M", 100},
+	{"L", 500},
+	{"XL", 1000},
+}
+
+// SizeBucket returns the size label for a number of lines changed: XS, S, M, L, XL, or XXL
+func SizeBucket(lines int) string {
+	for _, b := range sizeBuckets {
+		if lines < b.max {
+			return b.name
+		}
+	}
+	return "XXL"
+}
+
+// isSizeBucket returns whether a string is a size label
+func isSizeBucket(s string) bool {
+	s = strings.ToUpper(s)
+	if s == "XXL" {
+		return true
+	}
+	for _, b := range sizeBuckets {
+		if s == b.name {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidSize returns whether a size filter is a size label or a numeric range
+func ValidSize(s string) bool {
+	return isSizeBucket(s) || rangeRegexp.FindString(s) == s
+}
+
+// matchSize returns whether the number of lines changed matches a size label or numeric range
+func matchSize(lines int, s string) bool {
+	if isSizeBucket(s) {
+		return strings.EqualFold(SizeBucket(lines), s)
+	}
+	return matchRange(float64(lines), s)
+}
+
+// needSize returns true if the filters need the size of a PR
+func needSize(fs []provider.Filter) bool {
+	for _, f := range fs {
+		if f.Size != "" || f.ChangedFiles != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// addSize adds the additions, deletions, and changed file counts of a PR to a conversation, if necessary.
+// Counts are cached by head SHA, as they only change when new commits are pushed.
+func (h *Engine) addSize(ctx context.Context, sp provider.SearchParams, co *Conversation, pr *provider.PullRequest) {
+	if !needSize(sp.Filters) {
+		return
+	}
+
+	key := ""
+	if sha := pr.GetHead().GetSHA(); sha != "" {
+		key = fmt.Sprintf("%s-%s-%s-size", sp.Repo.Organization, sp.Repo.Project, sha)
+		if x := h.cache.GetNewerThan(key, time.Time{}); x != nil {
+			co.Additions = x.Counts["additions"]
+			co.Deletions = x.Counts["deletions"]
+			co.ChangedFiles = x.Counts["changed_files"]
+			return
+		}
+	}
+
+	sp.IssueNumber = pr.GetNumber()
+	sp.NewerThan = h.mtime(pr)
+	sp.Fetch = true
+
+	detail, _, err := h.cachedPR(ctx, sp)
+	if err != nil {
+		klog.Errorf("pr #%d: %v", pr.GetNumber(), err)
+		return
+	}
+
+	co.Additions = detail.GetAdditions()
+	co.Deletions = detail.GetDeletions()
+	co.ChangedFiles = detail.GetChangedFiles()
+
+	if key == "" {
+		return
+	}
+
+	counts := map[string]int{"additions": co.Additions, "deletions": co.Deletions, "changed_files": co.ChangedFiles}
+	if err := h.cache.Set(key, &provider.Thing{Counts: counts}); err != nil {
+		klog.Errorf("set %q failed: %v", key, err)
+	}
+}
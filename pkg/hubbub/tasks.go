@@ -0,0 +1,75 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubbub
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/google/triage-party/pkg/provider"
+)
+
+// taskRe matches a markdown task list item, such as "- [x] write docs"
+var taskRe = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+\[([ xX])\](?:\s|$)`)
+
+// countTasks returns the number of checked and unchecked task list items within a markdown body, ignoring code blocks
+func countTasks(body string) (done int, remaining int) {
+	fenced := false
+	for _, l := range strings.Split(body, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(l), "```") {
+			fenced = !fenced
+			continue
+		}
+		if fenced {
+			continue
+		}
+
+		m := taskRe.FindStringSubmatch(l)
+		if m == nil {
+			continue
+		}
+		if m[1] == " " {
+			remaining++
+		} else {
+			done++
+		}
+	}
+	return done, remaining
+}
+
+// matchTasks returns whether a body matches the tasks-complete and tasks-remaining filters
+func matchTasks(body string, f provider.Filter) bool {
+	done, remaining := countTasks(body)
+
+	if f.TasksComplete != "" {
+		// Items without a task list are neither complete nor incomplete
+		if done+remaining == 0 {
+			return false
+		}
+		if (remaining == 0) != (f.TasksComplete == "true") {
+			return false
+		}
+	}
+
+	if f.TasksRemaining != "" && !matchRange(float64(remaining), f.TasksRemaining) {
+		return false
+	}
+	return true
+}
+
+// ValidRange returns whether a filter value is a numeric range, such as >0
+func ValidRange(s string) bool {
+	return rangeRegexp.FindString(s) == s
+}
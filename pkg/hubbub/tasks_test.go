@@ -0,0 +1,34 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubbub
+
+import (
+	"testing"
+
+	"github.com/google/triage-party/pkg/provider"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountTasks(t *testing.T) {
+	body := "Done when:\n\n- [x] parser\n* [X] docs\n1. [ ] tests\n  - [ ] nested\n- [] not a task\n```\n- [ ] example\n```\n"
+	done, remaining := countTasks(body)
+	assert.Equal(t, 2, done)
+	assert.Equal(t, 2, remaining)
+
+	assert.True(t, matchTasks("- [x] a\n- [x] b", provider.Filter{TasksComplete: "true"}))
+	assert.False(t, matchTasks("- [x] a\n- [ ] b", provider.Filter{TasksComplete: "true"}))
+	assert.True(t, matchTasks("- [x] a\n- [ ] b", provider.Filter{TasksComplete: "false", TasksRemaining: ">0"}))
+	assert.False(t, matchTasks("no tasks", provider.Filter{TasksComplete: "false"}))
+}
@@ -0,0 +1,143 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubbub
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/triage-party/pkg/provider"
+	"k8s.io/klog/v2"
+)
+
+// teamMaxAge is how long team memberships are cached for
+const teamMaxAge = time.Hour
+
+// parseTeam returns the organization and slug for a team reference, such as "org/team" or "@org/team"
+func parseTeam(s string) (string, string, bool) {
+	parts := strings.SplitN(strings.TrimPrefix(s, "@"), "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// teamMembers returns the lowercased logins of a team's members, including members of child teams
+func (h *Engine) teamMembers(ctx context.Context, sp provider.SearchParams, org string, slug string) (map[string]bool, error) {
+	key := fmt.Sprintf("team-%s-%s-members", strings.ToLower(org), strings.ToLower(slug))
+	if x := h.cache.GetNewerThan(key, h.now().Add(-teamMaxAge)); x != nil {
+		return x.StringBool, nil
+	}
+
+	klog.Infof("Downloading members of %s/%s", org, slug)
+	sp.Repo.Organization = org
+	sp.ListOptions = provider.ListOptions{PerPage: 100}
+
+	members := map[string]bool{}
+	for {
+		us, resp, err := h.provider(sp.Repo.Host).TeamsListMembers(ctx, sp, slug)
+		if err != nil {
+			return nil, fmt.Errorf("team %s/%s: %w", org, slug, err)
+		}
+		h.logRate(resp.Rate)
+
+		for _, u := range us {
+			members[strings.ToLower(u.GetLogin())] = true
+		}
+
+		if resp.NextPage == 0 || sp.ListOptions.Page == resp.NextPage {
+			break
+		}
+		sp.ListOptions.Page = resp.NextPage
+	}
+
+	if err := h.cache.Set(key, &provider.Thing{StringBool: members}); err != nil {
+		klog.Errorf("set %q failed: %v", key, err)
+	}
+	return members, nil
+}
+
+// matchUsers returns true if any user is the given login, or a member of the given team
+func (h *Engine) matchUsers(ctx context.Context, sp provider.SearchParams, us []*provider.User, who string) bool {
+	org, slug, isTeam := parseTeam(who)
+	if !isTeam {
+		for _, u := range us {
+			if strings.EqualFold(u.GetLogin(), strings.TrimPrefix(who, "@")) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(us) == 0 {
+		return false
+	}
+
+	members, err := h.teamMembers(ctx, sp, org, slug)
+	if err != nil {
+		klog.Errorf("unable to match %q: %v", who, err)
+		return false
+	}
+
+	for _, u := range us {
+		if members[strings.ToLower(u.GetLogin())] {
+			return true
+		}
+	}
+	return false
+}
+
+// peopleMatch checks the assignee and reviewer filters, which may refer to teams
+func (h *Engine) peopleMatch(ctx context.Context, sp provider.SearchParams, co *Conversation) bool {
+	for _, f := range sp.Filters {
+		// "none" is checked before fetching
+		none, _ := f.AssigneeNone()
+		if f.Assignee != "" && !none && !h.matchUsers(ctx, sp, co.Assignees, f.Assignee) {
+			klog.V(2).Infof("#%d did not pass assignee: %q", co.ID, f.Assignee)
+			return false
+		}
+
+		if f.Reviewer == "" {
+			continue
+		}
+
+		requested := false
+		for _, t := range co.RequestedTeams {
+			if strings.EqualFold(t, strings.TrimPrefix(f.Reviewer, "@")) {
+				requested = true
+			}
+		}
+
+		reviewers := append(append([]*provider.User{}, co.RequestedReviewers...), co.Reviewers...)
+		if !requested && !h.matchUsers(ctx, sp, reviewers, f.Reviewer) {
+			klog.V(2).Infof("#%d did not pass reviewer: %q", co.ID, f.Reviewer)
+			return false
+		}
+	}
+	return true
+}
+
+// UserMatches returns true if login is one of who: a login, or a team such as "org/team"
+func (h *Engine) UserMatches(ctx context.Context, login string, who []string) bool {
+	us := []*provider.User{{Login: &login}}
+	for _, w := range who {
+		if h.matchUsers(ctx, provider.SearchParams{}, us, w) {
+			return true
+		}
+	}
+	return false
+}
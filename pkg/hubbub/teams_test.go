@@ -0,0 +1,105 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hubbub
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/triage-party/pkg/persist"
+	"github.com/google/triage-party/pkg/provider"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeopleMatch(t *testing.T) {
+	login := func(s string) *provider.User { return &provider.User{Login: &s} }
+	h := &Engine{}
+	co := &Conversation{
+		Assignees:          []*provider.User{login("alice")},
+		RequestedReviewers: []*provider.User{login("bob")},
+		RequestedTeams:     []string{"org/platform"},
+	}
+
+	tests := []struct {
+		f    provider.Filter
+		want bool
+	}{
+		{provider.Filter{Assignee: "alice"}, true},
+		{provider.Filter{Assignee: "@Alice"}, true},
+		{provider.Filter{Assignee: "bob"}, false},
+		{provider.Filter{Reviewer: "bob"}, true},
+		{provider.Filter{Reviewer: "@org/platform"}, true},
+		{provider.Filter{Reviewer: "carol"}, false},
+	}
+
+	for _, tc := range tests {
+		sp := provider.SearchParams{Filters: []provider.Filter{tc.f}}
+		assert.Equal(t, tc.want, h.peopleMatch(context.Background(), sp, co), "%+v", tc.f)
+	}
+}
+
+// teamProvider serves team members over two pages, counting requests
+type teamProvider struct {
+	provider.Provider
+	requests int
+}
+
+func (p *teamProvider) TeamsListMembers(_ context.Context, sp provider.SearchParams, slug string) ([]*provider.User, *provider.Response, error) {
+	p.requests++
+	if slug != "platform" {
+		return nil, nil, errors.New("not found")
+	}
+
+	login := func(s string) *provider.User { return &provider.User{Login: &s} }
+	if sp.ListOptions.Page == 0 {
+		return []*provider.User{login("Alice")}, &provider.Response{NextPage: 2}, nil
+	}
+	// Members of child teams are listed by GitHub alongside direct members
+	return []*provider.User{login("dave")}, &provider.Response{}, nil
+}
+
+func TestTeamMembership(t *testing.T) {
+	c, err := persist.NewMemory(persist.Config{})
+	if err != nil {
+		t.Fatalf("memory: %v", err)
+	}
+	assert.Nil(t, c.Initialize())
+
+	p := &teamProvider{Provider: provider.Offline()}
+	h := New(Config{Cache: c, GitHub: p})
+	ctx := context.Background()
+
+	login := func(s string) *provider.User { return &provider.User{Login: &s} }
+	co := &Conversation{
+		Assignees: []*provider.User{login("alice")},
+		Reviewers: []*provider.User{login("dave")},
+	}
+
+	match := func(f provider.Filter) bool {
+		return h.peopleMatch(ctx, provider.SearchParams{Filters: []provider.Filter{f}}, co)
+	}
+
+	assert.True(t, match(provider.Filter{Assignee: "@org/platform"}))
+	assert.True(t, match(provider.Filter{Reviewer: "org/platform"}))
+	assert.False(t, match(provider.Filter{Assignee: "org/security"}))
+	assert.False(t, h.peopleMatch(ctx, provider.SearchParams{Filters: []provider.Filter{{Assignee: "org/platform"}}}, &Conversation{}))
+
+	// Both pages were fetched once, then served from the cache; the unknown team was requested once
+	assert.Equal(t, 3, p.requests)
+	assert.True(t, h.UserMatches(ctx, "DAVE", []string{"bob", "org/platform"}))
+	assert.False(t, h.UserMatches(ctx, "carol", []string{"org/platform"}))
+	assert.Equal(t, 3, p.requests)
+}
@@ -97,10 +97,24 @@ func (h *Engine) addEvents(ctx context.Context, sp provider.SearchParams, co *Co
 			klog.Errorf("debug timeline event %q: %s", t.GetEvent(), formatStruct(t))
 		}
 
+		h.humanActivity(co, t.GetActor(), t.GetCreatedAt())
+
 		if t.GetEvent() == "labeled" && t.GetLabel().GetName() == priority {
 			co.Prioritized = t.GetCreatedAt()
 		}
 
+		if (t.GetEvent() == "closed" || t.GetEvent() == "reopened") && t.GetCreatedAt().After(co.StateChanged) {
+			co.StateChanged = t.GetCreatedAt()
+		}
+
+		if t.GetEvent() == "reopened" && t.GetCreatedAt().After(co.Reopened) {
+			co.Reopened = t.GetCreatedAt()
+		}
+
+		if t.GetEvent() == "converted_to_discussion" {
+			co.ConvertedToDiscussion = true
+		}
+
 		if t.GetEvent() == "cross-referenced" {
 			if assignedTo[t.GetActor().GetLogin()] {
 				if t.GetCreatedAt().After(co.LatestAssigneeResponse) {
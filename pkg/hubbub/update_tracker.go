@@ -42,7 +42,9 @@ func (h *Engine) mtimeRef(rc *RelatedConversation) time.Time {
 
 func (h *Engine) mtimeKey(idea time.Time, key string) time.Time {
 	updatedAt := idea
+	h.updatedAtMu.RLock()
 	updateSeen := h.updatedAt[key]
+	h.updatedAtMu.RUnlock()
 	klog.V(2).Infof("%s was definitely updated by %s - possibly by %s", key, updatedAt, updateSeen)
 
 	if updateSeen == updatedAt {
@@ -86,6 +88,9 @@ func (h *Engine) updateMtimeLong(org string, project string, num int, t time.Tim
 }
 
 func (h *Engine) updateMtimeByKey(key string, ts time.Time) {
+	h.updatedAtMu.Lock()
+	defer h.updatedAtMu.Unlock()
+
 	if ts.After(h.updatedAt[key]) {
 		if !h.updatedAt[key].IsZero() {
 			_, file, no, ok := runtime.Caller(2)
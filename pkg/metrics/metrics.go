@@ -0,0 +1,99 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics records operational metrics, such as refresh durations and API usage, and
+// forwards them to any configured sinks.
+package metrics
+
+import (
+	"sync"
+	"time"
+)
+
+// Metric names shared by all sinks
+const (
+	// RefreshDuration is how long a collection took to refresh (timing, tagged by collection)
+	RefreshDuration = "refresh_duration"
+	// RefreshRuns counts collection refreshes (counter, tagged by collection)
+	RefreshRuns = "refresh_runs"
+	// RefreshFailures counts failed collection refreshes (counter, tagged by collection)
+	RefreshFailures = "refresh_failures"
+	// CollectionSize is the number of items in a collection (gauge, tagged by collection)
+	CollectionSize = "collection_size"
+	// APICalls counts calls made to GitHub or GitLab (counter, tagged by call)
+	APICalls = "api_calls"
+	// APIErrors counts failed calls to GitHub or GitLab (counter, tagged by call and class)
+	APIErrors = "api_errors"
+	// RateLimitRemaining is the remaining hourly GitHub API quota (gauge)
+	RateLimitRemaining = "rate_limit_remaining"
+	// RateLimitReset is when the GitHub API quota resets, in seconds since the epoch (gauge)
+	RateLimitReset = "rate_limit_reset"
+	// LastRefresh is when every collection last refreshed without error, in seconds since the epoch (gauge)
+	LastRefresh = "last_refresh_timestamp"
+	// CacheHits counts lookups answered by the cache (counter)
+	CacheHits = "cache_hits"
+	// CacheMisses counts lookups which were missing or too old within the cache (counter)
+	CacheMisses = "cache_misses"
+)
+
+// Sink receives metrics. Tags are in "key:value" form.
+type Sink interface {
+	Gauge(name string, value float64, tags ...string)
+	Count(name string, delta int64, tags ...string)
+	Timing(name string, d time.Duration, tags ...string)
+}
+
+var (
+	sinksMu sync.RWMutex
+	sinks   []Sink
+)
+
+// AddSink registers a sink to receive all future metrics
+func AddSink(s Sink) {
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+	sinks = append(sinks, s)
+}
+
+// Gauge records the current value of a metric
+func Gauge(name string, value float64, tags ...string) {
+	sinksMu.RLock()
+	defer sinksMu.RUnlock()
+	for _, s := range sinks {
+		s.Gauge(name, value, tags...)
+	}
+}
+
+// Count increments a counter
+func Count(name string, delta int64, tags ...string) {
+	sinksMu.RLock()
+	defer sinksMu.RUnlock()
+	for _, s := range sinks {
+		s.Count(name, delta, tags...)
+	}
+}
+
+// Timing records a duration
+func Timing(name string, d time.Duration, tags ...string) {
+	sinksMu.RLock()
+	defer sinksMu.RUnlock()
+	for _, s := range sinks {
+		s.Timing(name, d, tags...)
+	}
+}
+
+// Tag formats a tag for use with a sink
+func Tag(key string, value string) string {
+	return key + ":" + value
+}
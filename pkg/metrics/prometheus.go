@@ -0,0 +1,190 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// promBuckets are the histogram bucket bounds for timings, in seconds
+var promBuckets = []float64{0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600}
+
+// promHistogram is a cumulative histogram of durations
+type promHistogram struct {
+	buckets []uint64
+	count   uint64
+	sum     float64
+}
+
+// Prometheus keeps the latest value of each metric, and serves them in the Prometheus text format.
+// Counters gain a _total suffix, and timings become histograms with a _seconds suffix.
+type Prometheus struct {
+	prefix string
+
+	mu         sync.Mutex
+	gauges     map[string]map[string]float64
+	counters   map[string]map[string]float64
+	histograms map[string]map[string]*promHistogram
+}
+
+// NewPrometheus returns a Prometheus sink, whose metric names start with prefix
+func NewPrometheus(prefix string) *Prometheus {
+	return &Prometheus{
+		prefix:     prefix,
+		gauges:     map[string]map[string]float64{},
+		counters:   map[string]map[string]float64{},
+		histograms: map[string]map[string]*promHistogram{},
+	}
+}
+
+// promLabels formats "key:value" tags as sorted Prometheus labels
+func promLabels(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+
+	ls := []string{}
+	for _, t := range tags {
+		kv := strings.SplitN(t, ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		ls = append(ls, fmt.Sprintf("%s=%s", kv[0], strconv.Quote(kv[1])))
+	}
+	sort.Strings(ls)
+	return "{" + strings.Join(ls, ",") + "}"
+}
+
+// Gauge sets the value of a gauge
+func (p *Prometheus) Gauge(name string, value float64, tags ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	name = p.prefix + name
+	if p.gauges[name] == nil {
+		p.gauges[name] = map[string]float64{}
+	}
+	p.gauges[name][promLabels(tags)] = value
+}
+
+// Count increments a counter
+func (p *Prometheus) Count(name string, delta int64, tags ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	name = p.prefix + name + "_total"
+	if p.counters[name] == nil {
+		p.counters[name] = map[string]float64{}
+	}
+	p.counters[name][promLabels(tags)] += float64(delta)
+}
+
+// Timing records a duration within a histogram
+func (p *Prometheus) Timing(name string, d time.Duration, tags ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	name = p.prefix + name + "_seconds"
+	if p.histograms[name] == nil {
+		p.histograms[name] = map[string]*promHistogram{}
+	}
+	labels := promLabels(tags)
+	h := p.histograms[name][labels]
+	if h == nil {
+		h = &promHistogram{buckets: make([]uint64, len(promBuckets))}
+		p.histograms[name][labels] = h
+	}
+
+	secs := d.Seconds()
+	for i, b := range promBuckets {
+		if secs <= b {
+			h.buckets[i]++
+		}
+	}
+	h.count++
+	h.sum += secs
+}
+
+// withLabel adds a label to a formatted label set
+func withLabel(labels string, label string) string {
+	if labels == "" {
+		return "{" + label + "}"
+	}
+	return labels[:len(labels)-1] + "," + label + "}"
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys(m interface{}) []string {
+	keys := []string{}
+	switch v := m.(type) {
+	case map[string]float64:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	case map[string]*promHistogram:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	case map[string]map[string]float64:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	case map[string]map[string]*promHistogram:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ServeHTTP writes every metric in the Prometheus text format
+func (p *Prometheus) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	for _, kind := range []struct {
+		typ    string
+		values map[string]map[string]float64
+	}{{"gauge", p.gauges}, {"counter", p.counters}} {
+		for _, name := range sortedKeys(kind.values) {
+			fmt.Fprintf(w, "# TYPE %s %s\n", name, kind.typ)
+			for _, labels := range sortedKeys(kind.values[name]) {
+				fmt.Fprintf(w, "%s%s %s\n", name, labels, strconv.FormatFloat(kind.values[name][labels], 'g', -1, 64))
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(p.histograms) {
+		fmt.Fprintf(w, "# TYPE %s histogram\n", name)
+		for _, labels := range sortedKeys(p.histograms[name]) {
+			h := p.histograms[name][labels]
+			for i, b := range promBuckets {
+				fmt.Fprintf(w, "%s_bucket%s %d\n", name, withLabel(labels, fmt.Sprintf("le=%q", strconv.FormatFloat(b, 'g', -1, 64))), h.buckets[i])
+			}
+			fmt.Fprintf(w, "%s_bucket%s %d\n", name, withLabel(labels, `le="+Inf"`), h.count)
+			fmt.Fprintf(w, "%s_sum%s %s\n", name, labels, strconv.FormatFloat(h.sum, 'g', -1, 64))
+			fmt.Fprintf(w, "%s_count%s %d\n", name, labels, h.count)
+		}
+	}
+}
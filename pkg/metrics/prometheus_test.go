@@ -0,0 +1,43 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrometheus(t *testing.T) {
+	p := NewPrometheus("tp_")
+	p.Gauge(RateLimitRemaining, 4200)
+	p.Count(RefreshFailures, 1, Tag("collection", "daily"))
+	p.Count(RefreshFailures, 2, Tag("collection", "daily"))
+	p.Timing(RefreshDuration, 3*time.Second, Tag("collection", "daily"))
+
+	w := httptest.NewRecorder()
+	p.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	body := w.Body.String()
+
+	assert.Contains(t, body, "# TYPE tp_rate_limit_remaining gauge\ntp_rate_limit_remaining 4200\n")
+	assert.Contains(t, body, "# TYPE tp_refresh_failures_total counter\ntp_refresh_failures_total{collection=\"daily\"} 3\n")
+	assert.Contains(t, body, "# TYPE tp_refresh_duration_seconds histogram\n")
+	assert.Contains(t, body, "tp_refresh_duration_seconds_bucket{collection=\"daily\",le=\"2.5\"} 0\n")
+	assert.Contains(t, body, "tp_refresh_duration_seconds_bucket{collection=\"daily\",le=\"5\"} 1\n")
+	assert.Contains(t, body, "tp_refresh_duration_seconds_bucket{collection=\"daily\",le=\"+Inf\"} 1\n")
+	assert.Contains(t, body, "tp_refresh_duration_seconds_sum{collection=\"daily\"} 3\n")
+}
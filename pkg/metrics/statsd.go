@@ -0,0 +1,72 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// StatsD sends metrics over UDP using the StatsD line protocol, with Datadog-style tags
+type StatsD struct {
+	conn   net.Conn
+	prefix string
+}
+
+// NewStatsD returns a StatsD sink for a host:port address
+func NewStatsD(addr string, prefix string) (*StatsD, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("dial %s: %w", addr, err)
+	}
+	return &StatsD{conn: conn, prefix: prefix}, nil
+}
+
+// Gauge sends a gauge value
+func (s *StatsD) Gauge(name string, value float64, tags ...string) {
+	s.send(name, strconv.FormatFloat(value, 'f', -1, 64), "g", tags)
+}
+
+// Count sends a counter increment
+func (s *StatsD) Count(name string, delta int64, tags ...string) {
+	s.send(name, strconv.FormatInt(delta, 10), "c", tags)
+}
+
+// Timing sends a duration in milliseconds
+func (s *StatsD) Timing(name string, d time.Duration, tags ...string) {
+	s.send(name, strconv.FormatInt(d.Milliseconds(), 10), "ms", tags)
+}
+
+// Close closes the underlying connection
+func (s *StatsD) Close() error {
+	return s.conn.Close()
+}
+
+func (s *StatsD) send(name string, value string, kind string, tags []string) {
+	line := fmt.Sprintf("%s%s:%s|%s", s.prefix, name, value, kind)
+	if len(tags) > 0 {
+		line += "|#" + strings.Join(tags, ",")
+	}
+
+	// UDP is fire-and-forget: a missing agent should never affect triage
+	if _, err := s.conn.Write([]byte(line)); err != nil {
+		klog.V(1).Infof("statsd write %q: %v", line, err)
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatsD(t *testing.T) {
+	l, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer l.Close()
+
+	s, err := NewStatsD(l.LocalAddr().String(), "tp.")
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	defer s.Close()
+
+	tests := []struct {
+		send func()
+		want string
+	}{
+		{func() { s.Gauge(CollectionSize, 42, Tag("collection", "daily")) }, "tp.collection_size:42|g|#collection:daily"},
+		{func() { s.Count(APICalls, 1) }, "tp.api_calls:1|c"},
+		{func() { s.Timing(RefreshDuration, 1500*time.Millisecond, "a:b", "c:d") }, "tp.refresh_duration:1500|ms|#a:b,c:d"},
+	}
+
+	buf := make([]byte, 1024)
+	for _, tc := range tests {
+		tc.send()
+		if err := l.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+			t.Fatalf("deadline: %v", err)
+		}
+		n, _, err := l.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		assert.Equal(t, tc.want, string(buf[:n]))
+	}
+}
@@ -17,14 +17,13 @@ package persist
 
 import (
 	"bufio"
-	"bytes"
 	"encoding/gob"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
-	"strings"
 	"time"
 
 	"github.com/google/triage-party/pkg/provider"
@@ -35,7 +34,21 @@ import (
 
 type Disk struct {
 	path  string
-	cache *cache.Cache
+	cache *memCache
+}
+
+// diskFormat is the version of the streamed disk format, written as a header before the records
+const diskFormat = 1
+
+// diskHeader precedes the records within a streamed cache file
+type diskHeader struct {
+	Format int
+}
+
+// diskRecord is a single cache entry within a streamed cache file
+type diskRecord struct {
+	Key  string
+	Item cache.Item
 }
 
 // NewDisk returns a new disk cache
@@ -47,10 +60,16 @@ func (d *Disk) String() string {
 	return d.path
 }
 
+// Initialize loads the cache file. Missing or unreadable files, such as one torn by a
+// crash in an older release, are replaced by an empty cache for the next refresh to fill.
 func (d *Disk) Initialize() error {
 	klog.Infof("Initializing with %s ...", d.path)
 	if err := d.load(); err != nil {
-		klog.Infof("recreating cache due to load error: %v", err)
+		if _, serr := os.Stat(d.path); serr == nil {
+			klog.Warningf("discarding unreadable cache %s, starting empty: %v", d.path, err)
+		} else {
+			klog.Infof("recreating cache due to load error: %v", err)
+		}
 		d.cache = createMem()
 		if err := d.Cleanup(); err != nil {
 			return fmt.Errorf("save: %w", err)
@@ -60,18 +79,9 @@ func (d *Disk) Initialize() error {
 }
 
 func (d *Disk) load() error {
-	f, err := os.Open(d.path)
+	decoded, _, err := readDisk(d.path)
 	if err != nil {
-		return fmt.Errorf("open: %w", err)
-	}
-	defer f.Close()
-
-	decoded := map[string]cache.Item{}
-	gd := gob.NewDecoder(bufio.NewReader(f))
-
-	err = gd.Decode(&decoded)
-	if err != nil && err != io.EOF {
-		return fmt.Errorf("decode failed: %w", err)
+		return err
 	}
 
 	if len(decoded) == 0 {
@@ -83,6 +93,80 @@ func (d *Disk) load() error {
 	return nil
 }
 
+// readDisk decodes a cache file in either format, returning its format version (0 for legacy files)
+func readDisk(path string) (decoded map[string]cache.Item, format int, err error) {
+	// gob may panic rather than return an error on some malformed input
+	defer func() {
+		if r := recover(); r != nil {
+			decoded, format, err = nil, 0, fmt.Errorf("decode panic: %v", r)
+		}
+	}()
+
+	decoded, err = loadStream(path)
+	if errors.Is(err, errLegacyFormat) {
+		klog.Infof("%s predates the streamed format, loading it whole", path)
+		decoded, err = loadLegacy(path)
+		return decoded, 0, err
+	}
+	return decoded, diskFormat, err
+}
+
+// errLegacyFormat is returned when a cache file was written as a single map, before records were streamed
+var errLegacyFormat = errors.New("legacy format")
+
+// loadStream decodes a header followed by one record at a time
+func loadStream(path string) (map[string]cache.Item, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open: %w", err)
+	}
+	defer f.Close()
+
+	gd := gob.NewDecoder(bufio.NewReader(f))
+
+	var h diskHeader
+	if err := gd.Decode(&h); err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+		return nil, errLegacyFormat
+	}
+	if h.Format != diskFormat {
+		return nil, fmt.Errorf("unknown disk format %d", h.Format)
+	}
+
+	decoded := map[string]cache.Item{}
+	for {
+		var r diskRecord
+		err := gd.Decode(&r)
+		if err == io.EOF {
+			return decoded, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("decode record %d: %w", len(decoded), err)
+		}
+		decoded[r.Key] = r.Item
+	}
+}
+
+// loadLegacy decodes a cache file written as a single map
+func loadLegacy(path string) (map[string]cache.Item, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open: %w", err)
+	}
+	defer f.Close()
+
+	decoded := map[string]cache.Item{}
+	gd := gob.NewDecoder(bufio.NewReader(f))
+
+	err = gd.Decode(&decoded)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("decode failed: %w", err)
+	}
+	return decoded, nil
+}
+
 // Set stores a thing into memory
 func (d *Disk) Set(key string, t *provider.Thing) error {
 	setMem(d.cache, key, t)
@@ -96,26 +180,70 @@ func (d *Disk) DeleteOlderThan(key string, t time.Time) error {
 	return nil
 }
 
+// DeletePrefix deletes every thing with a key starting with a prefix, except keys starting with an exception
+func (d *Disk) DeletePrefix(prefix string, except ...string) (int, error) {
+	return deletePrefixMem(d.cache, prefix, except), nil
+}
+
 // GetNewerThan returns a thing older than a timestamp
 func (d *Disk) GetNewerThan(key string, t time.Time) *provider.Thing {
 	return newerThanMem(d.cache, key, t)
 }
 
+// Cleanup streams each item to a temporary file, which then replaces the cache file
 func (d *Disk) Cleanup() error {
-	items := d.cache.Items()
+	items := snapshotMem(d.cache)
 	klog.Infof("*** Saving %d items to disk cache at %s", len(items), d.path)
 
-	b := new(bytes.Buffer)
-	ge := gob.NewEncoder(b)
-	if err := ge.Encode(items); err != nil {
-		return fmt.Errorf("encode: %w", err)
+	if err := os.MkdirAll(filepath.Dir(d.path), 0o700); err != nil {
+		return err
 	}
 
-	if err := os.MkdirAll(filepath.Dir(d.path), 0o700); err != nil {
+	f, err := ioutil.TempFile(filepath.Dir(d.path), filepath.Base(d.path)+".tmp")
+	if err != nil {
+		return fmt.Errorf("create: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	if err := writeRecords(f, items); err != nil {
+		f.Close()
 		return err
 	}
 
-	return ioutil.WriteFile(d.path, b.Bytes(), 0o644)
+	// Flush to disk before the rename, so that a crash leaves either the old or new file whole
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("sync: %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("close: %w", err)
+	}
+
+	if err := os.Chmod(f.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), d.path)
+}
+
+// writeRecords writes a header, then each item as its own record, redacting bodies one item at a time
+func writeRecords(w io.Writer, items map[string]cache.Item) error {
+	bw := bufio.NewWriter(w)
+	ge := gob.NewEncoder(bw)
+
+	if err := ge.Encode(diskHeader{Format: diskFormat}); err != nil {
+		return fmt.Errorf("encode header: %w", err)
+	}
+
+	for k, it := range items {
+		if th, ok := it.Object.(*provider.Thing); ok {
+			it.Object = redactThing(th)
+		}
+		if err := ge.Encode(diskRecord{Key: k, Item: it}); err != nil {
+			return fmt.Errorf("encode %s: %w", k, err)
+		}
+	}
+	return bw.Flush()
 }
 
 func findCacheRoot() string {
@@ -140,15 +268,3 @@ func findCacheRoot() string {
 
 	return filepath.Join(cdir, "triage-party")
 }
-
-func DefaultDiskPath(configPath string, override string) string {
-	name := filepath.Base(configPath)
-	if override != "" {
-		name = name + "_" + strings.Replace(override, "/", "_", -1)
-	}
-
-	dir := findCacheRoot()
-	path := filepath.Join(dir, name+".pc")
-	klog.Infof("default disk path: %s", path)
-	return path
-}
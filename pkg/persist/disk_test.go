@@ -0,0 +1,125 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package persist
+
+import (
+	"encoding/gob"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/triage-party/pkg/provider"
+	"github.com/patrickmn/go-cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiskRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "disk")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	cfg := Config{Type: "disk", Path: filepath.Join(dir, "cache", "test.pc")}
+	c, err := New(cfg)
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	assert.Nil(t, c.Initialize())
+
+	title := "streamed"
+	for _, k := range []string{"a", "b", "c"} {
+		assert.Nil(t, c.Set(k, &provider.Thing{Issues: []*provider.Issue{{Title: &title}}}))
+	}
+	assert.Nil(t, c.Cleanup())
+
+	loaded := &Disk{path: cfg.Path}
+	assert.Nil(t, loaded.load())
+	assert.Equal(t, 3, loaded.cache.ItemCount())
+	assert.Equal(t, title, loaded.GetNewerThan("b", time.Time{}).Issues[0].GetTitle())
+}
+
+func TestDiskInitializeCorrupt(t *testing.T) {
+	dir, err := ioutil.TempDir("", "disk")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "torn.pc")
+	d := &Disk{path: path, cache: createMem()}
+	for _, k := range []string{"a", "b", "c"} {
+		assert.Nil(t, d.Set(k, &provider.Thing{Created: time.Now()}))
+	}
+	assert.Nil(t, d.Cleanup())
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	assert.Nil(t, os.Truncate(path, fi.Size()/2))
+
+	c := &Disk{path: path}
+	assert.Nil(t, c.Initialize())
+	assert.Equal(t, 0, c.cache.ItemCount())
+
+	// The torn file was replaced by a readable, empty cache
+	_, _, err = readDisk(path)
+	assert.Nil(t, err)
+}
+
+func TestDiskLoadLegacy(t *testing.T) {
+	dir, err := ioutil.TempDir("", "disk")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	gob.Register(&provider.Thing{})
+	path := filepath.Join(dir, "legacy.pc")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	items := map[string]cache.Item{"old": {Object: &provider.Thing{Created: time.Now()}, Expiration: time.Now().Add(time.Hour).UnixNano()}}
+	assert.Nil(t, gob.NewEncoder(f).Encode(items))
+	f.Close()
+
+	d := &Disk{path: path}
+	assert.Nil(t, d.load())
+	assert.NotNil(t, d.GetNewerThan("old", time.Time{}))
+}
+
+func TestDeletePrefix(t *testing.T) {
+	d := &Disk{cache: createMem()}
+	for _, k := range []string{"org-a-1-timeline", "org-a-open-issues", "org-ab-1-timeline", "team-org-x-members"} {
+		assert.Nil(t, d.Set(k, &provider.Thing{}))
+	}
+
+	n, err := d.DeletePrefix("org-a-")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+	assert.Nil(t, d.GetNewerThan("org-a-1-timeline", time.Time{}))
+	assert.NotNil(t, d.GetNewerThan("org-ab-1-timeline", time.Time{}))
+
+	n, err = d.DeletePrefix("org-", "org-ab-")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, n)
+	assert.NotNil(t, d.GetNewerThan("org-ab-1-timeline", time.Time{}))
+
+	assert.Equal(t, `org\_a-%`, likePrefix("org_a-"))
+}
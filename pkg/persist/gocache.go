@@ -15,8 +15,11 @@
 package persist
 
 import (
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/google/triage-party/pkg/metrics"
 	"github.com/google/triage-party/pkg/provider"
 
 	"github.com/patrickmn/go-cache"
@@ -25,11 +28,18 @@ import (
 
 var memCleanupInterval = 15 * time.Minute
 
-func createMem() *cache.Cache {
-	return cache.New(MaxLoadAge, memCleanupInterval)
+// memCache is the in-memory cache behind every backend. go-cache guards single calls, but
+// mu keeps lookups-then-deletes, prefix deletes, and snapshots for saving consistent.
+type memCache struct {
+	*cache.Cache
+	mu sync.RWMutex
 }
 
-func loadMem(items map[string]cache.Item) *cache.Cache {
+func createMem() *memCache {
+	return &memCache{Cache: cache.New(MaxLoadAge, memCleanupInterval)}
+}
+
+func loadMem(items map[string]cache.Item) *memCache {
 	for key, v := range items {
 		th, ok := v.Object.(*provider.Thing)
 		if !ok {
@@ -38,19 +48,43 @@ func loadMem(items map[string]cache.Item) *cache.Cache {
 			klog.Infof("found %s (created: %s)", key, th.Created)
 		}
 	}
-	return cache.NewFrom(MaxLoadAge, memCleanupInterval, items)
+	return &memCache{Cache: cache.NewFrom(MaxLoadAge, memCleanupInterval, items)}
 }
 
-func setMem(c *cache.Cache, key string, th *provider.Thing) {
+func setMem(c *memCache, key string, th *provider.Thing) {
 	if th.Created.IsZero() {
 		th.Created = time.Now()
 	}
 
 	klog.V(1).Infof("Storing %s within in-memory cache (created: %s)", key, th.Created)
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.Set(key, th, MaxLoadAge)
 }
 
-func newerThanMem(c *cache.Cache, key string, t time.Time) *provider.Thing {
+// snapshotMem returns a copy of every item, for saving
+func snapshotMem(c *memCache) map[string]cache.Item {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.Items()
+}
+
+// newerThanMem returns a cached item newer than t, recording whether it was a hit
+func newerThanMem(c *memCache, key string, t time.Time) *provider.Thing {
+	c.mu.RLock()
+	th := lookupMem(c, key, t)
+	c.mu.RUnlock()
+
+	if th == nil {
+		metrics.Count(metrics.CacheMisses, 1)
+		return nil
+	}
+	metrics.Count(metrics.CacheHits, 1)
+	return th
+}
+
+// lookupMem returns a cached item newer than t. Callers must hold c.mu.
+func lookupMem(c *memCache, key string, t time.Time) *provider.Thing {
 	x, ok := c.Get(key)
 	if !ok {
 		klog.V(1).Infof("%s is not within in-memory cache!", key)
@@ -73,8 +107,10 @@ func newerThanMem(c *cache.Cache, key string, t time.Time) *provider.Thing {
 	return th
 }
 
-func deleteOlderMem(c *cache.Cache, key string, t time.Time) {
-	i := newerThanMem(c, key, t)
+func deleteOlderMem(c *memCache, key string, t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	i := lookupMem(c, key, t)
 
 	// Still good.
 	if i != nil && i.Created.After(t) {
@@ -84,3 +120,36 @@ func deleteOlderMem(c *cache.Cache, key string, t time.Time) {
 
 	c.Delete(key)
 }
+
+// deletePrefixMem deletes every key starting with a prefix but none of the exceptions, returning how many were deleted
+func deletePrefixMem(c *memCache, prefix string, except []string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for k := range c.Items() {
+		if hasPrefix(k, prefix, except) {
+			c.Delete(k)
+			n++
+		}
+	}
+	return n
+}
+
+// hasPrefix returns whether a key starts with a prefix, but with none of the exceptions
+func hasPrefix(key string, prefix string, except []string) bool {
+	if !strings.HasPrefix(key, prefix) {
+		return false
+	}
+	for _, e := range except {
+		if strings.HasPrefix(key, e) {
+			return false
+		}
+	}
+	return true
+}
+
+// likePrefix returns a SQL LIKE pattern matching keys starting with prefix
+func likePrefix(prefix string) string {
+	r := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	return r.Replace(prefix) + "%"
+}
@@ -0,0 +1,68 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package persist
+
+import (
+	"fmt"
+
+	"github.com/google/triage-party/pkg/provider"
+
+	"github.com/patrickmn/go-cache"
+	"k8s.io/klog/v2"
+)
+
+// LoadInit merges disk caches into c, in order, so that later paths win on conflicting keys.
+// Items which are older than what c already holds are skipped. All paths must share a format version.
+func LoadInit(c Cacher, paths []string) error {
+	merged := map[string]cache.Item{}
+	format := -1
+
+	for _, p := range paths {
+		decoded, f, err := readDisk(p)
+		if err != nil {
+			return fmt.Errorf("read %s: %w", p, err)
+		}
+		if format != -1 && f != format {
+			return fmt.Errorf("%s has format %d, but %s has format %d", p, f, paths[0], format)
+		}
+		format = f
+
+		klog.Infof("%d items loaded from init cache %s", len(decoded), p)
+		for k, v := range decoded {
+			merged[k] = v
+		}
+	}
+
+	set := 0
+	for k, v := range merged {
+		if v.Expired() {
+			continue
+		}
+		th, ok := v.Object.(*provider.Thing)
+		if !ok {
+			continue
+		}
+		if c.GetNewerThan(k, th.Created) != nil {
+			continue
+		}
+		if err := c.Set(k, th); err != nil {
+			return fmt.Errorf("set %s: %w", k, err)
+		}
+		set++
+	}
+
+	klog.Infof("%d of %d merged init cache items stored in %s", set, len(merged), c)
+	return nil
+}
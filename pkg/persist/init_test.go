@@ -0,0 +1,120 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package persist
+
+import (
+	"encoding/gob"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/triage-party/pkg/provider"
+	"github.com/patrickmn/go-cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadInit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "init")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	write := func(name string, title string, keys ...string) string {
+		d := &Disk{path: filepath.Join(dir, name), cache: createMem()}
+		for _, k := range keys {
+			title := title
+			assert.Nil(t, d.Set(k, &provider.Thing{Issues: []*provider.Issue{{Title: &title}}}))
+		}
+		assert.Nil(t, d.Cleanup())
+		return d.path
+	}
+
+	a := write("a.pc", "team-a", "shared", "a-only")
+	b := write("b.pc", "team-b", "shared", "b-only")
+
+	c, err := NewMemory(Config{})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	assert.Nil(t, c.Initialize())
+	assert.Nil(t, LoadInit(c, []string{a, b}))
+
+	assert.Equal(t, "team-b", c.GetNewerThan("shared", time.Time{}).Issues[0].GetTitle())
+	assert.Equal(t, "team-a", c.GetNewerThan("a-only", time.Time{}).Issues[0].GetTitle())
+	assert.NotNil(t, c.GetNewerThan("b-only", time.Time{}))
+
+	gob.Register(&provider.Thing{})
+	legacy := filepath.Join(dir, "legacy.pc")
+	f, err := os.Create(legacy)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	items := map[string]cache.Item{"old": {Object: &provider.Thing{Created: time.Now()}, Expiration: time.Now().Add(time.Hour).UnixNano()}}
+	assert.Nil(t, gob.NewEncoder(f).Encode(items))
+	f.Close()
+
+	assert.Error(t, LoadInit(c, []string{a, legacy}))
+}
+
+// TestLoadInitConcurrent is most useful with -race
+func TestLoadInitConcurrent(t *testing.T) {
+	dir, err := ioutil.TempDir("", "init")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := &Disk{path: filepath.Join(dir, "init.pc"), cache: createMem()}
+	for i := 0; i < 200; i++ {
+		assert.Nil(t, src.Set(fmt.Sprintf("org-a-%d-timeline", i), &provider.Thing{}))
+	}
+	assert.Nil(t, src.Cleanup())
+
+	d := &Disk{path: filepath.Join(dir, "live.pc"), cache: createMem()}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				k := fmt.Sprintf("org-a-%d-timeline", j)
+				d.GetNewerThan(k, time.Time{})
+				assert.Nil(t, d.Set(fmt.Sprintf("org-b-%d-%d", i, j), &provider.Thing{}))
+				assert.Nil(t, d.DeleteOlderThan(k, time.Now().Add(-time.Hour)))
+			}
+			_, err := d.DeletePrefix(fmt.Sprintf("org-b-%d-", i))
+			assert.Nil(t, err)
+		}(i)
+	}
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		assert.Nil(t, LoadInit(d, []string{src.path}))
+	}()
+	go func() {
+		defer wg.Done()
+		assert.Nil(t, d.Cleanup())
+	}()
+
+	wg.Wait()
+	assert.Nil(t, d.Cleanup())
+}
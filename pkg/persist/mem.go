@@ -20,12 +20,11 @@ import (
 
 	"github.com/google/triage-party/pkg/provider"
 
-	"github.com/patrickmn/go-cache"
 	"k8s.io/klog/v2"
 )
 
 type Memory struct {
-	cache *cache.Cache
+	cache *memCache
 }
 
 // NewMemory returns a new Memory cache
@@ -54,6 +53,11 @@ func (m *Memory) DeleteOlderThan(key string, t time.Time) error {
 	return nil
 }
 
+// DeletePrefix deletes every thing with a key starting with a prefix, except keys starting with an exception
+func (m *Memory) DeletePrefix(prefix string, except ...string) (int, error) {
+	return deletePrefixMem(m.cache, prefix, except), nil
+}
+
 // GetNewerThan returns a thing older than a timestamp
 func (m *Memory) GetNewerThan(key string, t time.Time) *provider.Thing {
 	return newerThanMem(m.cache, key, t)
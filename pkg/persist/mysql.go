@@ -47,7 +47,7 @@ type sqlItem struct {
 }
 
 type MySQL struct {
-	cache *cache.Cache
+	cache *memCache
 	db    *sqlx.DB
 	path  string
 }
@@ -135,6 +135,21 @@ func (m *MySQL) DeleteOlderThan(key string, t time.Time) error {
 	return nil
 }
 
+// DeletePrefix deletes every thing with a key starting with a prefix but no exception, from memory and the database
+func (m *MySQL) DeletePrefix(prefix string, except ...string) (int, error) {
+	n := deletePrefixMem(m.cache, prefix, except)
+	q := `DELETE FROM persist WHERE k LIKE ?`
+	args := []interface{}{likePrefix(prefix)}
+	for _, e := range except {
+		q += ` AND k NOT LIKE ?`
+		args = append(args, likePrefix(e))
+	}
+	if _, err := m.db.Exec(q, args...); err != nil {
+		return n, fmt.Errorf("delete exec: %w", err)
+	}
+	return n, nil
+}
+
 // GetNewerThan returns a Item older than a timestamp
 func (m *MySQL) GetNewerThan(key string, t time.Time) *provider.Thing {
 	return newerThanMem(m.cache, key, t)
@@ -145,7 +160,7 @@ func (m *MySQL) persist(key string, th *provider.Thing) error {
 	b := new(bytes.Buffer)
 	ge := gob.NewEncoder(b)
 
-	item := cache.Item{Object: th}
+	item := cache.Item{Object: redactThing(th)}
 	if err := ge.Encode(item); err != nil {
 		return fmt.Errorf("encode: %w", err)
 	}
@@ -0,0 +1,94 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package persist
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/klog/v2"
+)
+
+// Naming schemes for default disk cache files
+const (
+	// NamingConfig names the cache after the config file and repos override, such as kubernetes.yaml.pc
+	NamingConfig = "config"
+	// NamingPath adds a hash of the absolute config path, such as kubernetes.yaml-1a2b3c4d5e6f.pc
+	NamingPath = "path"
+	// NamingContent adds a hash of the config content, starting a new cache whenever the config changes
+	NamingContent = "content"
+)
+
+// DiskNaming is the naming scheme for default disk cache paths
+var DiskNaming = NamingConfig
+
+// IsNaming returns whether s is a known naming scheme
+func IsNaming(s string) bool {
+	return s == NamingConfig || s == NamingPath || s == NamingContent
+}
+
+// diskName returns the config-derived part of a cache file name
+func diskName(configPath string, override string) string {
+	name := filepath.Base(configPath)
+	if configPath == StdinConfig {
+		name = "stdin"
+	}
+	if override != "" {
+		name = name + "_" + strings.Replace(override, "/", "_", -1)
+	}
+	return name
+}
+
+// shortHash returns the first 12 hex digits of the SHA-256 of bs
+func shortHash(bs []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(bs))[:12]
+}
+
+// DiskPath returns the default disk cache path for a config, using a naming scheme
+func DiskPath(naming string, configPath string, override string) (string, error) {
+	name := diskName(configPath, override)
+
+	switch naming {
+	case "", NamingConfig:
+	case NamingPath, NamingContent:
+		if configPath == StdinConfig {
+			return "", fmt.Errorf("%s naming requires a config file, set --persist-path when reading the config from stdin", naming)
+		}
+
+		if naming == NamingPath {
+			abs, err := filepath.Abs(configPath)
+			if err != nil {
+				return "", fmt.Errorf("abs: %w", err)
+			}
+			name = name + "-" + shortHash([]byte(abs))
+			break
+		}
+
+		bs, err := ioutil.ReadFile(configPath)
+		if err != nil {
+			return "", fmt.Errorf("read config: %w", err)
+		}
+		name = name + "-" + shortHash(bs)
+	default:
+		return "", fmt.Errorf("unknown naming %q, expected %s, %s, or %s", naming, NamingConfig, NamingPath, NamingContent)
+	}
+
+	path := filepath.Join(findCacheRoot(), name+".pc")
+	klog.Infof("default disk path: %s", path)
+	return path, nil
+}
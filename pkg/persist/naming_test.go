@@ -0,0 +1,68 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package persist
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiskPath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "naming")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	a := filepath.Join(dir, "a", "config.yaml")
+	b := filepath.Join(dir, "b", "config.yaml")
+	for _, p := range []string{a, b} {
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := ioutil.WriteFile(p, []byte("settings: {}\n"), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	pa, err := DiskPath(NamingConfig, a, "")
+	assert.NoError(t, err)
+	pb, err := DiskPath(NamingConfig, b, "")
+	assert.NoError(t, err)
+	assert.Equal(t, pa, pb)
+	assert.True(t, strings.HasSuffix(pa, "config.yaml.pc"), pa)
+
+	pa, err = DiskPath(NamingPath, a, "")
+	assert.NoError(t, err)
+	pb, err = DiskPath(NamingPath, b, "")
+	assert.NoError(t, err)
+	assert.NotEqual(t, pa, pb)
+
+	pa, err = DiskPath(NamingContent, a, "")
+	assert.NoError(t, err)
+	pb, err = DiskPath(NamingContent, b, "")
+	assert.NoError(t, err)
+	assert.Equal(t, pa, pb)
+
+	_, err = DiskPath(NamingContent, StdinConfig, "")
+	assert.Error(t, err)
+	_, err = DiskPath("bogus", a, "")
+	assert.Error(t, err)
+}
@@ -44,6 +44,9 @@ type Cacher interface {
 	Set(string, *provider.Thing) error
 	DeleteOlderThan(string, time.Time) error
 	GetNewerThan(string, time.Time) *provider.Thing
+	// DeletePrefix deletes every thing with a key starting with a prefix, except keys starting with
+	// any of the exceptions, returning how many were deleted
+	DeletePrefix(string, ...string) (int, error)
 
 	Initialize() error
 	Cleanup() error
@@ -58,6 +61,8 @@ func New(cfg Config) (Cacher, error) {
 		return NewCloudSQL(cfg)
 	case "postgres":
 		return NewPostgres(cfg)
+	case "redis":
+		return NewRedis(cfg)
 	case "disk", "":
 		return NewDisk(cfg)
 	case "memory":
@@ -67,6 +72,9 @@ func New(cfg Config) (Cacher, error) {
 	}
 }
 
+// StdinConfig is the config path which reads the configuration from stdin
+const StdinConfig = "-"
+
 // FromEnv is shared magic between binaries
 func FromEnv(backend string, path string, configPath string, reposOverride string) (Cacher, error) {
 	if backend == "" {
@@ -81,7 +89,11 @@ func FromEnv(backend string, path string, configPath string, reposOverride strin
 	}
 
 	if backend == "disk" && path == "" {
-		path = DefaultDiskPath(configPath, reposOverride)
+		var err error
+		path, err = DiskPath(DiskNaming, configPath, reposOverride)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	c, err := New(Config{
@@ -41,7 +41,7 @@ CREATE INDEX IF NOT EXISTS saved_idx ON persist (saved);
 `
 
 type Postgres struct {
-	cache *cache.Cache
+	cache *memCache
 	db    *sqlx.DB
 	path  string
 }
@@ -130,6 +130,21 @@ func (m *Postgres) DeleteOlderThan(key string, t time.Time) error {
 	return nil
 }
 
+// DeletePrefix deletes every thing with a key starting with a prefix but no exception, from memory and the database
+func (m *Postgres) DeletePrefix(prefix string, except ...string) (int, error) {
+	n := deletePrefixMem(m.cache, prefix, except)
+	q := `DELETE FROM persist WHERE k LIKE $1`
+	args := []interface{}{likePrefix(prefix)}
+	for _, e := range except {
+		args = append(args, likePrefix(e))
+		q += fmt.Sprintf(` AND k NOT LIKE $%d`, len(args))
+	}
+	if _, err := m.db.Exec(q, args...); err != nil {
+		return n, fmt.Errorf("delete exec: %w", err)
+	}
+	return n, nil
+}
+
 // GetNewerThan returns a Item older than a timestamp
 func (m *Postgres) GetNewerThan(key string, t time.Time) *provider.Thing {
 	return newerThanMem(m.cache, key, t)
@@ -140,7 +155,7 @@ func (m *Postgres) persist(key string, th *provider.Thing) error {
 	b := new(bytes.Buffer)
 	ge := gob.NewEncoder(b)
 
-	item := cache.Item{Object: th}
+	item := cache.Item{Object: redactThing(th)}
 	if err := ge.Encode(item); err != nil {
 		return fmt.Errorf("encode: %w", err)
 	}
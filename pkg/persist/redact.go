@@ -0,0 +1,74 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package persist
+
+import (
+	"unicode/utf8"
+
+	"github.com/google/triage-party/pkg/provider"
+)
+
+// MaxBodyLength truncates issue and PR bodies to this many bytes when persisting.
+// 0 persists full bodies, and a negative value omits them. In-memory bodies are unaffected.
+var MaxBodyLength = 0
+
+// redactBody returns a body limited to MaxBodyLength
+func redactBody(s *string) *string {
+	if s == nil || MaxBodyLength == 0 {
+		return s
+	}
+	if MaxBodyLength < 0 {
+		return nil
+	}
+	if len(*s) <= MaxBodyLength {
+		return s
+	}
+	// Avoid splitting a multi-byte character
+	n := MaxBodyLength
+	for n > 0 && !utf8.RuneStart((*s)[n]) {
+		n--
+	}
+	b := (*s)[:n]
+	return &b
+}
+
+// redactThing returns a copy of a thing with bodies limited to MaxBodyLength, leaving the original intact
+func redactThing(th *provider.Thing) *provider.Thing {
+	if th == nil || MaxBodyLength == 0 || (len(th.Issues) == 0 && len(th.PullRequests) == 0) {
+		return th
+	}
+
+	c := *th
+	c.Issues = make([]*provider.Issue, len(th.Issues))
+	for x, i := range th.Issues {
+		if i != nil {
+			ri := *i
+			ri.Body = redactBody(i.Body)
+			i = &ri
+		}
+		c.Issues[x] = i
+	}
+
+	c.PullRequests = make([]*provider.PullRequest, len(th.PullRequests))
+	for x, pr := range th.PullRequests {
+		if pr != nil {
+			rpr := *pr
+			rpr.Body = redactBody(pr.Body)
+			pr = &rpr
+		}
+		c.PullRequests[x] = pr
+	}
+	return &c
+}
@@ -0,0 +1,40 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package persist
+
+import (
+	"testing"
+
+	"github.com/google/triage-party/pkg/provider"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactThing(t *testing.T) {
+	defer func(n int) { MaxBodyLength = n }(MaxBodyLength)
+	body := "héllo world"
+	th := &provider.Thing{Issues: []*provider.Issue{{Body: &body}}, PullRequests: []*provider.PullRequest{{Body: &body}}}
+
+	MaxBodyLength = 0
+	assert.Same(t, th, redactThing(th))
+
+	MaxBodyLength = 2
+	r := redactThing(th)
+	assert.Equal(t, "h", r.Issues[0].GetBody())
+	assert.Equal(t, "h", r.PullRequests[0].GetBody())
+	assert.Equal(t, body, th.Issues[0].GetBody())
+
+	MaxBodyLength = -1
+	assert.Nil(t, redactThing(th).Issues[0].Body)
+}
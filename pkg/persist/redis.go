@@ -0,0 +1,237 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package persist provides a persistence layer for the in-memory cache
+package persist
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/triage-party/pkg/provider"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/patrickmn/go-cache"
+	"k8s.io/klog/v2"
+)
+
+// redisPrefix namespaces keys, so that a Redis database may be shared with other applications
+const redisPrefix = "triage-party:"
+
+// redisBatch is how many keys are scanned or fetched per round-trip
+const redisBatch = 500
+
+type Redis struct {
+	cache *memCache
+	pool  *redis.Pool
+	path  string
+}
+
+// NewRedis returns a new Redis cache, where the path is a URL such as redis://:password@localhost:6379/0
+func NewRedis(cfg Config) (*Redis, error) {
+	m := &Redis{
+		path: cfg.Path,
+		pool: &redis.Pool{
+			MaxIdle:     4,
+			IdleTimeout: 5 * time.Minute,
+			Dial: func() (redis.Conn, error) {
+				return redis.DialURL(cfg.Path, redis.DialConnectTimeout(10*time.Second))
+			},
+		},
+	}
+
+	c := m.pool.Get()
+	defer c.Close()
+	if _, err := c.Do("PING"); err != nil {
+		return nil, fmt.Errorf("ping: %w", err)
+	}
+
+	return m, nil
+}
+
+func (m *Redis) String() string {
+	return redactURL(m.path)
+}
+
+// redactURL hides the password within a Redis URL
+func redactURL(s string) string {
+	at := strings.LastIndex(s, "@")
+	scheme := strings.Index(s, "://")
+	if at == -1 || scheme == -1 || at < scheme {
+		return s
+	}
+	return s[:scheme+3] + "..." + s[at:]
+}
+
+func (m *Redis) Initialize() error {
+	if err := m.loadItems(); err != nil {
+		return fmt.Errorf("load items: %w", err)
+	}
+	return nil
+}
+
+func (m *Redis) loadItems() error {
+	c := m.pool.Get()
+	defer c.Close()
+
+	klog.Infof("loading items from %s ...", m)
+	keys, err := m.scan(c, redisPrefix+"*")
+	if err != nil {
+		return err
+	}
+
+	decoded := map[string]cache.Item{}
+	for len(keys) > 0 {
+		n := len(keys)
+		if n > redisBatch {
+			n = redisBatch
+		}
+		batch := keys[:n]
+		keys = keys[n:]
+
+		vals, err := redis.ByteSlices(c.Do("MGET", redis.Args{}.AddFlat(batch)...))
+		if err != nil {
+			return fmt.Errorf("mget: %w", err)
+		}
+
+		for i, v := range vals {
+			// expired since the scan
+			if v == nil {
+				continue
+			}
+			key := strings.TrimPrefix(batch[i], redisPrefix)
+
+			var item cache.Item
+			if err := gob.NewDecoder(bytes.NewBuffer(v)).Decode(&item); err != nil {
+				klog.Errorf("decode failed for %s (bytes: %d): %v", key, len(v), err)
+				continue
+			}
+			decoded[key] = item
+		}
+	}
+
+	klog.Infof("%d items loaded from Redis", len(decoded))
+	m.cache = loadMem(decoded)
+	return nil
+}
+
+// scan returns every key matching a pattern, without blocking the server as KEYS would
+func (m *Redis) scan(c redis.Conn, pattern string) ([]string, error) {
+	keys := []string{}
+	cursor := 0
+	for {
+		vals, err := redis.Values(c.Do("SCAN", cursor, "MATCH", pattern, "COUNT", redisBatch))
+		if err != nil {
+			return nil, fmt.Errorf("scan: %w", err)
+		}
+
+		var found []string
+		if _, err := redis.Scan(vals, &cursor, &found); err != nil {
+			return nil, fmt.Errorf("scan reply: %w", err)
+		}
+		keys = append(keys, found...)
+
+		if cursor == 0 {
+			return keys, nil
+		}
+	}
+}
+
+// Set stores a thing
+func (m *Redis) Set(key string, th *provider.Thing) error {
+	setMem(m.cache, key, th)
+
+	go func() {
+		err := m.persist(key, th)
+		if err != nil {
+			klog.Errorf("failed to persist %s: %s", key, err)
+		}
+	}()
+
+	return nil
+}
+
+// DeleteOlderThan deletes a thing older than a timestamp
+func (m *Redis) DeleteOlderThan(key string, t time.Time) error {
+	deleteOlderMem(m.cache, key, t)
+	return nil
+}
+
+// DeletePrefix deletes every thing with a key starting with a prefix but no exception, from memory and Redis
+func (m *Redis) DeletePrefix(prefix string, except ...string) (int, error) {
+	n := deletePrefixMem(m.cache, prefix, except)
+
+	c := m.pool.Get()
+	defer c.Close()
+
+	found, err := m.scan(c, redisPrefix+globPrefix(prefix))
+	if err != nil {
+		return n, err
+	}
+
+	keys := []string{}
+	for _, k := range found {
+		if hasPrefix(strings.TrimPrefix(k, redisPrefix), prefix, except) {
+			keys = append(keys, k)
+		}
+	}
+
+	for len(keys) > 0 {
+		batch := len(keys)
+		if batch > redisBatch {
+			batch = redisBatch
+		}
+		if _, err := c.Do("DEL", redis.Args{}.AddFlat(keys[:batch])...); err != nil {
+			return n, fmt.Errorf("del: %w", err)
+		}
+		keys = keys[batch:]
+	}
+	return n, nil
+}
+
+// globPrefix returns a Redis pattern which matches keys starting with a prefix
+func globPrefix(prefix string) string {
+	r := strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+	return r.Replace(prefix) + "*"
+}
+
+// GetNewerThan returns a Item older than a timestamp
+func (m *Redis) GetNewerThan(key string, t time.Time) *provider.Thing {
+	return newerThanMem(m.cache, key, t)
+}
+
+// persist writes a thing to Redis, expiring it once it is too old to save
+func (m *Redis) persist(key string, th *provider.Thing) error {
+	b := new(bytes.Buffer)
+	ge := gob.NewEncoder(b)
+
+	item := cache.Item{Object: redactThing(th)}
+	if err := ge.Encode(item); err != nil {
+		return fmt.Errorf("encode: %w", err)
+	}
+
+	c := m.pool.Get()
+	defer c.Close()
+
+	_, err := c.Do("SET", redisPrefix+key, b.Bytes(), "EX", int(MaxSaveAge.Seconds()))
+	return err
+}
+
+// Cleanup is a no-op, as Redis expires stale items itself
+func (m *Redis) Cleanup() error {
+	return nil
+}
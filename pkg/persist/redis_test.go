@@ -0,0 +1,27 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package persist
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisHelpers(t *testing.T) {
+	assert.Equal(t, `org-a\*b\[1\]-*`, globPrefix("org-a*b[1]-"))
+	assert.Equal(t, "redis://...@cache:6379/0", redactURL("redis://:hunter2@cache:6379/0"))
+	assert.Equal(t, "redis://cache:6379", redactURL("redis://cache:6379"))
+}
@@ -0,0 +1,65 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v33/github"
+)
+
+// AccessDenied returns true if err shows that the token is not permitted to read a repository
+//
+// GitHub hides private repositories behind a 404, so a 404 is only treated as denied if the
+// token advertises OAuth scopes which do not include access to private repositories.
+func AccessDenied(err error) bool {
+	var rle *github.RateLimitError
+	var are *github.AbuseRateLimitError
+	if errors.As(err, &rle) || errors.As(err, &are) {
+		return false
+	}
+
+	switch statusCode(err) {
+	case http.StatusForbidden:
+		return true
+	case http.StatusNotFound:
+		s, ok := oauthScopes(err)
+		return ok && !hasRepoScope(s)
+	}
+	return false
+}
+
+// oauthScopes returns the X-OAuth-Scopes header for a GitHub error, if present
+func oauthScopes(err error) (string, bool) {
+	var ge *github.ErrorResponse
+	if errors.As(err, &ge) && ge.Response != nil {
+		if v, ok := ge.Response.Header["X-Oauth-Scopes"]; ok {
+			return strings.Join(v, ","), true
+		}
+	}
+	return "", false
+}
+
+// hasRepoScope returns true if a list of OAuth scopes grants access to private repositories
+func hasRepoScope(scopes string) bool {
+	for _, s := range strings.Split(scopes, ",") {
+		if strings.TrimSpace(s) == "repo" {
+			return true
+		}
+	}
+	return false
+}
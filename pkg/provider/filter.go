@@ -17,6 +17,7 @@ package provider
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -27,6 +28,7 @@ type Filter struct {
 	RawLabel    string `yaml:"label,omitempty"`
 	labelRegex  *regexp.Regexp
 	labelNegate bool
+	labelNone   bool
 
 	RawTag    string `yaml:"tag,omitempty"`
 	tagRegex  *regexp.Regexp
@@ -36,15 +38,39 @@ type Filter struct {
 	titleRegex  *regexp.Regexp
 	titleNegate bool
 
+	RawBodyMatches string `yaml:"body-matches,omitempty"`
+	bodyMatches    *regexp.Regexp
+	RawBodyMissing string `yaml:"body-missing,omitempty"`
+	bodyMissing    *regexp.Regexp
+
+	// Markdown section within the body, such as "Steps to reproduce"
+	RawSection    string `yaml:"section,omitempty"`
+	sectionRegex  *regexp.Regexp
+	sectionNegate bool
+
 	RawMilestone    string `yaml:"milestone,omitempty"`
 	milestoneRegex  *regexp.Regexp
 	milestoneNegate bool
+	milestoneNone   bool
+	MilestoneState  string `yaml:"milestone-state,omitempty"`
+
+	RawNumber    string `yaml:"number,omitempty"`
+	numberRanges [][2]int
+
+	RawBaseRef     string `yaml:"base-ref,omitempty"`
+	baseRefRegex   *regexp.Regexp
+	baseRefNegate  bool
+	baseRefDefault bool
 
 	Created            string `yaml:"created,omitempty"`
 	Updated            string `yaml:"updated,omitempty"`
 	Closed             string `yaml:"closed,omitempty"`
 	Prioritized        string `yaml:"prioritized,omitempty"`
+	AgeInState         string `yaml:"age-in-state,omitempty"`
+	Opened             string `yaml:"opened,omitempty"`
 	Responded          string `yaml:"responded,omitempty"`
+	HumanActivity      string `yaml:"human-activity,omitempty"`
+	FirstResponse      string `yaml:"first-response,omitempty"`
 	Reactions          string `yaml:"reactions,omitempty"`
 	ReactionsPerMonth  string `yaml:"reactions-per-month,omitempty"`
 	Comments           string `yaml:"comments,omitempty"`
@@ -52,12 +78,65 @@ type Filter struct {
 	CommentersPerMonth string `yaml:"commenters-per-month,omitempty"`
 	ClosedComments     string `yaml:"comments-while-closed,omitempty"`
 	ClosedCommenters   string `yaml:"commenters-while-closed,omitempty"`
+	Participants       string `yaml:"participants,omitempty"`
+	References         string `yaml:"references,omitempty"`
 	State              string `yaml:"state,omitempty"`
+	Awaiting           string `yaml:"awaiting,omitempty"`
+	Involves           string `yaml:"involves,omitempty"`
+	AuthorType         string `yaml:"author-type,omitempty"`
+	Assignee           string `yaml:"assignee,omitempty"`
+	Reviewer           string `yaml:"reviewer,omitempty"`
+	Review             string `yaml:"review,omitempty"`
+
+	Mergeable             string `yaml:"mergeable,omitempty"`
+	NeedsRebase           string `yaml:"needs-rebase,omitempty"`
+	MissingRequiredChecks string `yaml:"missing-required-checks,omitempty"`
+	ClosesIssue           string `yaml:"closes-issue,omitempty"`
+	Discussion            string `yaml:"discussion,omitempty"`
+
+	Size         string `yaml:"size,omitempty"`
+	ChangedFiles string `yaml:"changed-files,omitempty"`
+
+	// Task list items within the body, such as "- [ ] write docs"
+	TasksComplete  string `yaml:"tasks-complete,omitempty"`
+	TasksRemaining string `yaml:"tasks-remaining,omitempty"`
+
+	// Locked matches items whose conversation has been locked ("true") or not ("false")
+	Locked string `yaml:"locked,omitempty"`
+
+	// SLA is matched against the SLA of the collection the rule is shown in
+	SLA string `yaml:"sla,omitempty"`
 }
 
+// Values for the awaiting filter
+const (
+	AwaitingReporter   = "reporter"
+	AwaitingMaintainer = "maintainer"
+)
+
+// None is a label, milestone, or assignee value which matches items without any, or with any when negated
+const None = "none"
+
+// InvolvesViewer is an involves value which matches the logged in user
+const InvolvesViewer = "@me"
+
+// Values for the author-type filter
+const (
+	AuthorTypeBot  = "bot"
+	AuthorTypeUser = "user"
+)
+
+// DefaultBranch is the base-ref filter value which refers to a repository's default branch
+const DefaultBranch = "default"
+
 // LoadLabelRegex loads a new label reegx
 func (f *Filter) LoadLabelRegex() error {
 	label, negateLabel := negativeMatch(f.RawLabel)
+	if label == None {
+		f.labelNone = true
+		f.labelNegate = negateLabel
+		return nil
+	}
 
 	re, err := regex(label)
 	if err != nil {
@@ -77,6 +156,11 @@ func (f *Filter) LabelNegate() bool {
 	return f.labelNegate
 }
 
+// LabelNone returns true if the filter matches items without labels (or with labels, if negated)
+func (f *Filter) LabelNone() bool {
+	return f.labelNone
+}
+
 // LoadTagRegex loads a new tag regex
 func (f *Filter) LoadTagRegex() error {
 	tag, negateState := negativeMatch(f.RawTag)
@@ -121,9 +205,101 @@ func (f *Filter) TitleNegate() bool {
 	return f.titleNegate
 }
 
+// LoadBaseRefRegex loads a new base-ref regex, where "default" refers to the repository's default branch
+func (f *Filter) LoadBaseRefRegex() error {
+	r, negateState := negativeMatch(f.RawBaseRef)
+	f.baseRefNegate = negateState
+	if r == DefaultBranch {
+		f.baseRefDefault = true
+		return nil
+	}
+
+	re, err := regex(r)
+	if err != nil {
+		return err
+	}
+	f.baseRefRegex = re
+	return nil
+}
+
+// BaseRefRegex returns the regex which the base branch of a pull request must match
+func (f *Filter) BaseRefRegex() *regexp.Regexp {
+	return f.baseRefRegex
+}
+
+// BaseRefNegate returns whether the base-ref filter is negated
+func (f *Filter) BaseRefNegate() bool {
+	return f.baseRefNegate
+}
+
+// BaseRefDefault returns whether the base-ref filter refers to the repository's default branch
+func (f *Filter) BaseRefDefault() bool {
+	return f.baseRefDefault
+}
+
+// LoadBodyRegex loads the body-matches and body-missing regexes. Unlike other
+// filters, these are unanchored, as they are searched for within the body.
+func (f *Filter) LoadBodyRegex() error {
+	if f.RawBodyMatches != "" {
+		re, err := regexp.Compile(f.RawBodyMatches)
+		if err != nil {
+			return fmt.Errorf("body-matches: %w", err)
+		}
+		f.bodyMatches = re
+	}
+
+	if f.RawBodyMissing != "" {
+		re, err := regexp.Compile(f.RawBodyMissing)
+		if err != nil {
+			return fmt.Errorf("body-missing: %w", err)
+		}
+		f.bodyMissing = re
+	}
+	return nil
+}
+
+// BodyMatches returns a regex which must be found within the body
+func (f *Filter) BodyMatches() *regexp.Regexp {
+	return f.bodyMatches
+}
+
+// BodyMissing returns a regex which must not be found within the body
+func (f *Filter) BodyMissing() *regexp.Regexp {
+	return f.bodyMissing
+}
+
+// LoadSectionRegex loads the section regex, which is searched for case-insensitively within headings
+func (f *Filter) LoadSectionRegex() error {
+	r, negateState := negativeMatch(f.RawSection)
+
+	re, err := regexp.Compile("(?i)" + r)
+	if err != nil {
+		return err
+	}
+
+	f.sectionRegex = re
+	f.sectionNegate = negateState
+	return nil
+}
+
+// SectionRegex returns a regex matching the heading of a section which must be filled in
+func (f *Filter) SectionRegex() *regexp.Regexp {
+	return f.sectionRegex
+}
+
+// SectionNegate returns whether the section must instead be missing or empty
+func (f *Filter) SectionNegate() bool {
+	return f.sectionNegate
+}
+
 // LoadMilestoneRegex loads a new milestone regex
 func (f *Filter) LoadMilestoneRegex() error {
 	r, negateState := negativeMatch(f.RawMilestone)
+	if r == None {
+		f.milestoneNone = true
+		f.milestoneNegate = negateState
+		return nil
+	}
 
 	re, err := regex(r)
 	if err != nil {
@@ -143,6 +319,86 @@ func (f *Filter) MilestoneNegate() bool {
 	return f.milestoneNegate
 }
 
+// MilestoneNone returns true if the filter matches items without a milestone (or with one, if negated)
+func (f *Filter) MilestoneNone() bool {
+	return f.milestoneNone
+}
+
+// AssigneeNone returns whether the assignee filter matches unassigned items, and whether it is negated
+func (f *Filter) AssigneeNone() (bool, bool) {
+	s, negate := negativeMatch(f.Assignee)
+	return s == None, negate
+}
+
+// LoadNumbers parses a list of item numbers and ranges, such as "12,1000-2000"
+func (f *Filter) LoadNumbers() error {
+	f.numberRanges = nil
+	for _, p := range strings.Split(f.RawNumber, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+
+		bounds := strings.SplitN(p, "-", 2)
+		lo, err := strconv.Atoi(strings.TrimSpace(bounds[0]))
+		if err != nil {
+			return fmt.Errorf("number %q: %w", p, err)
+		}
+
+		hi := lo
+		if len(bounds) == 2 {
+			hi, err = strconv.Atoi(strings.TrimSpace(bounds[1]))
+			if err != nil {
+				return fmt.Errorf("number %q: %w", p, err)
+			}
+		}
+
+		if hi < lo {
+			return fmt.Errorf("number %q: range end is before its start", p)
+		}
+		f.numberRanges = append(f.numberRanges, [2]int{lo, hi})
+	}
+
+	if len(f.numberRanges) == 0 {
+		return fmt.Errorf("number %q: no numbers found", f.RawNumber)
+	}
+	return nil
+}
+
+// Numbers returns every item number the filter is restricted to, or nil if there are more than max
+func (f *Filter) Numbers(max int) []int {
+	total := 0
+	for _, r := range f.numberRanges {
+		total += r[1] - r[0] + 1
+	}
+	if total == 0 || total > max {
+		return nil
+	}
+
+	ns := []int{}
+	for _, r := range f.numberRanges {
+		for n := r[0]; n <= r[1]; n++ {
+			ns = append(ns, n)
+		}
+	}
+	return ns
+}
+
+// HasNumbers returns true if the filter is restricted to specific item numbers
+func (f *Filter) HasNumbers() bool {
+	return len(f.numberRanges) > 0
+}
+
+// MatchNumber returns true if an item number is within the filters numbers or ranges
+func (f *Filter) MatchNumber(n int) bool {
+	for _, r := range f.numberRanges {
+		if n >= r[0] && n <= r[1] {
+			return true
+		}
+	}
+	return false
+}
+
 // negativeMatch parses a match string and returns the underlying string and negation bool
 func negativeMatch(s string) (string, bool) {
 	if strings.HasPrefix(s, "!") {
@@ -18,6 +18,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/google/go-github/v33/github"
 	"golang.org/x/oauth2"
@@ -95,6 +98,15 @@ func (p *GitHubProvider) IssuesListByRepo(ctx context.Context, sp SearchParams)
 	return
 }
 
+func (p *GitHubProvider) IssuesGet(ctx context.Context, sp SearchParams) (i *Issue, r *Response, err error) {
+	gi, gr, err := p.client.Issues.Get(ctx, sp.Repo.Organization, sp.Repo.Project, sp.IssueNumber)
+	if gi != nil {
+		i = p.getIssues([]*github.Issue{gi})[0]
+	}
+	r = p.getResponse(gr)
+	return
+}
+
 func (p *GitHubProvider) getIssuesListCommentsOptions(sp SearchParams) *github.IssueListCommentsOptions {
 	return &github.IssueListCommentsOptions{
 		ListOptions: p.getListOptions(sp.IssueListCommentsOptions.ListOptions),
@@ -267,17 +279,194 @@ func (p *GitHubProvider) PullRequestsListReviews(ctx context.Context, sp SearchP
 	return
 }
 
-func NewGitHub(ctx context.Context, token string, url string) (Provider, error) {
+func (p *GitHubProvider) IssuesListLabels(ctx context.Context, sp SearchParams) ([]*Label, *Response, error) {
+	opt := p.getListOptions(sp.ListOptions)
+	gl, gr, err := p.client.Issues.ListLabels(ctx, sp.Repo.Organization, sp.Repo.Project, &opt)
+	ls := make([]*Label, len(gl))
+	for k, v := range gl {
+		ls[k] = &Label{ID: v.ID, URL: v.URL, Name: v.Name, Color: v.Color, Description: v.Description, Default: v.Default, NodeID: v.NodeID}
+	}
+	return ls, p.getResponse(gr), err
+}
+
+func (p *GitHubProvider) IssuesAddLabelsToIssue(ctx context.Context, sp SearchParams, labels []string) (*Response, error) {
+	_, gr, err := p.client.Issues.AddLabelsToIssue(ctx, sp.Repo.Organization, sp.Repo.Project, sp.IssueNumber, labels)
+	return p.getResponse(gr), err
+}
+
+func (p *GitHubProvider) IssuesRemoveLabelForIssue(ctx context.Context, sp SearchParams, label string) (*Response, error) {
+	gr, err := p.client.Issues.RemoveLabelForIssue(ctx, sp.Repo.Organization, sp.Repo.Project, sp.IssueNumber, label)
+	return p.getResponse(gr), err
+}
+
+func (p *GitHubProvider) IssuesEdit(ctx context.Context, sp SearchParams, req *IssueRequest) (*Response, error) {
+	_, gr, err := p.client.Issues.Edit(ctx, sp.Repo.Organization, sp.Repo.Project, sp.IssueNumber, &github.IssueRequest{State: req.State, Assignees: req.Assignees})
+	return p.getResponse(gr), err
+}
+
+func (p *GitHubProvider) RepositoriesGetPermissionLevel(ctx context.Context, sp SearchParams, user string) (string, *Response, error) {
+	pl, gr, err := p.client.Repositories.GetPermissionLevel(ctx, sp.Repo.Organization, sp.Repo.Project, user)
+	return pl.GetPermission(), p.getResponse(gr), err
+}
+
+func (p *GitHubProvider) SearchIssues(ctx context.Context, sp SearchParams) ([]*Issue, *Response, error) {
+	opt := &github.SearchOptions{ListOptions: p.getListOptions(sp.ListOptions)}
+	gs, gr, err := p.client.Search.Issues(ctx, sp.Query, opt)
+	if err != nil {
+		return nil, p.getResponse(gr), err
+	}
+	return p.getIssues(gs.Issues), p.getResponse(gr), err
+}
+
+func (p *GitHubProvider) RepositoriesGetRequiredStatusChecks(ctx context.Context, sp SearchParams, branch string) ([]string, *Response, error) {
+	rc, gr, err := p.client.Repositories.GetRequiredStatusChecks(ctx, sp.Repo.Organization, sp.Repo.Project, branch)
+	// Unprotected branches have no required checks
+	if statusCode(err) == http.StatusNotFound {
+		return []string{}, p.getResponse(gr), nil
+	}
+	if err != nil {
+		return nil, p.getResponse(gr), err
+	}
+	return rc.Contexts, p.getResponse(gr), nil
+}
+
+// RepositoriesListReportedChecks returns the names of statuses and check runs reported for a ref
+func (p *GitHubProvider) RepositoriesListReportedChecks(ctx context.Context, sp SearchParams, ref string) ([]string, *Response, error) {
+	names := []string{}
+	opt := &github.ListOptions{PerPage: 100}
+	for {
+		cs, gr, err := p.client.Repositories.GetCombinedStatus(ctx, sp.Repo.Organization, sp.Repo.Project, ref, opt)
+		if err != nil {
+			return nil, p.getResponse(gr), err
+		}
+		for _, s := range cs.Statuses {
+			names = append(names, s.GetContext())
+		}
+		if gr.NextPage == 0 {
+			break
+		}
+		opt.Page = gr.NextPage
+	}
+
+	copt := &github.ListCheckRunsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		crs, gr, err := p.client.Checks.ListCheckRunsForRef(ctx, sp.Repo.Organization, sp.Repo.Project, ref, copt)
+		if err != nil {
+			return nil, p.getResponse(gr), err
+		}
+		for _, c := range crs.CheckRuns {
+			names = append(names, c.GetName())
+		}
+		if gr.NextPage == 0 {
+			return names, p.getResponse(gr), nil
+		}
+		copt.Page = gr.NextPage
+	}
+}
+
+// TeamsListMembers returns the members of a team within sp.Repo.Organization, including members of child teams
+func (p *GitHubProvider) TeamsListMembers(ctx context.Context, sp SearchParams, slug string) ([]*User, *Response, error) {
+	opt := &github.TeamListTeamMembersOptions{Role: "all", ListOptions: p.getListOptions(sp.ListOptions)}
+	gus, gr, err := p.client.Teams.ListTeamMembersBySlug(ctx, sp.Repo.Organization, slug, opt)
+	if err != nil {
+		return nil, p.getResponse(gr), err
+	}
+
+	us := make([]*User, len(gus))
+	for k, v := range gus {
+		us[k] = &User{Login: v.Login, ID: v.ID, AvatarURL: v.AvatarURL, HTMLURL: v.HTMLURL}
+	}
+	return us, p.getResponse(gr), nil
+}
+
+// RepositoriesListByOwner returns the names of non-archived repositories with issues enabled, owned by the org or user in sp.Repo.Organization
+func (p *GitHubProvider) RepositoriesListByOwner(ctx context.Context, sp SearchParams) ([]string, *Response, error) {
+	opt := p.getListOptions(sp.ListOptions)
+	grs, gr, err := p.client.Repositories.ListByOrg(ctx, sp.Repo.Organization, &github.RepositoryListByOrgOptions{ListOptions: opt})
+	// Users are not organizations
+	if statusCode(err) == http.StatusNotFound {
+		grs, gr, err = p.client.Repositories.List(ctx, sp.Repo.Organization, &github.RepositoryListOptions{Type: "owner", ListOptions: opt})
+	}
+	if err != nil {
+		return nil, p.getResponse(gr), err
+	}
+
+	names := []string{}
+	for _, r := range grs {
+		if r.GetArchived() || !r.GetHasIssues() {
+			continue
+		}
+		names = append(names, r.GetName())
+	}
+	return names, p.getResponse(gr), nil
+}
+
+func NewGitHub(ctx context.Context, token string, url string, userAgent string) (Provider, error) {
 	o := oauth2.NewClient(ctx, oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: token},
 	))
+	return newGitHub(o, url, userAgent)
+}
 
+// newGitHub returns a GitHub provider which sends authenticated requests using o
+func newGitHub(o *http.Client, url string, userAgent string) (Provider, error) {
+	client := github.NewClient(o)
 	if url != "" {
-		client, err := github.NewEnterpriseClient(url, url, o)
+		var err error
+		client, err = github.NewEnterpriseClient(url, url, o)
 		if err != nil {
 			return nil, fmt.Errorf("NewEnterpriseClient: %v", err)
 		}
-		return &GitHubProvider{client: client}, nil
 	}
-	return &GitHubProvider{client: github.NewClient(o)}, nil
+
+	if userAgent != "" {
+		client.UserAgent = userAgent
+	}
+	return &GitHubProvider{client: client}, nil
+}
+
+// NewGitHubEnterprise returns a GitHub provider using exact API and upload URLs, such as https://ghe.corp/github/api/v3/.
+// Unlike NewGitHub, no /api/v3/ suffix is added, so instances mounted under any path are supported.
+func NewGitHubEnterprise(ctx context.Context, token string, apiURL string, uploadURL string, userAgent string) (Provider, error) {
+	o := oauth2.NewClient(ctx, oauth2.StaticTokenSource(
+		&oauth2.Token{AccessToken: token},
+	))
+
+	client := github.NewClient(o)
+
+	var err error
+	client.BaseURL, err = enterpriseURL(apiURL)
+	if err != nil {
+		return nil, fmt.Errorf("api url: %w", err)
+	}
+
+	if uploadURL == "" {
+		uploadURL = apiURL
+	}
+	client.UploadURL, err = enterpriseURL(uploadURL)
+	if err != nil {
+		return nil, fmt.Errorf("upload url: %w", err)
+	}
+
+	if userAgent != "" {
+		client.UserAgent = userAgent
+	}
+	return &GitHubProvider{client: client}, nil
+}
+
+// enterpriseURL parses an absolute http(s) URL, adding the trailing slash required by go-github
+func enterpriseURL(s string) (*url.URL, error) {
+	if !strings.HasSuffix(s, "/") {
+		s += "/"
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return nil, fmt.Errorf("%q is not an absolute http(s) URL", s)
+	}
+	return u, nil
 }
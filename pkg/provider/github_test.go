@@ -15,7 +15,13 @@
 package provider
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestGitHub_GetResponse(t *testing.T) {
@@ -57,3 +63,32 @@ func TestGitHub_GetPullRequestsListReviews(t *testing.T) {
 	p := GitHubProvider{}
 	p.getPullRequestsListReviews(nil)
 }
+
+func TestGitHub_RepositoriesListByOwner(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/orgs/org/repos":
+			fmt.Fprint(w, `[{"name": "app", "has_issues": true}, {"name": "old", "has_issues": true, "archived": true}, {"name": "wiki", "has_issues": false}]`)
+		case "/users/someone/repos":
+			assert.Equal(t, "owner", r.URL.Query().Get("type"))
+			fmt.Fprint(w, `[{"name": "dotfiles", "has_issues": true}]`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	p, err := NewGitHubEnterprise(context.Background(), "token", srv.URL+"/", srv.URL+"/", "")
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+
+	names, _, err := p.RepositoriesListByOwner(context.Background(), SearchParams{Repo: Repo{Organization: "org"}})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"app"}, names)
+
+	// Users are listed once the organization is not found
+	names, _, err = p.RepositoriesListByOwner(context.Background(), SearchParams{Repo: Repo{Organization: "someone"}})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"dotfiles"}, names)
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/triage-party/pkg/constants"
@@ -96,6 +97,7 @@ func (p *GitLabProvider) getIssues(i []*gitlab.Issue) []*Issue {
 			ClosedAt:  v.ClosedAt,
 			Number:    &v.IID,
 			Milestone: p.getMilestone(v.Milestone),
+			Locked:    &v.DiscussionLocked,
 			ID:        &id,
 			CreatedAt: v.CreatedAt,
 		}
@@ -145,6 +147,16 @@ func (p *GitLabProvider) IssuesListByRepo(ctx context.Context, sp SearchParams)
 	return
 }
 
+// https://docs.gitlab.com/ee/api/issues.html#single-project-issue
+func (p *GitLabProvider) IssuesGet(ctx context.Context, sp SearchParams) (i *Issue, r *Response, err error) {
+	gi, gr, err := p.client.Issues.GetIssue(p.getProjectId(sp.Repo), sp.IssueNumber)
+	if gi != nil {
+		i = p.getIssues([]*gitlab.Issue{gi})[0]
+	}
+	r = p.getResponse(gr)
+	return
+}
+
 func (p *GitLabProvider) getListIssueNotesOptions(sp SearchParams) *gitlab.ListIssueNotesOptions {
 	return &gitlab.ListIssueNotesOptions{
 		ListOptions: p.getListOptions(sp.IssueListCommentsOptions.ListOptions),
@@ -277,7 +289,9 @@ func (p *GitLabProvider) getPullRequest(v *gitlab.MergeRequest) *PullRequest {
 		ID:        &id,
 		Number:    &v.IID,
 		Milestone: p.getMilestone(v.Milestone),
+		Locked:    &v.DiscussionLocked,
 		HTMLURL:   &v.WebURL,
+		Base:      &PullRequestBranch{Ref: &v.TargetBranch},
 	}
 	return m
 }
@@ -357,6 +371,143 @@ func (p *GitLabProvider) PullRequestsListReviews(ctx context.Context, sp SearchP
 	return
 }
 
+// https://docs.gitlab.com/ee/api/labels.html#list-labels
+func (p *GitLabProvider) IssuesListLabels(ctx context.Context, sp SearchParams) ([]*Label, *Response, error) {
+	gl, gr, err := p.client.Labels.ListLabels(p.getProjectId(sp.Repo), &gitlab.ListLabelsOptions{ListOptions: p.getListOptions(sp.ListOptions)})
+	ls := make([]*Label, len(gl))
+	for k, v := range gl {
+		id := int64(v.ID)
+		name := v.Name
+		color := strings.TrimPrefix(v.Color, "#")
+		desc := v.Description
+		ls[k] = &Label{ID: &id, Name: &name, Color: &color, Description: &desc}
+	}
+	return ls, p.getResponse(gr), err
+}
+
+func (p *GitLabProvider) RepositoriesGetRequiredStatusChecks(ctx context.Context, sp SearchParams, branch string) ([]string, *Response, error) {
+	return nil, nil, fmt.Errorf("required checks are not supported for GitLab")
+}
+
+func (p *GitLabProvider) RepositoriesListReportedChecks(ctx context.Context, sp SearchParams, ref string) ([]string, *Response, error) {
+	return nil, nil, fmt.Errorf("required checks are not supported for GitLab")
+}
+
+func (p *GitLabProvider) TeamsListMembers(ctx context.Context, sp SearchParams, slug string) ([]*User, *Response, error) {
+	return nil, nil, fmt.Errorf("teams are not supported for GitLab")
+}
+
+func (p *GitLabProvider) RepositoriesListByOwner(ctx context.Context, sp SearchParams) ([]string, *Response, error) {
+	return nil, nil, fmt.Errorf("listing repositories by owner is not supported for GitLab")
+}
+
+func (p *GitLabProvider) SearchIssues(ctx context.Context, sp SearchParams) ([]*Issue, *Response, error) {
+	return nil, nil, fmt.Errorf("search queries are not supported for GitLab")
+}
+
+// https://docs.gitlab.com/ee/api/issues.html#edit-issue
+func (p *GitLabProvider) IssuesAddLabelsToIssue(ctx context.Context, sp SearchParams, labels []string) (*Response, error) {
+	if sp.PullRequest {
+		return p.editMergeRequestLabels(sp, labels, nil)
+	}
+	ls := gitlab.Labels(labels)
+	_, gr, err := p.client.Issues.UpdateIssue(p.getProjectId(sp.Repo), sp.IssueNumber, &gitlab.UpdateIssueOptions{AddLabels: &ls})
+	return p.getResponse(gr), err
+}
+
+func (p *GitLabProvider) IssuesRemoveLabelForIssue(ctx context.Context, sp SearchParams, label string) (*Response, error) {
+	if sp.PullRequest {
+		return p.editMergeRequestLabels(sp, nil, []string{label})
+	}
+	ls := gitlab.Labels{label}
+	_, gr, err := p.client.Issues.UpdateIssue(p.getProjectId(sp.Repo), sp.IssueNumber, &gitlab.UpdateIssueOptions{RemoveLabels: &ls})
+	return p.getResponse(gr), err
+}
+
+// editMergeRequestLabels adds and removes merge request labels. The merge request API only replaces the full set.
+// https://docs.gitlab.com/ee/api/merge_requests.html#update-mr
+func (p *GitLabProvider) editMergeRequestLabels(sp SearchParams, add []string, remove []string) (*Response, error) {
+	mr, gr, err := p.client.MergeRequests.GetMergeRequest(p.getProjectId(sp.Repo), sp.IssueNumber, &gitlab.GetMergeRequestsOptions{})
+	if err != nil {
+		return p.getResponse(gr), err
+	}
+
+	removed := map[string]bool{}
+	for _, l := range remove {
+		removed[l] = true
+	}
+
+	ls := gitlab.Labels{}
+	for _, l := range append(mr.Labels, add...) {
+		if !removed[l] {
+			ls = append(ls, l)
+		}
+	}
+
+	_, gr, err = p.client.MergeRequests.UpdateMergeRequest(p.getProjectId(sp.Repo), sp.IssueNumber, &gitlab.UpdateMergeRequestOptions{Labels: &ls})
+	return p.getResponse(gr), err
+}
+
+func (p *GitLabProvider) IssuesEdit(ctx context.Context, sp SearchParams, req *IssueRequest) (*Response, error) {
+	var event *string
+	if req.State != nil {
+		e := "reopen"
+		if *req.State == constants.ClosedState {
+			e = "close"
+		}
+		event = &e
+	}
+
+	var assignees []int
+	if req.Assignees != nil {
+		for _, login := range *req.Assignees {
+			us, gr, err := p.client.Users.ListUsers(&gitlab.ListUsersOptions{Username: &login})
+			if err != nil {
+				return p.getResponse(gr), err
+			}
+			if len(us) == 0 {
+				return p.getResponse(gr), fmt.Errorf("unknown user: %q", login)
+			}
+			assignees = append(assignees, us[0].ID)
+		}
+	}
+
+	if sp.PullRequest {
+		opt := &gitlab.UpdateMergeRequestOptions{StateEvent: event, AssigneeIDs: assignees}
+		_, gr, err := p.client.MergeRequests.UpdateMergeRequest(p.getProjectId(sp.Repo), sp.IssueNumber, opt)
+		return p.getResponse(gr), err
+	}
+
+	opt := &gitlab.UpdateIssueOptions{StateEvent: event, AssigneeIDs: assignees}
+	_, gr, err := p.client.Issues.UpdateIssue(p.getProjectId(sp.Repo), sp.IssueNumber, opt)
+	return p.getResponse(gr), err
+}
+
+// https://docs.gitlab.com/ee/api/members.html#get-a-member-of-a-group-or-project
+func (p *GitLabProvider) RepositoriesGetPermissionLevel(ctx context.Context, sp SearchParams, user string) (string, *Response, error) {
+	us, gr, err := p.client.Users.ListUsers(&gitlab.ListUsersOptions{Username: &user})
+	if err != nil {
+		return NoPermission, p.getResponse(gr), err
+	}
+	if len(us) == 0 {
+		return NoPermission, p.getResponse(gr), fmt.Errorf("unknown user: %q", user)
+	}
+
+	m, gr, err := p.client.ProjectMembers.GetProjectMember(p.getProjectId(sp.Repo), us[0].ID)
+	if err != nil {
+		return NoPermission, p.getResponse(gr), err
+	}
+
+	switch {
+	case m.AccessLevel >= gitlab.MaintainerPermissions:
+		return AdminPermission, p.getResponse(gr), nil
+	case m.AccessLevel >= gitlab.DeveloperPermissions:
+		return WritePermission, p.getResponse(gr), nil
+	default:
+		return ReadPermission, p.getResponse(gr), nil
+	}
+}
+
 // https://gitlab.com/gitlab-org/gitlab-foss/-/issues/28342#note_23852124
 func (p *GitLabProvider) getProjectId(repo Repo) string {
 	var u string
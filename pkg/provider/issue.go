@@ -116,6 +116,22 @@ func (i *Issue) GetHTMLURL() string {
 	return *i.HTMLURL
 }
 
+// GetLocked returns the Locked field if it's non-nil, zero value otherwise.
+func (i *Issue) GetLocked() bool {
+	if i == nil || i.Locked == nil {
+		return false
+	}
+	return *i.Locked
+}
+
+// GetActiveLockReason returns the ActiveLockReason field if it's non-nil, zero value otherwise.
+func (i *Issue) GetActiveLockReason() string {
+	if i == nil || i.ActiveLockReason == nil {
+		return ""
+	}
+	return *i.ActiveLockReason
+}
+
 // GetID returns the ID field if it's non-nil, zero value otherwise.
 func (i *Issue) GetID() int64 {
 	if i == nil || i.ID == nil {
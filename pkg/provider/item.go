@@ -25,6 +25,8 @@ type IItem interface {
 	GetHTMLURL() string
 	GetCreatedAt() time.Time
 	GetID() int64
+	GetLocked() bool
+	GetActiveLockReason() string
 	GetMilestone() *Milestone
 	GetNumber() int
 	GetClosedAt() time.Time
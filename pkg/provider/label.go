@@ -30,3 +30,10 @@ func (a *Label) GetName() string {
 	}
 	return *a.Name
 }
+
+func (a *Label) GetColor() string {
+	if a == nil || a.Color == nil {
+		return ""
+	}
+	return *a.Color
+}
@@ -0,0 +1,189 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+)
+
+// limitProvider caps the number of in-flight requests to the wrapped provider
+type limitProvider struct {
+	p   Provider
+	sem chan struct{}
+}
+
+// WithConcurrencyLimit wraps a provider so that at most n requests are in flight at once.
+// The limit is shared by every caller of the returned provider. n <= 0 disables the limit.
+func WithConcurrencyLimit(p Provider, n int) Provider {
+	if n <= 0 {
+		return p
+	}
+	return &limitProvider{p: p, sem: make(chan struct{}, n)}
+}
+
+// do runs f once a request slot is available
+func (l *limitProvider) do(ctx context.Context, f func() error) error {
+	select {
+	case l.sem <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-l.sem }()
+	return f()
+}
+
+func (l *limitProvider) IssuesListByRepo(ctx context.Context, sp SearchParams) (is []*Issue, resp *Response, err error) {
+	err = l.do(ctx, func() error {
+		is, resp, err = l.p.IssuesListByRepo(ctx, sp)
+		return err
+	})
+	return is, resp, err
+}
+
+func (l *limitProvider) IssuesListComments(ctx context.Context, sp SearchParams) (cs []*IssueComment, resp *Response, err error) {
+	err = l.do(ctx, func() error {
+		cs, resp, err = l.p.IssuesListComments(ctx, sp)
+		return err
+	})
+	return cs, resp, err
+}
+
+func (l *limitProvider) IssuesListIssueTimeline(ctx context.Context, sp SearchParams) (ts []*Timeline, resp *Response, err error) {
+	err = l.do(ctx, func() error {
+		ts, resp, err = l.p.IssuesListIssueTimeline(ctx, sp)
+		return err
+	})
+	return ts, resp, err
+}
+
+func (l *limitProvider) IssuesGet(ctx context.Context, sp SearchParams) (i *Issue, resp *Response, err error) {
+	err = l.do(ctx, func() error {
+		i, resp, err = l.p.IssuesGet(ctx, sp)
+		return err
+	})
+	return i, resp, err
+}
+
+func (l *limitProvider) PullRequestsList(ctx context.Context, sp SearchParams) (prs []*PullRequest, resp *Response, err error) {
+	err = l.do(ctx, func() error {
+		prs, resp, err = l.p.PullRequestsList(ctx, sp)
+		return err
+	})
+	return prs, resp, err
+}
+
+func (l *limitProvider) PullRequestsGet(ctx context.Context, sp SearchParams) (pr *PullRequest, resp *Response, err error) {
+	err = l.do(ctx, func() error {
+		pr, resp, err = l.p.PullRequestsGet(ctx, sp)
+		return err
+	})
+	return pr, resp, err
+}
+
+func (l *limitProvider) PullRequestsListComments(ctx context.Context, sp SearchParams) (cs []*PullRequestComment, resp *Response, err error) {
+	err = l.do(ctx, func() error {
+		cs, resp, err = l.p.PullRequestsListComments(ctx, sp)
+		return err
+	})
+	return cs, resp, err
+}
+
+func (l *limitProvider) PullRequestsListReviews(ctx context.Context, sp SearchParams) (rs []*PullRequestReview, resp *Response, err error) {
+	err = l.do(ctx, func() error {
+		rs, resp, err = l.p.PullRequestsListReviews(ctx, sp)
+		return err
+	})
+	return rs, resp, err
+}
+
+func (l *limitProvider) IssuesListLabels(ctx context.Context, sp SearchParams) (ls []*Label, resp *Response, err error) {
+	err = l.do(ctx, func() error {
+		ls, resp, err = l.p.IssuesListLabels(ctx, sp)
+		return err
+	})
+	return ls, resp, err
+}
+
+func (l *limitProvider) SearchIssues(ctx context.Context, sp SearchParams) (is []*Issue, resp *Response, err error) {
+	err = l.do(ctx, func() error {
+		is, resp, err = l.p.SearchIssues(ctx, sp)
+		return err
+	})
+	return is, resp, err
+}
+
+func (l *limitProvider) RepositoriesGetRequiredStatusChecks(ctx context.Context, sp SearchParams, branch string) (cs []string, resp *Response, err error) {
+	err = l.do(ctx, func() error {
+		cs, resp, err = l.p.RepositoriesGetRequiredStatusChecks(ctx, sp, branch)
+		return err
+	})
+	return cs, resp, err
+}
+
+func (l *limitProvider) RepositoriesListReportedChecks(ctx context.Context, sp SearchParams, ref string) (cs []string, resp *Response, err error) {
+	err = l.do(ctx, func() error {
+		cs, resp, err = l.p.RepositoriesListReportedChecks(ctx, sp, ref)
+		return err
+	})
+	return cs, resp, err
+}
+
+func (l *limitProvider) RepositoriesListByOwner(ctx context.Context, sp SearchParams) (names []string, resp *Response, err error) {
+	err = l.do(ctx, func() error {
+		names, resp, err = l.p.RepositoriesListByOwner(ctx, sp)
+		return err
+	})
+	return names, resp, err
+}
+
+func (l *limitProvider) TeamsListMembers(ctx context.Context, sp SearchParams, slug string) (us []*User, resp *Response, err error) {
+	err = l.do(ctx, func() error {
+		us, resp, err = l.p.TeamsListMembers(ctx, sp, slug)
+		return err
+	})
+	return us, resp, err
+}
+
+func (l *limitProvider) IssuesAddLabelsToIssue(ctx context.Context, sp SearchParams, labels []string) (resp *Response, err error) {
+	err = l.do(ctx, func() error {
+		resp, err = l.p.IssuesAddLabelsToIssue(ctx, sp, labels)
+		return err
+	})
+	return resp, err
+}
+
+func (l *limitProvider) IssuesRemoveLabelForIssue(ctx context.Context, sp SearchParams, label string) (resp *Response, err error) {
+	err = l.do(ctx, func() error {
+		resp, err = l.p.IssuesRemoveLabelForIssue(ctx, sp, label)
+		return err
+	})
+	return resp, err
+}
+
+func (l *limitProvider) IssuesEdit(ctx context.Context, sp SearchParams, req *IssueRequest) (resp *Response, err error) {
+	err = l.do(ctx, func() error {
+		resp, err = l.p.IssuesEdit(ctx, sp, req)
+		return err
+	})
+	return resp, err
+}
+
+func (l *limitProvider) RepositoriesGetPermissionLevel(ctx context.Context, sp SearchParams, user string) (level string, resp *Response, err error) {
+	err = l.do(ctx, func() error {
+		level, resp, err = l.p.RepositoriesGetPermissionLevel(ctx, sp, user)
+		return err
+	})
+	return level, resp, err
+}
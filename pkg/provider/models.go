@@ -69,6 +69,16 @@ type SearchParams struct {
 	SearchKey   string
 	IssueNumber int
 	Fetch       bool
+	Query       string
+
+	// ClosedWindow limits closed items to those updated this recently, if the filters allow it
+	ClosedWindow time.Duration
+
+	// PullRequest is set if IssueNumber refers to a pull request, rather than an issue
+	PullRequest bool
+
+	// CommentCount is how many comments the item has, so that only the most recent may be fetched
+	CommentCount int
 
 	IssueListByRepoOptions   IssueListByRepoOptions
 	IssueListCommentsOptions IssueListCommentsOptions
@@ -116,6 +126,22 @@ type IssueListByRepoOptions struct {
 	ListOptions
 }
 
+// IssueRequest represents a request to edit an issue or PR
+type IssueRequest struct {
+	// State is either "open" or "closed"
+	State *string
+	// Assignees replaces the set of assignees
+	Assignees *[]string
+}
+
+// Permission levels returned by RepositoriesGetPermissionLevel
+const (
+	AdminPermission = "admin"
+	WritePermission = "write"
+	ReadPermission  = "read"
+	NoPermission    = "none"
+)
+
 // abstraction model for github.ListOptions struct
 // ListOptions specifies the optional parameters to various List methods that
 // support offset pagination.
@@ -0,0 +1,103 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrOffline is returned by the offline provider for every request
+var ErrOffline = errors.New("offline: no API requests are made when refreshes are disabled")
+
+// offlineProvider stands in for a real provider when serving purely from cache
+type offlineProvider struct{}
+
+// Offline returns a provider which fails every request with ErrOffline
+func Offline() Provider {
+	return &offlineProvider{}
+}
+
+func (o *offlineProvider) IssuesListByRepo(context.Context, SearchParams) ([]*Issue, *Response, error) {
+	return nil, nil, ErrOffline
+}
+
+func (o *offlineProvider) IssuesListComments(context.Context, SearchParams) ([]*IssueComment, *Response, error) {
+	return nil, nil, ErrOffline
+}
+
+func (o *offlineProvider) IssuesListIssueTimeline(context.Context, SearchParams) ([]*Timeline, *Response, error) {
+	return nil, nil, ErrOffline
+}
+
+func (o *offlineProvider) PullRequestsList(context.Context, SearchParams) ([]*PullRequest, *Response, error) {
+	return nil, nil, ErrOffline
+}
+
+func (o *offlineProvider) IssuesGet(context.Context, SearchParams) (*Issue, *Response, error) {
+	return nil, nil, ErrOffline
+}
+
+func (o *offlineProvider) PullRequestsGet(context.Context, SearchParams) (*PullRequest, *Response, error) {
+	return nil, nil, ErrOffline
+}
+
+func (o *offlineProvider) PullRequestsListComments(context.Context, SearchParams) ([]*PullRequestComment, *Response, error) {
+	return nil, nil, ErrOffline
+}
+
+func (o *offlineProvider) PullRequestsListReviews(context.Context, SearchParams) ([]*PullRequestReview, *Response, error) {
+	return nil, nil, ErrOffline
+}
+
+func (o *offlineProvider) IssuesListLabels(context.Context, SearchParams) ([]*Label, *Response, error) {
+	return nil, nil, ErrOffline
+}
+
+func (o *offlineProvider) SearchIssues(context.Context, SearchParams) ([]*Issue, *Response, error) {
+	return nil, nil, ErrOffline
+}
+
+func (o *offlineProvider) RepositoriesGetRequiredStatusChecks(context.Context, SearchParams, string) ([]string, *Response, error) {
+	return nil, nil, ErrOffline
+}
+
+func (o *offlineProvider) RepositoriesListReportedChecks(context.Context, SearchParams, string) ([]string, *Response, error) {
+	return nil, nil, ErrOffline
+}
+
+func (o *offlineProvider) RepositoriesListByOwner(context.Context, SearchParams) ([]string, *Response, error) {
+	return nil, nil, ErrOffline
+}
+
+func (o *offlineProvider) TeamsListMembers(context.Context, SearchParams, string) ([]*User, *Response, error) {
+	return nil, nil, ErrOffline
+}
+
+func (o *offlineProvider) IssuesAddLabelsToIssue(context.Context, SearchParams, []string) (*Response, error) {
+	return nil, ErrOffline
+}
+
+func (o *offlineProvider) IssuesRemoveLabelForIssue(context.Context, SearchParams, string) (*Response, error) {
+	return nil, ErrOffline
+}
+
+func (o *offlineProvider) IssuesEdit(context.Context, SearchParams, *IssueRequest) (*Response, error) {
+	return nil, ErrOffline
+}
+
+func (o *offlineProvider) RepositoriesGetPermissionLevel(context.Context, SearchParams, string) (string, *Response, error) {
+	return "", nil, ErrOffline
+}
@@ -25,12 +25,25 @@ import (
 
 type Provider interface {
 	IssuesListByRepo(ctx context.Context, sp SearchParams) ([]*Issue, *Response, error)
+	IssuesGet(ctx context.Context, sp SearchParams) (*Issue, *Response, error)
 	IssuesListComments(ctx context.Context, sp SearchParams) ([]*IssueComment, *Response, error)
 	IssuesListIssueTimeline(ctx context.Context, sp SearchParams) ([]*Timeline, *Response, error)
 	PullRequestsList(ctx context.Context, sp SearchParams) ([]*PullRequest, *Response, error)
 	PullRequestsGet(ctx context.Context, sp SearchParams) (*PullRequest, *Response, error)
 	PullRequestsListComments(ctx context.Context, sp SearchParams) ([]*PullRequestComment, *Response, error)
 	PullRequestsListReviews(ctx context.Context, sp SearchParams) ([]*PullRequestReview, *Response, error)
+	IssuesListLabels(ctx context.Context, sp SearchParams) ([]*Label, *Response, error)
+	SearchIssues(ctx context.Context, sp SearchParams) ([]*Issue, *Response, error)
+	RepositoriesGetRequiredStatusChecks(ctx context.Context, sp SearchParams, branch string) ([]string, *Response, error)
+	RepositoriesListReportedChecks(ctx context.Context, sp SearchParams, ref string) ([]string, *Response, error)
+	TeamsListMembers(ctx context.Context, sp SearchParams, slug string) ([]*User, *Response, error)
+	RepositoriesListByOwner(ctx context.Context, sp SearchParams) ([]string, *Response, error)
+
+	// Write operations, used by the optional write mode
+	IssuesAddLabelsToIssue(ctx context.Context, sp SearchParams, labels []string) (*Response, error)
+	IssuesRemoveLabelForIssue(ctx context.Context, sp SearchParams, label string) (*Response, error)
+	IssuesEdit(ctx context.Context, sp SearchParams, req *IssueRequest) (*Response, error)
+	RepositoriesGetPermissionLevel(ctx context.Context, sp SearchParams, user string) (string, *Response, error)
 }
 
 type Config struct {
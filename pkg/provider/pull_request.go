@@ -62,20 +62,25 @@ type PullRequest struct {
 	AuthorAssociation   *string    `json:"author_association,omitempty"`
 	NodeID              *string    `json:"node_id,omitempty"`
 	RequestedReviewers  []*User    `json:"requested_reviewers,omitempty"`
-
-	// RequestedTeams is populated as part of the PullRequestEvent.
-	// See, https://developer.github.com/v3/activity/events/types/#pullrequestevent for an example.
-	//RequestedTeams []*Team `json:"requested_teams,omitempty"`
+	RequestedTeams      []*Team    `json:"requested_teams,omitempty"`
 	//
 	//Links *PRLinks           `json:"_links,omitempty"`
-	//Head  *PullRequestBranch `json:"head,omitempty"`
-	//Base  *PullRequestBranch `json:"base,omitempty"`
+	Head *PullRequestBranch `json:"head,omitempty"`
+	Base *PullRequestBranch `json:"base,omitempty"`
 
 	// ActiveLockReason is populated only when LockReason is provided while locking the pull request.
 	// Possible values are: "off-topic", "too heated", "resolved", and "spam".
 	ActiveLockReason *string `json:"active_lock_reason,omitempty"`
 }
 
+// GetAdditions returns the Additions field if it's non-nil, zero value otherwise.
+func (p *PullRequest) GetAdditions() int {
+	if p == nil || p.Additions == nil {
+		return 0
+	}
+	return *p.Additions
+}
+
 // GetAssignee returns the Assignee field.
 func (p *PullRequest) GetAssignee() *User {
 	if p == nil {
@@ -93,12 +98,12 @@ func (p *PullRequest) GetAuthorAssociation() string {
 }
 
 // GetBase returns the Base field.
-//func (p *PullRequest) GetBase() *PullRequestBranch {
-//	if p == nil {
-//		return nil
-//	}
-//	return p.Base
-//}
+func (p *PullRequest) GetBase() *PullRequestBranch {
+	if p == nil {
+		return nil
+	}
+	return p.Base
+}
 
 // GetBody returns the Body field if it's non-nil, zero value otherwise.
 func (p *PullRequest) GetBody() string {
@@ -108,6 +113,14 @@ func (p *PullRequest) GetBody() string {
 	return *p.Body
 }
 
+// GetChangedFiles returns the ChangedFiles field if it's non-nil, zero value otherwise.
+func (p *PullRequest) GetChangedFiles() int {
+	if p == nil || p.ChangedFiles == nil {
+		return 0
+	}
+	return *p.ChangedFiles
+}
+
 // GetClosedAt returns the ClosedAt field if it's non-nil, zero value otherwise.
 func (p *PullRequest) GetClosedAt() time.Time {
 	if p == nil || p.ClosedAt == nil {
@@ -132,6 +145,14 @@ func (p *PullRequest) GetCreatedAt() time.Time {
 	return *p.CreatedAt
 }
 
+// GetDeletions returns the Deletions field if it's non-nil, zero value otherwise.
+func (p *PullRequest) GetDeletions() int {
+	if p == nil || p.Deletions == nil {
+		return 0
+	}
+	return *p.Deletions
+}
+
 // GetDraft returns the Draft field if it's non-nil, zero value otherwise.
 func (p *PullRequest) GetDraft() bool {
 	if p == nil || p.Draft == nil {
@@ -141,12 +162,12 @@ func (p *PullRequest) GetDraft() bool {
 }
 
 // GetHead returns the Head field.
-//func (p *PullRequest) GetHead() *PullRequestBranch {
-//	if p == nil {
-//		return nil
-//	}
-//	return p.Head
-//}
+func (p *PullRequest) GetHead() *PullRequestBranch {
+	if p == nil {
+		return nil
+	}
+	return p.Head
+}
 
 // GetHTMLURL returns the HTMLURL field if it's non-nil, zero value otherwise.
 func (p *PullRequest) GetHTMLURL() string {
@@ -156,6 +177,22 @@ func (p *PullRequest) GetHTMLURL() string {
 	return *p.HTMLURL
 }
 
+// GetLocked returns the Locked field if it's non-nil, zero value otherwise.
+func (p *PullRequest) GetLocked() bool {
+	if p == nil || p.Locked == nil {
+		return false
+	}
+	return *p.Locked
+}
+
+// GetActiveLockReason returns the ActiveLockReason field if it's non-nil, zero value otherwise.
+func (p *PullRequest) GetActiveLockReason() string {
+	if p == nil || p.ActiveLockReason == nil {
+		return ""
+	}
+	return *p.ActiveLockReason
+}
+
 // GetID returns the ID field if it's non-nil, zero value otherwise.
 func (p *PullRequest) GetID() int64 {
 	if p == nil || p.ID == nil {
@@ -164,6 +201,14 @@ func (p *PullRequest) GetID() int64 {
 	return *p.ID
 }
 
+// GetMergeableState returns the MergeableState field if it's non-nil, zero value otherwise.
+func (p *PullRequest) GetMergeableState() string {
+	if p == nil || p.MergeableState == nil {
+		return ""
+	}
+	return *p.MergeableState
+}
+
 // GetMerged returns the Merged field if it's non-nil, zero value otherwise.
 func (p *PullRequest) GetMerged() bool {
 	if p == nil || p.Merged == nil {
@@ -239,3 +284,47 @@ func (p *PullRequest) GetUser() *User {
 func (p PullRequest) String() string {
 	return Stringify(p)
 }
+
+// PullRequestBranch represents a base or head branch in a GitHub pull request.
+type PullRequestBranch struct {
+	Ref  *string           `json:"ref,omitempty"`
+	SHA  *string           `json:"sha,omitempty"`
+	Repo *BranchRepository `json:"repo,omitempty"`
+}
+
+// BranchRepository is the repository of a pull request branch. Only the fields used are kept, as it is cached with every pull request.
+type BranchRepository struct {
+	DefaultBranch *string `json:"default_branch,omitempty"`
+}
+
+// GetDefaultBranch returns the DefaultBranch field if it's non-nil, zero value otherwise.
+func (r *BranchRepository) GetDefaultBranch() string {
+	if r == nil || r.DefaultBranch == nil {
+		return ""
+	}
+	return *r.DefaultBranch
+}
+
+// GetRepo returns the Repo field.
+func (p *PullRequestBranch) GetRepo() *BranchRepository {
+	if p == nil {
+		return nil
+	}
+	return p.Repo
+}
+
+// GetRef returns the Ref field if it's non-nil, zero value otherwise.
+func (p *PullRequestBranch) GetRef() string {
+	if p == nil || p.Ref == nil {
+		return ""
+	}
+	return *p.Ref
+}
+
+// GetSHA returns the SHA field if it's non-nil, zero value otherwise.
+func (p *PullRequestBranch) GetSHA() string {
+	if p == nil || p.SHA == nil {
+		return ""
+	}
+	return *p.SHA
+}
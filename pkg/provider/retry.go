@@ -0,0 +1,339 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/google/go-github/v33/github"
+	"github.com/google/triage-party/pkg/metrics"
+	"github.com/xanzy/go-gitlab"
+	"k8s.io/klog/v2"
+)
+
+// ErrorClass describes how a failed API call should be handled
+type ErrorClass int
+
+const (
+	// UnknownError is not recognized, and is not retried
+	UnknownError ErrorClass = iota
+	// TransientError is a DNS, connection, or timeout failure
+	TransientError
+	// ServerError is a 5xx response from the API
+	ServerError
+	// ClientError is a 4xx response from the API, such as a bad token or missing repository
+	ClientError
+)
+
+func (c ErrorClass) String() string {
+	switch c {
+	case TransientError:
+		return "transient"
+	case ServerError:
+		return "server"
+	case ClientError:
+		return "client"
+	default:
+		return "unknown"
+	}
+}
+
+// Retryable returns true if a request with this class of error may succeed later
+func (c ErrorClass) Retryable() bool {
+	return c == TransientError || c == ServerError
+}
+
+var (
+	// maxAttempts is the number of times a retryable request is attempted
+	maxAttempts = 4
+	// retryDelay is the initial delay between attempts, doubled each time
+	retryDelay = 2 * time.Second
+)
+
+// statusCode returns the HTTP status code for an API error, or 0
+func statusCode(err error) int {
+	var ge *github.ErrorResponse
+	if errors.As(err, &ge) && ge.Response != nil {
+		return ge.Response.StatusCode
+	}
+
+	var rle *github.RateLimitError
+	if errors.As(err, &rle) && rle.Response != nil {
+		return rle.Response.StatusCode
+	}
+
+	var are *github.AbuseRateLimitError
+	if errors.As(err, &are) && are.Response != nil {
+		return are.Response.StatusCode
+	}
+
+	var gle *gitlab.ErrorResponse
+	if errors.As(err, &gle) && gle.Response != nil {
+		return gle.Response.StatusCode
+	}
+	return 0
+}
+
+// Classify determines the class of an error returned by a provider
+func Classify(err error) ErrorClass {
+	if err == nil {
+		return UnknownError
+	}
+
+	if code := statusCode(err); code != 0 {
+		switch {
+		case code >= 500:
+			return ServerError
+		case code >= 400:
+			return ClientError
+		}
+		return UnknownError
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return UnknownError
+	}
+
+	var de *net.DNSError
+	if errors.As(err, &de) {
+		return TransientError
+	}
+
+	var oe *net.OpError
+	if errors.As(err, &oe) {
+		return TransientError
+	}
+
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return TransientError
+	}
+
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, context.DeadlineExceeded) {
+		return TransientError
+	}
+
+	return UnknownError
+}
+
+// explain adds an actionable hint to client errors
+func explain(err error) error {
+	if AccessDenied(err) {
+		return fmt.Errorf("%w (access denied: the token lacks permission to read this repository)", err)
+	}
+
+	switch statusCode(err) {
+	case http.StatusUnauthorized:
+		return fmt.Errorf("%w (check that the API token is valid)", err)
+	case http.StatusForbidden:
+		return fmt.Errorf("%w (check token permissions, or wait for the rate limit to reset)", err)
+	case http.StatusNotFound:
+		return fmt.Errorf("%w (check that the repository exists and the token can access it)", err)
+	}
+	return err
+}
+
+// retry calls f until it succeeds, returns a non-retryable error, or runs out of attempts
+func retry(ctx context.Context, name string, f func() error) error {
+	delay := retryDelay
+	for attempt := 1; ; attempt++ {
+		metrics.Count(metrics.APICalls, 1, metrics.Tag("call", name))
+		err := f()
+		if err == nil {
+			return nil
+		}
+
+		class := Classify(err)
+		metrics.Count(metrics.APIErrors, 1, metrics.Tag("call", name), metrics.Tag("class", class.String()))
+		if class == ClientError {
+			return explain(err)
+		}
+
+		if !class.Retryable() || attempt >= maxAttempts {
+			if class.Retryable() {
+				return fmt.Errorf("%s failed after %d attempts: %w", name, attempt, err)
+			}
+			return err
+		}
+
+		klog.Warningf("%s: %s error (attempt %d/%d), retrying in %s: %v", name, class, attempt, maxAttempts, delay, err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// retryProvider retries transient and server errors from the wrapped provider
+type retryProvider struct {
+	p Provider
+}
+
+// WithRetries wraps a provider so that network and 5xx errors are retried with backoff
+func WithRetries(p Provider) Provider {
+	return &retryProvider{p: p}
+}
+
+func (r *retryProvider) IssuesListByRepo(ctx context.Context, sp SearchParams) (is []*Issue, resp *Response, err error) {
+	err = retry(ctx, "IssuesListByRepo", func() error {
+		is, resp, err = r.p.IssuesListByRepo(ctx, sp)
+		return err
+	})
+	return is, resp, err
+}
+
+func (r *retryProvider) IssuesListComments(ctx context.Context, sp SearchParams) (cs []*IssueComment, resp *Response, err error) {
+	err = retry(ctx, "IssuesListComments", func() error {
+		cs, resp, err = r.p.IssuesListComments(ctx, sp)
+		return err
+	})
+	return cs, resp, err
+}
+
+func (r *retryProvider) IssuesListIssueTimeline(ctx context.Context, sp SearchParams) (ts []*Timeline, resp *Response, err error) {
+	err = retry(ctx, "IssuesListIssueTimeline", func() error {
+		ts, resp, err = r.p.IssuesListIssueTimeline(ctx, sp)
+		return err
+	})
+	return ts, resp, err
+}
+
+func (r *retryProvider) IssuesGet(ctx context.Context, sp SearchParams) (i *Issue, resp *Response, err error) {
+	err = retry(ctx, "IssuesGet", func() error {
+		i, resp, err = r.p.IssuesGet(ctx, sp)
+		return err
+	})
+	return i, resp, err
+}
+
+func (r *retryProvider) PullRequestsList(ctx context.Context, sp SearchParams) (prs []*PullRequest, resp *Response, err error) {
+	err = retry(ctx, "PullRequestsList", func() error {
+		prs, resp, err = r.p.PullRequestsList(ctx, sp)
+		return err
+	})
+	return prs, resp, err
+}
+
+func (r *retryProvider) PullRequestsGet(ctx context.Context, sp SearchParams) (pr *PullRequest, resp *Response, err error) {
+	err = retry(ctx, "PullRequestsGet", func() error {
+		pr, resp, err = r.p.PullRequestsGet(ctx, sp)
+		return err
+	})
+	return pr, resp, err
+}
+
+func (r *retryProvider) PullRequestsListComments(ctx context.Context, sp SearchParams) (cs []*PullRequestComment, resp *Response, err error) {
+	err = retry(ctx, "PullRequestsListComments", func() error {
+		cs, resp, err = r.p.PullRequestsListComments(ctx, sp)
+		return err
+	})
+	return cs, resp, err
+}
+
+func (r *retryProvider) PullRequestsListReviews(ctx context.Context, sp SearchParams) (rs []*PullRequestReview, resp *Response, err error) {
+	err = retry(ctx, "PullRequestsListReviews", func() error {
+		rs, resp, err = r.p.PullRequestsListReviews(ctx, sp)
+		return err
+	})
+	return rs, resp, err
+}
+
+func (r *retryProvider) IssuesListLabels(ctx context.Context, sp SearchParams) (ls []*Label, resp *Response, err error) {
+	err = retry(ctx, "IssuesListLabels", func() error {
+		ls, resp, err = r.p.IssuesListLabels(ctx, sp)
+		return err
+	})
+	return ls, resp, err
+}
+
+func (r *retryProvider) SearchIssues(ctx context.Context, sp SearchParams) (is []*Issue, resp *Response, err error) {
+	err = retry(ctx, "SearchIssues", func() error {
+		is, resp, err = r.p.SearchIssues(ctx, sp)
+		return err
+	})
+	return is, resp, err
+}
+
+func (r *retryProvider) RepositoriesGetRequiredStatusChecks(ctx context.Context, sp SearchParams, branch string) (cs []string, resp *Response, err error) {
+	err = retry(ctx, "RepositoriesGetRequiredStatusChecks", func() error {
+		cs, resp, err = r.p.RepositoriesGetRequiredStatusChecks(ctx, sp, branch)
+		return err
+	})
+	return cs, resp, err
+}
+
+func (r *retryProvider) RepositoriesListReportedChecks(ctx context.Context, sp SearchParams, ref string) (cs []string, resp *Response, err error) {
+	err = retry(ctx, "RepositoriesListReportedChecks", func() error {
+		cs, resp, err = r.p.RepositoriesListReportedChecks(ctx, sp, ref)
+		return err
+	})
+	return cs, resp, err
+}
+
+func (r *retryProvider) RepositoriesListByOwner(ctx context.Context, sp SearchParams) (names []string, resp *Response, err error) {
+	err = retry(ctx, "RepositoriesListByOwner", func() error {
+		names, resp, err = r.p.RepositoriesListByOwner(ctx, sp)
+		return err
+	})
+	return names, resp, err
+}
+
+func (r *retryProvider) TeamsListMembers(ctx context.Context, sp SearchParams, slug string) (us []*User, resp *Response, err error) {
+	err = retry(ctx, "TeamsListMembers", func() error {
+		us, resp, err = r.p.TeamsListMembers(ctx, sp, slug)
+		return err
+	})
+	return us, resp, err
+}
+
+func (r *retryProvider) IssuesAddLabelsToIssue(ctx context.Context, sp SearchParams, labels []string) (resp *Response, err error) {
+	err = retry(ctx, "IssuesAddLabelsToIssue", func() error {
+		resp, err = r.p.IssuesAddLabelsToIssue(ctx, sp, labels)
+		return err
+	})
+	return resp, err
+}
+
+func (r *retryProvider) IssuesRemoveLabelForIssue(ctx context.Context, sp SearchParams, label string) (resp *Response, err error) {
+	err = retry(ctx, "IssuesRemoveLabelForIssue", func() error {
+		resp, err = r.p.IssuesRemoveLabelForIssue(ctx, sp, label)
+		return err
+	})
+	return resp, err
+}
+
+func (r *retryProvider) IssuesEdit(ctx context.Context, sp SearchParams, req *IssueRequest) (resp *Response, err error) {
+	err = retry(ctx, "IssuesEdit", func() error {
+		resp, err = r.p.IssuesEdit(ctx, sp, req)
+		return err
+	})
+	return resp, err
+}
+
+func (r *retryProvider) RepositoriesGetPermissionLevel(ctx context.Context, sp SearchParams, user string) (level string, resp *Response, err error) {
+	err = retry(ctx, "RepositoriesGetPermissionLevel", func() error {
+		level, resp, err = r.p.RepositoriesGetPermissionLevel(ctx, sp, user)
+		return err
+	})
+	return level, resp, err
+}
@@ -0,0 +1,99 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v33/github"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassify(t *testing.T) {
+	resp := func(code int) *http.Response {
+		return &http.Response{StatusCode: code, Request: &http.Request{}}
+	}
+
+	tests := []struct {
+		err  error
+		want ErrorClass
+	}{
+		{&net.DNSError{Err: "no such host", Name: "api.github.com"}, TransientError},
+		{fmt.Errorf("get: %w", &net.OpError{Op: "dial", Err: fmt.Errorf("connection refused")}), TransientError},
+		{&github.ErrorResponse{Response: resp(502)}, ServerError},
+		{&github.ErrorResponse{Response: resp(404)}, ClientError},
+		{&github.RateLimitError{Response: resp(403)}, ClientError},
+		{context.Canceled, UnknownError},
+		{fmt.Errorf("something else"), UnknownError},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.want, Classify(tc.err), tc.err.Error())
+	}
+}
+
+func TestRetry(t *testing.T) {
+	retryDelay = time.Millisecond
+
+	calls := 0
+	err := retry(context.Background(), "transient", func() error {
+		calls++
+		if calls < 3 {
+			return &net.DNSError{Err: "temporary failure"}
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	err = retry(context.Background(), "client", func() error {
+		calls++
+		return &github.ErrorResponse{Response: &http.Response{StatusCode: 401, Request: &http.Request{}}}
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestAccessDenied(t *testing.T) {
+	resp := func(code int, scopes ...string) *http.Response {
+		r := &http.Response{StatusCode: code, Request: &http.Request{}, Header: http.Header{}}
+		if scopes != nil {
+			r.Header.Set("X-OAuth-Scopes", scopes[0])
+		}
+		return r
+	}
+
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&github.ErrorResponse{Response: resp(403)}, true},
+		{&github.RateLimitError{Response: resp(403)}, false},
+		{&github.ErrorResponse{Response: resp(404)}, false},
+		{&github.ErrorResponse{Response: resp(404, "public_repo, read:org")}, true},
+		{fmt.Errorf("list: %w", &github.ErrorResponse{Response: resp(404, "repo, read:org")}), false},
+		{&github.ErrorResponse{Response: resp(500)}, false},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.want, AccessDenied(tc.err), tc.err.Error())
+	}
+}
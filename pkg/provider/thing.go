@@ -26,4 +26,5 @@ type Thing struct {
 	Timeline            []*Timeline
 	Reviews             []*PullRequestReview
 	StringBool          map[string]bool
+	Counts              map[string]int
 }
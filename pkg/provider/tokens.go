@@ -0,0 +1,242 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"golang.org/x/oauth2"
+	"k8s.io/klog/v2"
+)
+
+// ReadTokens reads one or more tokens, separated by newlines or commas, from a file or environment variable
+func ReadTokens(path string, envVar string) []string {
+	return splitTokens(ReadToken(path, envVar))
+}
+
+// splitTokens splits a list of tokens separated by whitespace or commas
+func splitTokens(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool { return r == ',' || unicode.IsSpace(r) })
+}
+
+// NewGitHubTokens returns a GitHub provider which moves on to the next token whenever the current one
+// is rate limited. If every token is rate limited, requests wait until the earliest reset.
+func NewGitHubTokens(ctx context.Context, tokens []string, url string, userAgent string) (Provider, error) {
+	if len(tokens) == 1 {
+		return NewGitHub(ctx, tokens[0], url, userAgent)
+	}
+
+	base := http.DefaultTransport
+	if c, ok := ctx.Value(oauth2.HTTPClient).(*http.Client); ok && c.Transport != nil {
+		base = c.Transport
+	}
+	klog.Infof("rotating between %d GitHub tokens", len(tokens))
+	return newGitHub(&http.Client{Transport: newTokenRotator(base, tokens)}, url, userAgent)
+}
+
+// rateState is the last known rate limit of a token for a resource
+type rateState struct {
+	remaining int
+	limit     int
+	reset     time.Time
+}
+
+// limited returns whether the token may not be used until its reset
+func (s *rateState) limited(now time.Time) bool {
+	return s != nil && s.remaining <= 0 && now.Before(s.reset)
+}
+
+// budget returns how many requests the token has left, assuming limit if unknown
+func (s *rateState) budget(now time.Time, limit int) int {
+	switch {
+	case s == nil:
+		return limit
+	case !now.Before(s.reset):
+		return s.limit
+	default:
+		return s.remaining
+	}
+}
+
+// tokenRotator authenticates each request with the first token which is not rate limited
+type tokenRotator struct {
+	base   http.RoundTripper
+	tokens []string
+	now    func() time.Time
+	sleep  func(context.Context, time.Duration) error
+
+	mu      sync.Mutex
+	current int
+	// rates are the last known limits of each token, by resource, such as core or search
+	rates []map[string]*rateState
+}
+
+func newTokenRotator(base http.RoundTripper, tokens []string) *tokenRotator {
+	r := &tokenRotator{
+		base:   base,
+		tokens: tokens,
+		now:    time.Now,
+		sleep:  sleepContext,
+	}
+	for range tokens {
+		r.rates = append(r.rates, map[string]*rateState{})
+	}
+	return r
+}
+
+// sleepContext waits for d, or until the context is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// rateResource returns the rate limit resource a request counts against
+func rateResource(req *http.Request) string {
+	if strings.Contains(req.URL.Path, "/search/") {
+		return "search"
+	}
+	return "core"
+}
+
+// pick returns the first usable token, starting with the current one, or how long until one is usable
+func (r *tokenRotator) pick(res string) (int, time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	var earliest time.Time
+	for k := range r.tokens {
+		i := (r.current + k) % len(r.tokens)
+		s := r.rates[i][res]
+		if !s.limited(now) {
+			r.current = i
+			return i, 0
+		}
+		if earliest.IsZero() || s.reset.Before(earliest) {
+			earliest = s.reset
+		}
+	}
+	return -1, earliest.Sub(now)
+}
+
+// record updates the rate limit of a token from a response, returning whether the request was refused for exceeding it
+func (r *tokenRotator) record(i int, res string, resp *http.Response) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	refused := resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests
+
+	// Secondary rate limits ask the client to back off for a while
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && refused {
+		limit := 0
+		if s := r.rates[i][res]; s != nil {
+			limit = s.limit
+		}
+		r.rates[i][res] = &rateState{limit: limit, reset: now.Add(time.Duration(secs) * time.Second)}
+		return true
+	}
+
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return false
+	}
+	limit, _ := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+	reset, _ := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+
+	r.rates[i][res] = &rateState{remaining: remaining, limit: limit, reset: time.Unix(reset, 0)}
+	return refused && remaining == 0
+}
+
+// rewrite reports the combined budget of every token, so that the GitHub client does not refuse
+// to send requests while other tokens remain. If none do, requests wait within RoundTrip instead.
+func (r *tokenRotator) rewrite(res string, resp *http.Response) {
+	limit, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	budget := 0
+	for i := range r.tokens {
+		budget += r.rates[i][res].budget(now, limit)
+	}
+	if budget < 1 {
+		budget = 1
+	}
+
+	resp.Header.Set("X-RateLimit-Remaining", strconv.Itoa(budget))
+	resp.Header.Set("X-RateLimit-Limit", strconv.Itoa(limit*len(r.tokens)))
+}
+
+// RoundTrip sends a request using the first token which is not rate limited
+func (r *tokenRotator) RoundTrip(req *http.Request) (*http.Response, error) {
+	res := rateResource(req)
+	// Requests with a body which can't be replayed are only sent once
+	replayable := req.Body == nil || req.GetBody != nil
+
+	for attempt := 0; ; attempt++ {
+		i, wait := r.pick(res)
+		if i < 0 {
+			klog.Warningf("all %d GitHub tokens are rate limited for %s requests, waiting %s for the earliest reset", len(r.tokens), res, wait)
+			if err := r.sleep(req.Context(), wait+time.Second); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		treq := req.Clone(req.Context())
+		if attempt > 0 && req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			treq.Body = body
+		}
+		treq.Header.Set("Authorization", "Bearer "+r.tokens[i])
+
+		resp, err := r.base.RoundTrip(treq)
+		if err != nil {
+			return nil, err
+		}
+
+		if r.record(i, res, resp) && replayable {
+			klog.Warningf("GitHub token %d of %d is rate limited for %s requests, trying the next", i+1, len(r.tokens), res)
+			_, _ = io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+			continue
+		}
+
+		r.rewrite(res, resp)
+		return resp, nil
+	}
+}
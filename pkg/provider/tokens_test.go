@@ -0,0 +1,88 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestSplitTokens(t *testing.T) {
+	assert.Equal(t, []string{"a", "b", "c"}, splitTokens("a\n b,c\n\n"))
+	assert.Empty(t, splitTokens(""))
+}
+
+func TestTokenRotator(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	reset := now.Add(30 * time.Minute)
+	exhausted := map[string]bool{"a": true}
+	used := []string{}
+
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+		used = append(used, token)
+		resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: ioutil.NopCloser(strings.NewReader(""))}
+		resp.Header.Set("X-RateLimit-Limit", "5000")
+		resp.Header.Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		resp.Header.Set("X-RateLimit-Remaining", "100")
+		if exhausted[token] {
+			resp.StatusCode = http.StatusForbidden
+			resp.Header.Set("X-RateLimit-Remaining", "0")
+		}
+		return resp, nil
+	})
+
+	r := newTokenRotator(base, []string{"a", "b"})
+	r.now = func() time.Time { return now }
+	var slept time.Duration
+	r.sleep = func(ctx context.Context, d time.Duration) error {
+		slept = d
+		now = now.Add(d)
+		delete(exhausted, "a")
+		delete(exhausted, "b")
+		return nil
+	}
+
+	req, _ := http.NewRequest("GET", "https://api.github.com/repos/o/p/issues", nil)
+	resp, err := r.RoundTrip(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{"a", "b"}, used)
+	// The exhausted token is not counted, so the GitHub client keeps sending requests
+	assert.Equal(t, "100", resp.Header.Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "10000", resp.Header.Get("X-RateLimit-Limit"))
+
+	// Once every token is exhausted, requests wait for the earliest reset
+	exhausted["b"] = true
+	used = nil
+	resp, err = r.RoundTrip(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{"b", "b"}, used)
+	assert.Equal(t, 30*time.Minute+time.Second, slept)
+}
@@ -0,0 +1,71 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package provider
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// Connection reuse defaults, suitable for frequent polling of a single API host
+const (
+	DefaultMaxIdleConnsPerHost = 16
+	DefaultIdleConnTimeout     = 90 * time.Second
+	DefaultKeepAlive           = 30 * time.Second
+)
+
+// Transport tunes connection reuse for API requests. Zero values use the defaults.
+type Transport struct {
+	// MaxIdleConnsPerHost is how many idle connections are kept open to each host
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout is how long an idle connection is kept open
+	IdleConnTimeout time.Duration
+	// KeepAlive is the interval between TCP keep-alive probes
+	KeepAlive time.Duration
+}
+
+// NewHTTPClient returns a client whose connections are pooled according to t
+func NewHTTPClient(t Transport) *http.Client {
+	if t.MaxIdleConnsPerHost <= 0 {
+		t.MaxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+	}
+	if t.IdleConnTimeout <= 0 {
+		t.IdleConnTimeout = DefaultIdleConnTimeout
+	}
+	if t.KeepAlive <= 0 {
+		t.KeepAlive = DefaultKeepAlive
+	}
+
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	tr.DialContext = (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: t.KeepAlive}).DialContext
+	tr.MaxIdleConnsPerHost = t.MaxIdleConnsPerHost
+	if tr.MaxIdleConns < t.MaxIdleConnsPerHost {
+		tr.MaxIdleConns = t.MaxIdleConnsPerHost
+	}
+	tr.IdleConnTimeout = t.IdleConnTimeout
+	return &http.Client{Transport: tr}
+}
+
+// WithHTTPClient returns a context which causes NewGitHub and NewGitHubEnterprise to send requests using c
+func WithHTTPClient(ctx context.Context, c *http.Client) context.Context {
+	if c == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, oauth2.HTTPClient, c)
+}
@@ -0,0 +1,71 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/google/triage-party/pkg/triage"
+	"k8s.io/klog/v2"
+)
+
+// errForbidden is returned when the user may not view a collection
+var errForbidden = errors.New("access denied")
+
+// tokenAllowed returns true if the request has a valid refresh token
+func (h *Handlers) tokenAllowed(r *http.Request) bool {
+	if h.refreshToken == "" {
+		return false
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(token), []byte(h.refreshToken)) == 1
+}
+
+// permitted returns true if the requesting user is within an access list, or has a valid refresh token
+func (h *Handlers) permitted(r *http.Request, access []string) bool {
+	if len(access) == 0 || h.tokenAllowed(r) {
+		return true
+	}
+	return h.party.Permitted(r.Context(), h.user(r), access)
+}
+
+// canView returns true if the requesting user may view a collection on this board
+func (h *Handlers) canView(r *http.Request, c triage.Collection) bool {
+	return h.permitted(r, h.boardAccess) && h.permitted(r, c.Access)
+}
+
+// visible filters collections to those the requesting user may view
+func (h *Handlers) visible(r *http.Request, sts []triage.Collection) []triage.Collection {
+	shown := []triage.Collection{}
+	for _, s := range sts {
+		if h.canView(r, s) {
+			shown = append(shown, s)
+		}
+	}
+	return shown
+}
+
+// forbidden asks anonymous users to log in, and tells everyone else they lack access
+func (h *Handlers) forbidden(w http.ResponseWriter, r *http.Request) {
+	if h.oauth != nil && h.user(r) == "" {
+		http.Redirect(w, r, h.basePath+"/login", http.StatusFound)
+		return
+	}
+	klog.Warningf("%q may not view %s", h.user(r), r.URL.Path)
+	http.Error(w, "you do not have access to this collection", http.StatusForbidden)
+}
@@ -0,0 +1,90 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/triage-party/pkg/triage"
+	"k8s.io/klog/v2"
+)
+
+// Action performs a write operation on an issue or PR, then refreshes that item in the background.
+func (h *Handlers) Action() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !h.writeMode {
+			http.Error(w, "write mode is disabled", http.StatusForbidden)
+			return
+		}
+
+		if r.Method != http.MethodPost {
+			http.Error(w, "POST required", http.StatusMethodNotAllowed)
+			return
+		}
+
+		user := h.user(r)
+		if user == "" {
+			http.Redirect(w, r, h.basePath+"/login", http.StatusSeeOther)
+			return
+		}
+
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, fmt.Sprintf("parse form: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		if !h.validCSRF(r) {
+			klog.Warningf("%s posted an action without a valid CSRF token", user)
+			http.Error(w, "invalid CSRF token", http.StatusForbidden)
+			return
+		}
+
+		a := triage.Action{
+			URL:   r.PostForm.Get("url"),
+			Kind:  r.PostForm.Get("kind"),
+			Label: r.PostForm.Get("label"),
+		}
+
+		if err := h.party.Act(r.Context(), user, a); err != nil {
+			klog.Errorf("%s action %+v: %v", user, a, err)
+			if errors.Is(err, triage.ErrPermissionDenied) {
+				http.Error(w, fmt.Sprintf("%s may not modify %s", user, a.URL), http.StatusForbidden)
+				return
+			}
+			http.Error(w, fmt.Sprintf("action failed: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		go h.refreshItem(a)
+
+		id := r.PostForm.Get("collection")
+		if id == "" {
+			http.Redirect(w, r, h.basePath+"/", http.StatusSeeOther)
+			return
+		}
+
+		http.Redirect(w, r, fmt.Sprintf("%s/s/%s", h.basePath, id), http.StatusSeeOther)
+	}
+}
+
+// refreshItem refreshes the item an action was performed on, so that the next page view reflects it
+func (h *Handlers) refreshItem(a triage.Action) {
+	if err := h.updater.RefreshItem(context.Background(), a.URL); err != nil {
+		klog.Errorf("refresh after %s: %v", a.Kind, err)
+	}
+}
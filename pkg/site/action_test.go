@@ -0,0 +1,194 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/triage-party/pkg/persist"
+	"github.com/google/triage-party/pkg/provider"
+	"github.com/google/triage-party/pkg/triage"
+	"github.com/google/triage-party/pkg/updater"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeWriter records write operations, and is otherwise offline
+type fakeWriter struct {
+	provider.Provider
+	level string
+
+	mu    sync.Mutex
+	calls []string
+}
+
+func (f *fakeWriter) record(s string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, s)
+}
+
+func (f *fakeWriter) RepositoriesGetPermissionLevel(context.Context, provider.SearchParams, string) (string, *provider.Response, error) {
+	return f.level, &provider.Response{}, nil
+}
+
+func (f *fakeWriter) IssuesAddLabelsToIssue(_ context.Context, sp provider.SearchParams, labels []string) (*provider.Response, error) {
+	f.record("label " + strings.Join(labels, ","))
+	return &provider.Response{}, nil
+}
+
+func (f *fakeWriter) IssuesRemoveLabelForIssue(_ context.Context, sp provider.SearchParams, label string) (*provider.Response, error) {
+	f.record("unlabel " + label)
+	return &provider.Response{}, nil
+}
+
+func (f *fakeWriter) IssuesEdit(_ context.Context, sp provider.SearchParams, req *provider.IssueRequest) (*provider.Response, error) {
+	f.record("state " + *req.State)
+	return &provider.Response{}, nil
+}
+
+const actionConfig = `settings:
+  repos: [https://github.com/org/project]
+collections:
+  - id: open
+    rules: [open]
+rules:
+  open:
+    filters:
+      - state: open
+`
+
+func TestAction(t *testing.T) {
+	tests := []struct {
+		name      string
+		writeMode bool
+		level     string
+		url       string
+		noCSRF    bool
+		form      url.Values
+		wantCode  int
+		wantCalls []string
+	}{
+		{
+			name:      "label",
+			writeMode: true,
+			level:     provider.WritePermission,
+			form:      url.Values{"kind": {triage.LabelAction}, "label": {"triage/duplicate"}},
+			wantCode:  http.StatusSeeOther,
+			wantCalls: []string{"label triage/duplicate"},
+		},
+		{
+			name:      "unlabel",
+			writeMode: true,
+			level:     provider.AdminPermission,
+			form:      url.Values{"kind": {triage.UnlabelAction}, "label": {"needs-triage"}},
+			wantCode:  http.StatusSeeOther,
+			wantCalls: []string{"unlabel needs-triage"},
+		},
+		{
+			name:      "close",
+			writeMode: true,
+			level:     provider.WritePermission,
+			form:      url.Values{"kind": {triage.CloseAction}},
+			wantCode:  http.StatusSeeOther,
+			wantCalls: []string{"state closed"},
+		},
+		{
+			name:      "permission denied",
+			writeMode: true,
+			level:     provider.ReadPermission,
+			form:      url.Values{"kind": {triage.CloseAction}},
+			wantCode:  http.StatusForbidden,
+		},
+		{
+			name:      "missing csrf token",
+			writeMode: true,
+			level:     provider.AdminPermission,
+			noCSRF:    true,
+			form:      url.Values{"kind": {triage.CloseAction}},
+			wantCode:  http.StatusForbidden,
+		},
+		{
+			name:      "other host",
+			writeMode: true,
+			level:     provider.AdminPermission,
+			url:       "https://gitlab.com/org/project/-/issues/1",
+			form:      url.Values{"kind": {triage.CloseAction}},
+			wantCode:  http.StatusForbidden,
+		},
+		{
+			name:      "write mode disabled",
+			writeMode: false,
+			level:     provider.AdminPermission,
+			form:      url.Values{"kind": {triage.CloseAction}},
+			wantCode:  http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := &fakeWriter{Provider: provider.Offline(), level: tc.level}
+			c, err := persist.NewMemory(persist.Config{})
+			if err != nil {
+				t.Fatalf("memory: %v", err)
+			}
+			tp, err := triage.New(triage.Config{Cache: c, Provider: f})
+			if err != nil {
+				t.Fatalf("new: %v", err)
+			}
+			if err := tp.Load(strings.NewReader(actionConfig)); err != nil {
+				t.Fatalf("load: %v", err)
+			}
+
+			h := New(&Config{Party: tp, Updater: updater.New(updater.Config{Party: tp}), WriteMode: tc.writeMode})
+			h.sessionKey = newSessionKey()
+			login := httptest.NewRecorder()
+			h.setUser(login, "triager")
+
+			u := "https://github.com/org/project/issues/1"
+			if tc.url != "" {
+				u = tc.url
+			}
+			tc.form.Set("url", u)
+
+			session := httptest.NewRequest("GET", "/", nil)
+			for _, ck := range login.Result().Cookies() {
+				session.AddCookie(ck)
+			}
+			if !tc.noCSRF {
+				tc.form.Set("csrf", h.csrfToken(session))
+			}
+
+			r := httptest.NewRequest("POST", "/action", strings.NewReader(tc.form.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			for _, ck := range login.Result().Cookies() {
+				r.AddCookie(ck)
+			}
+
+			w := httptest.NewRecorder()
+			h.Action()(w, r)
+			assert.Equal(t, tc.wantCode, w.Code)
+
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			assert.Equal(t, tc.wantCalls, f.calls)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// ClearCacheResponse is returned by the cache clearing endpoint
+type ClearCacheResponse struct {
+	// Cleared is how many cache entries were deleted
+	Cleared int `json:"cleared"`
+	// Refreshed is the time of the latest results for each refreshed collection
+	Refreshed map[string]time.Time `json:"refreshed"`
+}
+
+// ClearCache empties the cache, or the entries for a single repository, then refreshes and persists.
+func (h *Handlers) ClearCache() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		klog.Infof("%s %s", r.Method, r.URL.Path)
+
+		if r.Method != http.MethodPost {
+			http.Error(w, "POST required", http.StatusMethodNotAllowed)
+			return
+		}
+
+		// Clearing affects every viewer, so a login is not enough
+		if !h.tokenAllowed(r) {
+			http.Error(w, "clearing the cache requires a valid refresh token", http.StatusUnauthorized)
+			return
+		}
+
+		org, project, err := parseRepoParam(r.FormValue("repo"))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("repo: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		sts, err := h.party.ListCollections()
+		if err != nil {
+			http.Error(w, fmt.Sprintf("list collections: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		n, err := h.party.ClearCache(org, project)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("clear: %v", err), http.StatusInternalServerError)
+			klog.Errorf("clear cache: %v", err)
+			return
+		}
+		klog.Warningf("cleared %d cache entries (repo=%q), refreshing", n, r.FormValue("repo"))
+
+		resp := ClearCacheResponse{Cleared: n, Refreshed: map[string]time.Time{}}
+		for _, s := range h.onBoard(sts) {
+			if cr := h.updater.ForceRefresh(r.Context(), s.ID); cr != nil {
+				resp.Refreshed[s.ID] = cr.Created
+			}
+		}
+
+		if err := h.updater.Flush(); err != nil {
+			klog.Errorf("persist after clearing cache: %v", err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			klog.Errorf("encode: %v", err)
+		}
+	}
+}
+
+// parseRepoParam parses an owner/name or repository URL into an organization and project, which are empty if s is
+func parseRepoParam(s string) (string, string, error) {
+	if s == "" {
+		return "", "", nil
+	}
+
+	if i := strings.Index(s, "://"); i >= 0 {
+		s = s[i+3:]
+		if j := strings.Index(s, "/"); j >= 0 {
+			s = s[j+1:]
+		}
+	}
+
+	parts := strings.Split(strings.Trim(s, "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("expected owner/name or a repository URL, got %q", s)
+	}
+	return parts[0], parts[1], nil
+}
@@ -0,0 +1,39 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRepoParam(t *testing.T) {
+	for _, s := range []string{"kubernetes/minikube", "https://github.com/kubernetes/minikube", "https://github.com/kubernetes/minikube/"} {
+		org, project, err := parseRepoParam(s)
+		assert.Nil(t, err, s)
+		assert.Equal(t, "kubernetes", org, s)
+		assert.Equal(t, "minikube", project, s)
+	}
+
+	org, _, err := parseRepoParam("")
+	assert.Nil(t, err)
+	assert.Equal(t, "", org)
+
+	for _, s := range []string{"minikube", "kubernetes/minikube/issues", "/minikube"} {
+		_, _, err := parseRepoParam(s)
+		assert.Error(t, err, s)
+	}
+}
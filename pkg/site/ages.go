@@ -0,0 +1,67 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"fmt"
+	"time"
+)
+
+// How ages are displayed
+const (
+	// HumanizedAges rounds ages into buckets, such as 5wk or 3mo
+	HumanizedAges = "humanized"
+	// ExactAges shows ages in whole minutes, hours, or days, such as 37d
+	ExactAges = "exact"
+)
+
+// IsAgeDisplay returns whether a string is a known age display
+func IsAgeDisplay(s string) bool {
+	return s == HumanizedAges || s == ExactAges
+}
+
+// exactTime formats the time since t in whole minutes, hours, or days, without rounding to weeks, months, or years
+func exactTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	d := time.Since(t)
+	switch {
+	case d < 0:
+		return roughTime(t)
+	case d < time.Minute:
+		return "now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dmin", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+// roughTime formats the time since t for display, as configured
+func (h *Handlers) roughTime(t time.Time) string {
+	if h.ages == ExactAges {
+		return exactTime(t)
+	}
+	return roughTime(t)
+}
+
+// humanDuration formats a duration for display, as configured
+func (h *Handlers) humanDuration(d time.Duration) string {
+	return h.roughTime(time.Now().Add(-d))
+}
@@ -0,0 +1,34 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAges(t *testing.T) {
+	then := time.Now().Add(-37*24*time.Hour - time.Minute)
+
+	h := New(&Config{})
+	assert.Equal(t, "5wk", h.roughTime(then))
+
+	h = New(&Config{Ages: ExactAges})
+	assert.Equal(t, "37d", h.roughTime(then))
+	assert.Equal(t, "5h", h.humanDuration(5*time.Hour+time.Minute))
+	assert.Equal(t, "", h.roughTime(time.Time{}))
+}
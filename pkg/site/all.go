@@ -0,0 +1,173 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/google/triage-party/pkg/hubbub"
+	"github.com/google/triage-party/pkg/triage"
+	"k8s.io/klog/v2"
+)
+
+// defaultFlatSort is the order of the flat view if unspecified, unless heat is configured
+const defaultFlatSort = "created"
+
+// heatFlatSort is the order of the flat view by heat, which is the default if heat is configured
+const heatFlatSort = "heat"
+
+// flatSorts are the supported orderings of the flat view, keyed by name
+var flatSorts = map[string]func(a, b *FlatItem) bool{
+	// oldest first
+	"created": func(a, b *FlatItem) bool { return a.Created.Before(b.Created) },
+	// newest first
+	"newest": func(a, b *FlatItem) bool { return a.Created.After(b.Created) },
+	// least recently updated first
+	"updated": func(a, b *FlatItem) bool { return a.Updated.Before(b.Updated) },
+	// hottest first, then oldest
+	heatFlatSort: func(a, b *FlatItem) bool {
+		if a.Heat != b.Heat {
+			return a.Heat > b.Heat
+		}
+		return a.Created.Before(b.Created)
+	},
+}
+
+// FlatItem is an item within the flat view, along with the collections it appears in
+type FlatItem struct {
+	*hubbub.Conversation
+	Collections []triage.Collection
+}
+
+// All shows the items of every collection as a single deduplicated list
+func (h *Handlers) All() http.HandlerFunc {
+	fmap := template.FuncMap{
+		"toJS":          toJS,
+		"toYAML":        toYAML,
+		"toJSfunc":      toJSfunc,
+		"toDays":        toDays,
+		"HumanDuration": h.humanDuration,
+		"RoughTime":     h.roughTime,
+		"UnixNano":      unixNano,
+		"Avatar":        avatar,
+		"Class":         className,
+		"TextColor":     textColor,
+	}
+	t := template.Must(template.New("all").Funcs(withTemplateFuncs(fmap)).ParseFiles(
+		filepath.Join(h.baseDir, "all.tmpl"),
+		filepath.Join(h.baseDir, "base.tmpl"),
+	))
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		heat := h.party.HeatEnabled()
+		by := r.URL.Query().Get("sort")
+		if by == "" {
+			by = defaultFlatSort
+			if heat {
+				by = heatFlatSort
+			}
+		}
+		if flatSorts[by] == nil {
+			http.Error(w, fmt.Sprintf("sort: unknown order %q", by), http.StatusBadRequest)
+			return
+		}
+		if by == heatFlatSort && !heat {
+			http.Error(w, "sort: heat is not configured, see the heat setting", http.StatusBadRequest)
+			return
+		}
+
+		sts, err := h.party.ListCollections()
+		if err != nil {
+			http.Error(w, fmt.Sprintf("list collections: %v", err), 500)
+			return
+		}
+		sts = h.visible(r, h.onBoard(sts))
+
+		crs := map[string]*triage.CollectionResult{}
+		oldest := time.Now()
+		for _, s := range sts {
+			if s.Hidden || s.UsedForStats {
+				continue
+			}
+			cr := h.updater.Lookup(r.Context(), s.ID, false)
+			if cr == nil {
+				continue
+			}
+			if cr.RuleResults != nil {
+				cr = viewerFilter(cr, h.user(r))
+			}
+			crs[s.ID] = cr
+			if !cr.OldestInput.IsZero() && cr.OldestInput.Before(oldest) {
+				oldest = cr.OldestInput
+			}
+		}
+
+		items := flatten(sts, crs, by)
+
+		p := &Page{
+			Version:     VERSION,
+			SiteName:    h.displayName(),
+			Branding:    h.branding,
+			Title:       "All items",
+			Collections: h.orderCollections(sts),
+			Total:       len(items),
+			ResultAge:   time.Since(oldest),
+			Status:      h.updater.Status(),
+			BasePath:    h.basePath,
+			Heat:        heat,
+
+			FlatItems: items,
+			FlatSort:  by,
+		}
+
+		err = t.ExecuteTemplate(w, "base", p)
+		if err != nil {
+			klog.Errorf("tmpl: %v", err)
+			return
+		}
+	}
+}
+
+// flatten merges collection results into a single list, ordered by the named sort
+func flatten(sts []triage.Collection, crs map[string]*triage.CollectionResult, by string) []*FlatItem {
+	items := []*FlatItem{}
+	seen := map[string]*FlatItem{}
+
+	for _, s := range sts {
+		cr := crs[s.ID]
+		if cr == nil {
+			continue
+		}
+
+		for _, co := range uniqueItems(cr.RuleResults) {
+			if fi := seen[co.URL]; fi != nil {
+				fi.Collections = append(fi.Collections, s)
+				continue
+			}
+			fi := &FlatItem{Conversation: co, Collections: []triage.Collection{s}}
+			seen[co.URL] = fi
+			items = append(items, fi)
+		}
+	}
+
+	less := flatSorts[by]
+	sort.SliceStable(items, func(i, j int) bool { return less(items[i], items[j]) })
+	return items
+}
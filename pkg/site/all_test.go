@@ -0,0 +1,57 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/triage-party/pkg/hubbub"
+	"github.com/google/triage-party/pkg/triage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlatten(t *testing.T) {
+	now := time.Now()
+	old := &hubbub.Conversation{URL: "old", Created: now.Add(-48 * time.Hour)}
+	mid := &hubbub.Conversation{URL: "mid", Created: now.Add(-24 * time.Hour)}
+	recent := &hubbub.Conversation{URL: "recent", Created: now}
+
+	a := triage.Collection{ID: "a"}
+	b := triage.Collection{ID: "b"}
+	crs := map[string]*triage.CollectionResult{
+		"a": {RuleResults: []*triage.RuleResult{{Items: []*hubbub.Conversation{recent, mid}}}},
+		"b": {RuleResults: []*triage.RuleResult{{Items: []*hubbub.Conversation{mid}}, {Items: []*hubbub.Conversation{old}}}},
+	}
+
+	got := flatten([]triage.Collection{a, b}, crs, "created")
+	urls := []string{}
+	for _, fi := range got {
+		urls = append(urls, fi.URL)
+	}
+	assert.Equal(t, []string{"old", "mid", "recent"}, urls)
+	assert.Equal(t, []triage.Collection{a, b}, got[1].Collections)
+
+	got = flatten([]triage.Collection{a, b}, crs, "newest")
+	assert.Equal(t, "recent", got[0].URL)
+
+	mid.Heat = 5
+	got = flatten([]triage.Collection{a, b}, crs, heatFlatSort)
+	urls = []string{}
+	for _, fi := range got {
+		urls = append(urls, fi.URL)
+	}
+	assert.Equal(t, []string{"mid", "old", "recent"}, urls)
+}
@@ -0,0 +1,135 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"k8s.io/klog/v2"
+)
+
+// Allowlist rejects requests from clients outside of a set of networks
+type Allowlist struct {
+	allowed []*net.IPNet
+	trusted []*net.IPNet
+}
+
+// NewAllowlist returns an allowlist for CIDR ranges or addresses. X-Forwarded-For is only
+// believed when the connecting address, and each address after the client, is a trusted proxy.
+func NewAllowlist(allowed []string, trustedProxies []string) (*Allowlist, error) {
+	a := &Allowlist{}
+	var err error
+	if a.allowed, err = parseNets(allowed); err != nil {
+		return nil, fmt.Errorf("allowed: %w", err)
+	}
+	if a.trusted, err = parseNets(trustedProxies); err != nil {
+		return nil, fmt.Errorf("trusted proxies: %w", err)
+	}
+	if len(a.allowed) == 0 {
+		return nil, fmt.Errorf("no allowed networks")
+	}
+	return a, nil
+}
+
+// parseNets parses CIDR ranges, treating bare addresses as a single host
+func parseNets(ss []string) ([]*net.IPNet, error) {
+	nets := []*net.IPNet{}
+	for _, s := range ss {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+
+		if !strings.Contains(s, "/") {
+			ip := net.ParseIP(s)
+			if ip == nil {
+				return nil, fmt.Errorf("%q is not an IP address or CIDR range", s)
+			}
+			bits := 128
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 32
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, n, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, err
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+func contains(nets []*net.IPNet, ip net.IP) bool {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientIP returns the address of the client, skipping over trusted proxies from the right of X-Forwarded-For
+func clientIP(r *http.Request, trusted []*net.IPNet) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !contains(trusted, ip) {
+		return ip
+	}
+
+	hops := []string{}
+	for _, v := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(v, ",")...)
+	}
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := net.ParseIP(strings.TrimSpace(hops[i]))
+		if hop == nil {
+			return ip
+		}
+		ip = hop
+		if !contains(trusted, ip) {
+			return ip
+		}
+	}
+	return ip
+}
+
+// Handler rejects requests from clients which are not allowed with a 403. Health checks are always permitted.
+func (a *Allowlist) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/healthz" || r.URL.Path == "/readyz" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ip := clientIP(r, a.trusted)
+		if ip == nil || !contains(a.allowed, ip) {
+			klog.Warningf("rejecting %s %s from %s (remote %s): not within an allowed network", r.Method, r.URL.Path, ip, r.RemoteAddr)
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,60 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllowlist(t *testing.T) {
+	a, err := NewAllowlist([]string{"10.0.0.0/8", "192.0.2.7"}, []string{"172.16.0.1"})
+	assert.NoError(t, err)
+	h := a.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		remote string
+		xff    string
+		path   string
+		want   int
+	}{
+		{"10.1.2.3:1234", "", "/", http.StatusOK},
+		{"192.0.2.7:1234", "", "/", http.StatusOK},
+		{"198.51.100.1:1234", "", "/", http.StatusForbidden},
+		{"198.51.100.1:1234", "", "/healthz", http.StatusOK},
+		// X-Forwarded-For is ignored from untrusted clients
+		{"198.51.100.1:1234", "10.1.2.3", "/", http.StatusForbidden},
+		{"172.16.0.1:1234", "10.1.2.3", "/", http.StatusOK},
+		{"172.16.0.1:1234", "10.1.2.3, 198.51.100.1", "/", http.StatusForbidden},
+		{"172.16.0.1:1234", "198.51.100.1, 10.1.2.3, 172.16.0.1", "/", http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		r := httptest.NewRequest("GET", tc.path, nil)
+		r.RemoteAddr = tc.remote
+		if tc.xff != "" {
+			r.Header.Set("X-Forwarded-For", tc.xff)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		assert.Equal(t, tc.want, w.Code, "%s via %s", tc.xff, tc.remote)
+	}
+
+	_, err = NewAllowlist([]string{"not-a-network"}, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,91 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/triage-party/pkg/triage"
+	"k8s.io/klog/v2"
+)
+
+// CollectionSchemaVersion is the version of the /api/s/ payload, increased when fields are removed or change meaning
+const CollectionSchemaVersion = 1
+
+// CollectionPayload is the latest result of a collection, as served by /api/s/
+type CollectionPayload struct {
+	SchemaVersion int    `json:"schema_version"`
+	Version       string `json:"version"`
+	SiteName      string `json:"site_name"`
+	Description   string `json:"description,omitempty"`
+
+	SnapshotCollection
+
+	// SLAStatus is the SLA status of each item, by URL, if the collection has an SLA
+	SLAStatus map[string]string `json:"sla_status,omitempty"`
+}
+
+// newCollectionPayload returns the API view of a collection result
+func newCollectionPayload(siteName string, s triage.Collection, cr *triage.CollectionResult) *CollectionPayload {
+	return &CollectionPayload{
+		SchemaVersion:      CollectionSchemaVersion,
+		Version:            VERSION,
+		SiteName:           siteName,
+		Description:        s.Description,
+		SnapshotCollection: newSnapshotCollection(s, cr),
+		SLAStatus:          cr.SLAStatus,
+	}
+}
+
+// CollectionJSON returns the latest results of a collection as JSON, for bots and custom dashboards
+func (h *Handlers) CollectionJSON() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		klog.Infof("GET %s: %v", r.URL.Path, r.URL.Query())
+
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/s/"), "/")
+		if !h.onBoardID(id) {
+			http.Error(w, fmt.Sprintf("collection %q is not on this board", id), http.StatusNotFound)
+			return
+		}
+
+		s, err := h.party.LookupCollection(id)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("lookup collection: %v", err), http.StatusNotFound)
+			return
+		}
+		if !h.canView(r, s) {
+			klog.Warningf("%q may not view %s", h.user(r), r.URL.Path)
+			http.Error(w, "you do not have access to this collection", http.StatusForbidden)
+			return
+		}
+
+		cr := h.updater.Lookup(r.Context(), id, false)
+		if cr == nil || cr.RuleResults == nil {
+			http.Error(w, fmt.Sprintf("results for %q are not yet available", id), http.StatusServiceUnavailable)
+			return
+		}
+		cr = viewerFilter(cr, h.user(r))
+
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(newCollectionPayload(h.displayName(), s, cr)); err != nil {
+			klog.Errorf("encode: %v", err)
+		}
+	}
+}
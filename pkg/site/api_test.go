@@ -0,0 +1,44 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/triage-party/pkg/hubbub"
+	"github.com/google/triage-party/pkg/triage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCollectionPayload(t *testing.T) {
+	url := "https://github.com/org/project/issues/7"
+	cr := &triage.CollectionResult{
+		Total:       1,
+		RuleResults: []*triage.RuleResult{{Rule: triage.Rule{ID: "untriaged"}, Items: []*hubbub.Conversation{{ID: 7, URL: url}}}},
+		SLAStatus:   map[string]string{url: triage.SLABreached},
+	}
+
+	b, err := json.Marshal(newCollectionPayload("Triage", triage.Collection{ID: "daily", Description: "Every day"}, cr))
+	assert.NoError(t, err)
+
+	got := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(b, &got))
+	assert.Equal(t, float64(CollectionSchemaVersion), got["schema_version"])
+	assert.Equal(t, "daily", got["id"])
+	assert.Equal(t, "Every day", got["description"])
+	assert.Equal(t, map[string]interface{}{url: triage.SLABreached}, got["sla_status"])
+	assert.Len(t, got["rules"], 1)
+}
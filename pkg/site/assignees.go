@@ -0,0 +1,171 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/triage-party/pkg/hubbub"
+	"github.com/google/triage-party/pkg/provider"
+	"k8s.io/klog/v2"
+)
+
+// unassignedGroup is the group name for items without an assignee
+const unassignedGroup = "unassigned"
+
+// ungroupedGroup is the group name for items without a label matching the groupBy prefix
+const ungroupedGroup = "ungrouped"
+
+// ItemGroup is a set of items assigned to the same user, or sharing a label
+type ItemGroup struct {
+	Name  string
+	User  *provider.User
+	Label *provider.Label
+	Items []*hubbub.Conversation
+}
+
+// Assignees shows a collection's items grouped by assignee, or with ?groupBy=, by labels with that prefix.
+func (h *Handlers) Assignees() http.HandlerFunc {
+	fmap := template.FuncMap{
+		"toJS":          toJS,
+		"toYAML":        toYAML,
+		"toJSfunc":      toJSfunc,
+		"toDays":        toDays,
+		"HumanDuration": h.humanDuration,
+		"RoughTime":     h.roughTime,
+		"UnixNano":      unixNano,
+		"Avatar":        avatar,
+		"Class":         className,
+		"TextColor":     textColor,
+		"LabelStyle":    h.labelStyle,
+	}
+	t := template.Must(template.New("assignees").Funcs(withTemplateFuncs(fmap)).ParseFiles(
+		filepath.Join(h.baseDir, "assignees.tmpl"),
+		filepath.Join(h.baseDir, "base.tmpl"),
+	))
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/s/")
+		id = strings.TrimSuffix(strings.TrimSuffix(id, "/assignees"), "/groups")
+
+		p, err := h.collectionPage(r, id, false)
+		if errors.Is(err, errForbidden) {
+			h.forbidden(w, r)
+			return
+		}
+		if err != nil {
+			http.Error(w, fmt.Sprintf("collection page for %q: %v", id, err), 500)
+			klog.Errorf("page: %v", err)
+			return
+		}
+
+		p.GroupBy = r.URL.Query().Get("groupBy")
+		if p.GroupBy != "" {
+			p.ItemGroups = groupByLabel(p.UniqueItems, p.GroupBy)
+		} else {
+			p.ItemGroups = groupByAssignee(p.UniqueItems)
+		}
+
+		err = t.ExecuteTemplate(w, "base", p)
+		if err != nil {
+			klog.Errorf("tmpl: %v", err)
+			return
+		}
+	}
+}
+
+// groupByAssignee groups items by assignee, busiest first, with unassigned items last
+func groupByAssignee(items []*hubbub.Conversation) []*ItemGroup {
+	groups := map[string]*ItemGroup{}
+	none := &ItemGroup{Name: unassignedGroup}
+
+	for _, i := range items {
+		if len(i.Assignees) == 0 {
+			none.Items = append(none.Items, i)
+			continue
+		}
+
+		for _, a := range i.Assignees {
+			login := a.GetLogin()
+			if groups[login] == nil {
+				groups[login] = &ItemGroup{Name: login, User: a}
+			}
+			groups[login].Items = append(groups[login].Items, i)
+		}
+	}
+
+	gs := []*ItemGroup{}
+	for _, g := range groups {
+		gs = append(gs, g)
+	}
+
+	sort.Slice(gs, func(i, j int) bool {
+		if len(gs[i].Items) != len(gs[j].Items) {
+			return len(gs[i].Items) > len(gs[j].Items)
+		}
+		return gs[i].Name < gs[j].Name
+	})
+
+	if len(none.Items) > 0 {
+		gs = append(gs, none)
+	}
+	return gs
+}
+
+// groupByLabel groups items by their labels starting with prefix, in label order, with ungrouped items last
+func groupByLabel(items []*hubbub.Conversation, prefix string) []*ItemGroup {
+	groups := map[string]*ItemGroup{}
+	none := &ItemGroup{Name: ungroupedGroup}
+	prefix = strings.ToLower(prefix)
+
+	for _, i := range items {
+		found := false
+		for _, l := range i.Labels {
+			name := strings.ToLower(l.GetName())
+			if !strings.HasPrefix(name, prefix) {
+				continue
+			}
+			found = true
+			if groups[name] == nil {
+				groups[name] = &ItemGroup{Name: l.GetName(), Label: l}
+			}
+			groups[name].Items = append(groups[name].Items, i)
+		}
+
+		if !found {
+			none.Items = append(none.Items, i)
+		}
+	}
+
+	gs := []*ItemGroup{}
+	for _, g := range groups {
+		gs = append(gs, g)
+	}
+
+	sort.Slice(gs, func(i, j int) bool {
+		return strings.ToLower(gs[i].Name) < strings.ToLower(gs[j].Name)
+	})
+
+	if len(none.Items) > 0 {
+		gs = append(gs, none)
+	}
+	return gs
+}
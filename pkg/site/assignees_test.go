@@ -0,0 +1,43 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"testing"
+
+	"github.com/google/triage-party/pkg/hubbub"
+	"github.com/google/triage-party/pkg/provider"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupByLabel(t *testing.T) {
+	label := func(name string) *provider.Label {
+		return &provider.Label{Name: &name}
+	}
+
+	p0 := &hubbub.Conversation{URL: "p0", Labels: []*provider.Label{label("Priority/P0"), label("kind/bug")}}
+	both := &hubbub.Conversation{URL: "both", Labels: []*provider.Label{label("priority/p1"), label("priority/p0")}}
+	none := &hubbub.Conversation{URL: "none", Labels: []*provider.Label{label("kind/bug")}}
+
+	gs := groupByLabel([]*hubbub.Conversation{p0, both, none}, "priority/")
+	names := []string{}
+	for _, g := range gs {
+		names = append(names, g.Name)
+	}
+	assert.Equal(t, []string{"Priority/P0", "priority/p1", ungroupedGroup}, names)
+	assert.Equal(t, []*hubbub.Conversation{p0, both}, gs[0].Items)
+	assert.Equal(t, []*hubbub.Conversation{both}, gs[1].Items)
+	assert.Equal(t, []*hubbub.Conversation{none}, gs[2].Items)
+}
@@ -0,0 +1,179 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v33/github"
+	"k8s.io/klog/v2"
+)
+
+const (
+	sessionCookie = "tp-session"
+	stateCookie   = "tp-oauth-state"
+	sessionMaxAge = 7 * 24 * time.Hour
+)
+
+// newSessionKey returns a random key used to sign session cookies
+func newSessionKey() []byte {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		klog.Exitf("unable to generate session key: %v", err)
+	}
+	return b
+}
+
+func (h *Handlers) sign(s string) string {
+	m := hmac.New(sha256.New, h.sessionKey)
+	m.Write([]byte(s))
+	return hex.EncodeToString(m.Sum(nil))
+}
+
+// setUser issues a signed session cookie for a user
+func (h *Handlers) setUser(w http.ResponseWriter, login string) {
+	v := fmt.Sprintf("%s|%d", login, time.Now().Add(sessionMaxAge).Unix())
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    base64.RawURLEncoding.EncodeToString([]byte(v)) + "." + h.sign(v),
+		Path:     h.cookiePath,
+		MaxAge:   int(sessionMaxAge.Seconds()),
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// csrfToken returns the token which write forms must include, derived from the session cookie
+func (h *Handlers) csrfToken(r *http.Request) string {
+	if h.sessionKey == nil {
+		return ""
+	}
+	c, err := r.Cookie(sessionCookie)
+	if err != nil || c.Value == "" {
+		return ""
+	}
+	return h.sign("csrf|" + c.Value)
+}
+
+// validCSRF returns true if a form carries the CSRF token of the session it was posted with
+func (h *Handlers) validCSRF(r *http.Request) bool {
+	want := h.csrfToken(r)
+	return want != "" && hmac.Equal([]byte(want), []byte(r.PostForm.Get("csrf")))
+}
+
+// user returns the logged in user, or an empty string
+func (h *Handlers) user(r *http.Request) string {
+	if h.sessionKey == nil {
+		return ""
+	}
+
+	c, err := r.Cookie(sessionCookie)
+	if err != nil {
+		return ""
+	}
+
+	parts := strings.SplitN(c.Value, ".", 2)
+	if len(parts) != 2 {
+		return ""
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return ""
+	}
+
+	v := string(b)
+	if !hmac.Equal([]byte(h.sign(v)), []byte(parts[1])) {
+		klog.Warningf("invalid session signature for %q", v)
+		return ""
+	}
+
+	fields := strings.SplitN(v, "|", 2)
+	if len(fields) != 2 {
+		return ""
+	}
+
+	exp, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil || time.Now().Unix() > exp {
+		return ""
+	}
+	return fields[0]
+}
+
+// Login redirects to the OAuth provider.
+func (h *Handlers) Login() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if h.oauth == nil {
+			http.Error(w, "login is not configured", http.StatusNotFound)
+			return
+		}
+
+		state := hex.EncodeToString(newSessionKey()[:16])
+		http.SetCookie(w, &http.Cookie{
+			Name:     stateCookie,
+			Value:    state,
+			Path:     h.cookiePath,
+			MaxAge:   600,
+			HttpOnly: true,
+			Secure:   true,
+			SameSite: http.SameSiteLaxMode,
+		})
+		http.Redirect(w, r, h.oauth.AuthCodeURL(state), http.StatusFound)
+	}
+}
+
+// OAuthCallback completes the OAuth flow and issues a session cookie.
+func (h *Handlers) OAuthCallback() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if h.oauth == nil {
+			http.Error(w, "login is not configured", http.StatusNotFound)
+			return
+		}
+
+		c, err := r.Cookie(stateCookie)
+		if err != nil || c.Value == "" || c.Value != r.URL.Query().Get("state") {
+			http.Error(w, "invalid oauth state", http.StatusBadRequest)
+			return
+		}
+
+		tok, err := h.oauth.Exchange(r.Context(), r.URL.Query().Get("code"))
+		if err != nil {
+			klog.Errorf("oauth exchange: %v", err)
+			http.Error(w, "oauth exchange failed", http.StatusUnauthorized)
+			return
+		}
+
+		u, _, err := github.NewClient(h.oauth.Client(r.Context(), tok)).Users.Get(r.Context(), "")
+		if err != nil {
+			klog.Errorf("get user: %v", err)
+			http.Error(w, "unable to identify user", http.StatusUnauthorized)
+			return
+		}
+
+		klog.Infof("%s logged in", u.GetLogin())
+		h.setUser(w, u.GetLogin())
+		http.Redirect(w, r, h.basePath+"/", http.StatusSeeOther)
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/bcrypt"
+	"k8s.io/klog/v2"
+)
+
+// DefaultAuthExempt are the paths served without authentication by default, so that health checks keep working
+var DefaultAuthExempt = []string{"/healthz", "/readyz", "/health"}
+
+// Authenticator requires HTTP basic auth or a bearer token for each request, other than to exempt paths
+type Authenticator struct {
+	// users maps usernames to a password, or to its bcrypt hash
+	users  map[string]string
+	tokens []string
+	exempt map[string]bool
+
+	// verified caches the credentials which passed a bcrypt comparison, as it is deliberately slow
+	verified sync.Map
+}
+
+// NewAuthenticator returns an authenticator for basic auth users and bearer tokens
+func NewAuthenticator(users map[string]string, tokens []string, exempt []string) (*Authenticator, error) {
+	if len(users) == 0 && len(tokens) == 0 {
+		return nil, fmt.Errorf("no users or tokens")
+	}
+
+	a := &Authenticator{users: users, tokens: tokens, exempt: map[string]bool{}}
+	for _, p := range exempt {
+		if p = strings.TrimSpace(p); p != "" {
+			a.exempt[p] = true
+		}
+	}
+	// Webhooks are verified by their signature instead
+	a.exempt["/webhook"] = true
+	return a, nil
+}
+
+// ReadAuthFile reads basic auth users from an htpasswd-style file of user:password lines.
+// Passwords may be in plain text or bcrypt hashed, as by "htpasswd -B". Blank lines and # comments are ignored.
+func ReadAuthFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	users := map[string]string{}
+	s := bufio.NewScanner(f)
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("line %d: expected user:password", n)
+		}
+		users[parts[0]] = parts[1]
+	}
+	return users, s.Err()
+}
+
+// isBcrypt returns true if a password is a bcrypt hash
+func isBcrypt(s string) bool {
+	return strings.HasPrefix(s, "$2a$") || strings.HasPrefix(s, "$2b$") || strings.HasPrefix(s, "$2y$")
+}
+
+// validUser returns true if a basic auth user and password are known
+func (a *Authenticator) validUser(user string, pass string) bool {
+	want, ok := a.users[user]
+	if !ok {
+		return false
+	}
+
+	if !isBcrypt(want) {
+		return subtle.ConstantTimeCompare([]byte(pass), []byte(want)) == 1
+	}
+
+	key := sha256.Sum256([]byte(user + "\x00" + pass + "\x00" + want))
+	if _, ok := a.verified.Load(key); ok {
+		return true
+	}
+	if bcrypt.CompareHashAndPassword([]byte(want), []byte(pass)) != nil {
+		return false
+	}
+	a.verified.Store(key, true)
+	return true
+}
+
+// validToken returns true if a bearer token is known
+func (a *Authenticator) validToken(token string) bool {
+	ok := false
+	for _, t := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(t)) == 1 {
+			ok = true
+		}
+	}
+	return ok
+}
+
+// authenticated returns true if a request carries valid credentials, and whether it carried any
+func (a *Authenticator) authenticated(r *http.Request) (bool, bool) {
+	if user, pass, ok := r.BasicAuth(); ok {
+		return a.validUser(user, pass), true
+	}
+
+	if h := r.Header.Get("Authorization"); strings.HasPrefix(h, "Bearer ") {
+		return a.validToken(strings.TrimPrefix(h, "Bearer ")), true
+	}
+	return false, false
+}
+
+// Handler rejects requests without valid credentials with a 401
+func (a *Authenticator) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if a.exempt[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ok, supplied := a.authenticated(r)
+		if ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if supplied {
+			klog.Warningf("rejecting %s %s from %s: invalid credentials", r.Method, r.URL.Path, r.RemoteAddr)
+		}
+		if len(a.users) > 0 {
+			w.Header().Set("WWW-Authenticate", `Basic realm="Triage Party", charset="UTF-8"`)
+		} else {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+		}
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	})
+}
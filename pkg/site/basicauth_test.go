@@ -0,0 +1,62 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestAuthenticator(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("hashed"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("bcrypt: %v", err)
+	}
+
+	a, err := NewAuthenticator(map[string]string{"alice": "plain", "bob": string(hash)}, []string{"t0ken"}, DefaultAuthExempt)
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	h := a.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		path  string
+		setup func(r *http.Request)
+		want  int
+	}{
+		{"/", func(r *http.Request) {}, http.StatusUnauthorized},
+		{"/healthz", func(r *http.Request) {}, http.StatusOK},
+		{"/webhook", func(r *http.Request) {}, http.StatusOK},
+		{"/", func(r *http.Request) { r.SetBasicAuth("alice", "plain") }, http.StatusOK},
+		{"/", func(r *http.Request) { r.SetBasicAuth("alice", "wrong") }, http.StatusUnauthorized},
+		{"/", func(r *http.Request) { r.SetBasicAuth("bob", "hashed") }, http.StatusOK},
+		{"/", func(r *http.Request) { r.SetBasicAuth("bob", "hashed") }, http.StatusOK},
+		{"/", func(r *http.Request) { r.SetBasicAuth("bob", "plain") }, http.StatusUnauthorized},
+		{"/api/s/daily", func(r *http.Request) { r.Header.Set("Authorization", "Bearer t0ken") }, http.StatusOK},
+		{"/api/s/daily", func(r *http.Request) { r.Header.Set("Authorization", "Bearer nope") }, http.StatusUnauthorized},
+	}
+
+	for _, tc := range tests {
+		r := httptest.NewRequest("GET", tc.path, nil)
+		tc.setup(r)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		assert.Equal(t, tc.want, w.Code, "%s %v", tc.path, r.Header)
+	}
+}
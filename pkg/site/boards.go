@@ -0,0 +1,80 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"github.com/google/triage-party/pkg/triage"
+)
+
+// reservedBoardIDs are top-level paths used by the site itself
+var reservedBoardIDs = map[string]bool{
+	"s": true, "k": true, "static": true, "third_party": true, "healthz": true, "health": true,
+	"readyz": true, "threadz": true, "threads": true, "config": true, "stats": true, "debug": true,
+	"sla": true, "all": true, "version": true, "login": true, "oauth": true, "action": true, "suggest-assignee": true, "refresh": true,
+	"api": true, "admin": true, "metrics": true, "webhook": true,
+}
+
+// IsReservedBoardID returns whether a board ID would conflict with an existing path
+func IsReservedBoardID(id string) bool {
+	return reservedBoardIDs[id]
+}
+
+// Board returns handlers for a board, which share state with h but only show the board's collections
+func (h *Handlers) Board(b triage.Board) *Handlers {
+	bh := *h
+	bh.basePath = h.basePath + "/" + b.ID
+	bh.board = b.Collections
+	bh.boardAccess = b.Access
+	if b.Name != "" {
+		bh.siteName = b.Name
+	}
+	if b.LogoURL != "" {
+		bh.branding.LogoURL = b.LogoURL
+	}
+	return &bh
+}
+
+// onBoard filters collections to those shown by these handlers, in board order
+func (h *Handlers) onBoard(sts []triage.Collection) []triage.Collection {
+	if h.board == nil {
+		return sts
+	}
+
+	byID := map[string]triage.Collection{}
+	for _, s := range sts {
+		byID[s.ID] = s
+	}
+
+	shown := []triage.Collection{}
+	for _, id := range h.board {
+		if s, ok := byID[id]; ok {
+			shown = append(shown, s)
+		}
+	}
+	return shown
+}
+
+// onBoardID returns whether a collection is shown by these handlers
+func (h *Handlers) onBoardID(id string) bool {
+	if h.board == nil {
+		return true
+	}
+	for _, b := range h.board {
+		if b == id {
+			return true
+		}
+	}
+	return false
+}
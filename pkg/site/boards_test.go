@@ -0,0 +1,38 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"testing"
+
+	"github.com/google/triage-party/pkg/triage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoard(t *testing.T) {
+	h := New(&Config{Name: "Triage", BasePath: "/triage"})
+	b := h.Board(triage.Board{ID: "community", Name: "Community", Collections: []string{"c", "a"}})
+
+	assert.Equal(t, "/triage/community", b.basePath)
+	assert.Equal(t, "Community", b.siteName)
+	assert.Equal(t, "/triage/", b.cookiePath)
+	assert.Equal(t, h.sessionKey, b.sessionKey)
+
+	sts := []triage.Collection{{ID: "a"}, {ID: "b"}, {ID: "c"}}
+	assert.Equal(t, []triage.Collection{{ID: "c"}, {ID: "a"}}, b.onBoard(sts))
+	assert.Equal(t, sts, h.onBoard(sts))
+	assert.False(t, b.onBoardID("b"))
+	assert.True(t, h.onBoardID("b"))
+}
@@ -0,0 +1,71 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"github.com/google/triage-party/pkg/hubbub"
+	"github.com/google/triage-party/pkg/triage"
+)
+
+// minBotGroup is the fewest pull requests from one bot that are collapsed into a summary row
+const minBotGroup = 2
+
+// BotGroup is a set of pull requests opened by a single bot, shown as one summary row
+type BotGroup struct {
+	Login string
+	Items []*hubbub.Conversation
+}
+
+// collapseBots groups the pull requests opened by each bot within each rule result.
+// It returns the groups by rule ID, and the URLs of the items they contain by rule ID.
+func (h *Handlers) collapseBots(rrs []*triage.RuleResult) (map[string][]*BotGroup, map[string]map[string]bool) {
+	groups := map[string][]*BotGroup{}
+	collapsed := map[string]map[string]bool{}
+
+	for _, rr := range rrs {
+		byLogin := map[string]*BotGroup{}
+		order := []*BotGroup{}
+
+		for _, co := range rr.Items {
+			if co.Type != hubbub.PullRequest || !h.party.IsBot(co.Author) {
+				continue
+			}
+
+			login := co.Author.GetLogin()
+			g := byLogin[login]
+			if g == nil {
+				g = &BotGroup{Login: login}
+				byLogin[login] = g
+				order = append(order, g)
+			}
+			g.Items = append(g.Items, co)
+		}
+
+		for _, g := range order {
+			if len(g.Items) < minBotGroup {
+				continue
+			}
+			groups[rr.Rule.ID] = append(groups[rr.Rule.ID], g)
+			if collapsed[rr.Rule.ID] == nil {
+				collapsed[rr.Rule.ID] = map[string]bool{}
+			}
+			for _, co := range g.Items {
+				collapsed[rr.Rule.ID][co.URL] = true
+			}
+		}
+	}
+
+	return groups, collapsed
+}
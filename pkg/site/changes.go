@@ -0,0 +1,110 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/triage-party/pkg/hubbub"
+	"github.com/google/triage-party/pkg/triage"
+	"k8s.io/klog/v2"
+)
+
+// defaultChangesSince is how far back to look for changes if unspecified
+const defaultChangesSince = 7 * 24 * time.Hour
+
+// Changes shows what changed within a collection since a previous refresh.
+func (h *Handlers) Changes() http.HandlerFunc {
+	fmap := template.FuncMap{
+		"toJS":          toJS,
+		"toYAML":        toYAML,
+		"toJSfunc":      toJSfunc,
+		"toDays":        toDays,
+		"HumanDuration": h.humanDuration,
+		"RoughTime":     h.roughTime,
+		"UnixNano":      unixNano,
+		"Avatar":        avatar,
+		"Class":         className,
+		"TextColor":     textColor,
+		"LabelStyle":    h.labelStyle,
+	}
+	t := template.Must(template.New("changes").Funcs(withTemplateFuncs(fmap)).ParseFiles(
+		filepath.Join(h.baseDir, "changes.tmpl"),
+		filepath.Join(h.baseDir, "base.tmpl"),
+	))
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/s/"), "/changes")
+
+		since, err := parseSince(r.URL.Query().Get("since"))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("since: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		p, err := h.collectionPage(r, id, false)
+		if errors.Is(err, errForbidden) {
+			h.forbidden(w, r)
+			return
+		}
+		if err != nil {
+			http.Error(w, fmt.Sprintf("collection page for %q: %v", id, err), 500)
+			klog.Errorf("page: %v", err)
+			return
+		}
+
+		p.ChangesSince = since
+		p.SinceParam = r.URL.Query().Get("since")
+		if p.SinceParam == "" {
+			p.SinceParam = "7d"
+		}
+		if prev := h.updater.Previous(id, since); prev != nil && p.CollectionResult.RuleResults != nil {
+			p.Changes = triage.CompareCollectionResults(prev, p.CollectionResult)
+		}
+
+		err = t.ExecuteTemplate(w, "base", p)
+		if err != nil {
+			klog.Errorf("tmpl: %v", err)
+			return
+		}
+	}
+}
+
+// parseSince parses a relative duration (7d, 48h) or an absolute date into a timestamp
+func parseSince(s string) (time.Time, error) {
+	if s == "" {
+		return time.Now().Add(-1 * defaultChangesSince), nil
+	}
+
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+
+	d, _, _ := hubbub.ParseDuration(s)
+	if d == 0 {
+		return time.Time{}, fmt.Errorf("unable to parse %q as a date or duration", s)
+	}
+	return time.Now().Add(-1 * d), nil
+}
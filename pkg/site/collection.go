@@ -16,9 +16,11 @@
 package site
 
 import (
+	"errors"
 	"fmt"
 	"html/template"
 	"net/http"
+	"net/url"
 	"path/filepath"
 	"strings"
 
@@ -32,20 +34,36 @@ func (h *Handlers) Collection() http.HandlerFunc {
 		"toYAML":        toYAML,
 		"toJSfunc":      toJSfunc,
 		"toDays":        toDays,
-		"HumanDuration": humanDuration,
-		"RoughTime":     roughTime,
+		"HumanDuration": h.humanDuration,
+		"RoughTime":     h.roughTime,
 		"UnixNano":      unixNano,
 		"Avatar":        avatar,
 		"Class":         className,
 		"TextColor":     textColor,
+		"LabelStyle":    h.labelStyle,
+		"Markdown":      markdown,
+		"ItemLinks":     h.party.ItemLinks,
 	}
-	t := template.Must(template.New("collection").Funcs(fmap).ParseFiles(
+	t := template.Must(template.New("collection").Funcs(withTemplateFuncs(fmap)).ParseFiles(
 		filepath.Join(h.baseDir, "collection.tmpl"),
 		filepath.Join(h.baseDir, "base.tmpl"),
 	))
 
+	changes := h.Changes()
+	assignees := h.Assignees()
+
 	return func(w http.ResponseWriter, r *http.Request) {
-		klog.Infof("GET %s: %v", r.URL.Path, r.Header)
+		if strings.HasSuffix(r.URL.Path, "/changes") {
+			changes(w, r)
+			return
+		}
+
+		if strings.HasSuffix(r.URL.Path, "/assignees") || strings.HasSuffix(r.URL.Path, "/groups") {
+			assignees(w, r)
+			return
+		}
+
+		klog.Infof("GET %s?%s", r.URL.Path, r.URL.RawQuery)
 
 		id := strings.TrimPrefix(r.URL.Path, "/s/")
 		playerChoices := []string{"Select a player"}
@@ -62,25 +80,69 @@ func (h *Handlers) Collection() http.HandlerFunc {
 			playerNums = append(playerNums, i+1)
 		}
 
-		p, err := h.collectionPage(r.Context(), id, isRefresh(r))
+		p, err := h.collectionPage(r, id, isRefresh(r))
+		if errors.Is(err, errForbidden) {
+			h.forbidden(w, r)
+			return
+		}
 		if err != nil {
 			http.Error(w, fmt.Sprintf("collection page for %q: %v", id, err), 500)
 			klog.Errorf("page: %v", err)
 
 			return
 		}
+		p.User = h.user(r)
+		p.CSRFToken = h.csrfToken(r)
+		p.Density = h.density
+		if d := r.URL.Query().Get("density"); IsDensity(d) {
+			p.Density = d
+		}
 
 		result := p.CollectionResult
+		if ids := r.URL.Query()["rule"]; len(ids) > 0 && result.RuleResults != nil {
+			p.CollectionResult, p.IgnoredRules = ruleFilter(result, ids)
+			p.UniqueItems = uniqueItems(p.CollectionResult.RuleResults)
+			result = p.CollectionResult
+		} else if result.RuleResults != nil {
+			// Rules which were explicitly requested are shown regardless of their size
+			p.CollectionResult, p.SmallRules = sizeFilter(result)
+			p.UniqueItems = uniqueItems(p.CollectionResult.RuleResults)
+			result = p.CollectionResult
+		}
+
+		owner := r.URL.Query().Get("owner")
+		if result.RuleResults != nil {
+			p.Owners = ruleOwners(result.RuleResults)
+		}
+		if owner != "" && result.RuleResults != nil {
+			p.Owner = owner
+			p.CollectionResult = ownerFilter(result, owner)
+			p.UniqueItems = uniqueItems(p.CollectionResult.RuleResults)
+			p.Collections = h.ownedCollections(p.Collections, owner)
+			result = p.CollectionResult
+		}
 
 		if player > 0 && players > 1 {
 			p.CollectionResult = playerFilter(result, player, players)
 			p.UniqueItems = uniqueItems(p.CollectionResult.RuleResults)
 		}
+		p.Actionable = len(p.UniqueItems) - p.CollectionResult.Parked
+
+		if h.export(w, p, r.URL.Query().Get("format")) {
+			return
+		}
+
+		if p.Collection.CollapseBots && p.CollectionResult.RuleResults != nil {
+			p.BotGroups, p.BotCollapsed = h.collapseBots(p.CollectionResult.RuleResults)
+		}
 
 		getVars := ""
 		if players > 0 {
 			getVars = fmt.Sprintf("?player=%d&players=%d", player, players)
 		}
+		if p.Owner != "" {
+			getVars += "&owner=" + url.QueryEscape(p.Owner)
+		}
 
 		p.PlayerChoices = playerChoices
 		p.PlayerNums = playerNums
@@ -0,0 +1,158 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// compressibleTypes are content types worth compressing: formats such as images and archives are already compressed
+var compressibleTypes = []string{
+	"text/",
+	"application/json",
+	"application/javascript",
+	"application/xml",
+	"image/svg+xml",
+}
+
+// compressible returns true if a content type is worth compressing
+func compressible(contentType string) bool {
+	ct := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	for _, t := range compressibleTypes {
+		if strings.HasPrefix(ct, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptedEncoding returns the preferred supported encoding within an Accept-Encoding header, or ""
+func acceptedEncoding(header string) string {
+	best := ""
+	bestQ := 0.0
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		enc := strings.ToLower(strings.TrimSpace(fields[0]))
+		if enc != "gzip" && enc != "deflate" {
+			continue
+		}
+
+		q := 1.0
+		for _, p := range fields[1:] {
+			p = strings.TrimSpace(p)
+			if strings.HasPrefix(p, "q=") {
+				if v, err := strconv.ParseFloat(strings.TrimPrefix(p, "q="), 64); err == nil {
+					q = v
+				}
+			}
+		}
+
+		if q <= 0 {
+			continue
+		}
+
+		// gzip wins ties, as it is the more widely supported of the two
+		if q > bestQ || (q == bestQ && enc == "gzip") {
+			best = enc
+			bestQ = q
+		}
+	}
+	return best
+}
+
+// compressWriter compresses a response once its content type is known to be compressible
+type compressWriter struct {
+	http.ResponseWriter
+	encoding    string
+	method      string
+	w           io.WriteCloser
+	wroteHeader bool
+}
+
+func (c *compressWriter) WriteHeader(status int) {
+	if c.wroteHeader {
+		return
+	}
+	c.wroteHeader = true
+
+	h := c.Header()
+	h.Add("Vary", "Accept-Encoding")
+
+	if c.method != http.MethodHead && status != http.StatusNoContent && status != http.StatusNotModified &&
+		h.Get("Content-Encoding") == "" && compressible(h.Get("Content-Type")) {
+		h.Set("Content-Encoding", c.encoding)
+		h.Del("Content-Length")
+		if c.encoding == "gzip" {
+			c.w = gzip.NewWriter(c.ResponseWriter)
+		} else {
+			// The deflate encoding is zlib-wrapped (RFC 9110 section 8.4.1.2), rather than raw flate data
+			c.w = zlib.NewWriter(c.ResponseWriter)
+		}
+	}
+
+	c.ResponseWriter.WriteHeader(status)
+}
+
+func (c *compressWriter) Write(b []byte) (int, error) {
+	if !c.wroteHeader {
+		if c.Header().Get("Content-Type") == "" {
+			c.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+		c.WriteHeader(http.StatusOK)
+	}
+	if c.w == nil {
+		return c.ResponseWriter.Write(b)
+	}
+	return c.w.Write(b)
+}
+
+// Flush sends any buffered compressed data to the client
+func (c *compressWriter) Flush() {
+	if f, ok := c.w.(interface{ Flush() error }); ok {
+		f.Flush()
+	}
+	if f, ok := c.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Close flushes the compressor, if one was used
+func (c *compressWriter) Close() error {
+	if c.w == nil {
+		return nil
+	}
+	return c.w.Close()
+}
+
+// Compress gzip or deflate encodes text responses, such as HTML, JSON, and CSV, for clients which accept it.
+func Compress(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		enc := acceptedEncoding(r.Header.Get("Accept-Encoding"))
+		// Range requests refer to offsets within the uncompressed content
+		if enc == "" || r.Header.Get("Range") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressWriter{ResponseWriter: w, encoding: enc, method: r.Method}
+		defer cw.Close()
+		next.ServeHTTP(cw, r)
+	})
+}
@@ -0,0 +1,79 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAcceptedEncoding(t *testing.T) {
+	assert.Equal(t, "gzip", acceptedEncoding("gzip, deflate, br"))
+	assert.Equal(t, "deflate", acceptedEncoding("deflate, gzip;q=0.5"))
+	assert.Equal(t, "", acceptedEncoding("gzip;q=0, br"))
+	assert.Equal(t, "", acceptedEncoding(""))
+}
+
+func TestCompress(t *testing.T) {
+	h := Compress(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/logo.png" {
+			w.Header().Set("Content-Type", "image/png")
+		}
+		w.Write([]byte("<html><body>hello</body></html>"))
+	}))
+
+	req := httptest.NewRequest("GET", "/s/daily", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+	zr, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	body, err := ioutil.ReadAll(zr)
+	assert.NoError(t, err)
+	assert.Equal(t, "<html><body>hello</body></html>", string(body))
+
+	req = httptest.NewRequest("GET", "/s/daily", nil)
+	req.Header.Set("Accept-Encoding", "deflate")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, "deflate", rec.Header().Get("Content-Encoding"))
+	fr, err := zlib.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("zlib reader: %v", err)
+	}
+	body, err = ioutil.ReadAll(fr)
+	assert.NoError(t, err)
+	assert.Equal(t, "<html><body>hello</body></html>", string(body))
+
+	req = httptest.NewRequest("GET", "/logo.png", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, "", rec.Header().Get("Content-Encoding"))
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/s/daily", nil))
+	assert.Equal(t, "", rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, "<html><body>hello</body></html>", rec.Body.String())
+}
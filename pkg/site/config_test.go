@@ -0,0 +1,86 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/triage-party/pkg/persist"
+	"github.com/google/triage-party/pkg/triage"
+	"github.com/stretchr/testify/assert"
+)
+
+// testParty returns an offline party loaded with a config
+func testParty(t *testing.T, config string) *triage.Party {
+	t.Helper()
+	c, err := persist.NewMemory(persist.Config{})
+	if err != nil {
+		t.Fatalf("memory: %v", err)
+	}
+	tp, err := triage.New(triage.Config{Cache: c, Offline: true})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	if err := tp.Load(strings.NewReader(config)); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	return tp
+}
+
+func TestConfigRequiresAuth(t *testing.T) {
+	h := New(&Config{ConfigRequiresAuth: true, RefreshToken: "secret"})
+
+	w := httptest.NewRecorder()
+	h.Config()(w, httptest.NewRequest("GET", "/config", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestConfigHidesRestrictedCollections(t *testing.T) {
+	tp := testParty(t, `settings:
+  repos: [https://github.com/org/project]
+collections:
+  - id: public
+    rules: [open]
+  - id: private
+    rules: [secret]
+    access: [org/maintainers]
+rules:
+  open:
+    filters:
+      - state: open
+  secret:
+    filters:
+      - label: embargoed
+`)
+	h := New(&Config{Party: tp, RefreshToken: "secret"})
+
+	w := httptest.NewRecorder()
+	h.Config()(w, httptest.NewRequest("GET", "/config", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+	assert.Contains(t, body, "id: public")
+	assert.NotContains(t, body, "private")
+	assert.NotContains(t, body, "embargoed")
+	assert.NotContains(t, body, "org/maintainers")
+
+	w = httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/config", nil)
+	r.Header.Set("Authorization", "Bearer secret")
+	h.Config()(w, r)
+	assert.Contains(t, w.Body.String(), "id: private")
+}
@@ -0,0 +1,161 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/triage-party/pkg/hubbub"
+	"github.com/google/triage-party/pkg/tag"
+	"k8s.io/klog/v2"
+)
+
+// DebugItem is the cached state of an item, as shown by the debug endpoint
+type DebugItem struct {
+	*hubbub.Conversation
+
+	// Tags replaces the conversation's tag set, which can't be encoded as JSON
+	Tags []tag.Tag `json:"tags"`
+
+	IssueRefs       []*DebugRelated `json:"issue_refs"`
+	PullRequestRefs []*DebugRelated `json:"pull_request_refs"`
+	Similar         []*DebugRelated `json:"similar"`
+
+	// MatchedBy lists the rules which matched the item in each collection's latest results
+	MatchedBy map[string][]string `json:"matched_by"`
+}
+
+// DebugRelated is a related item, with its tags encodable as JSON
+type DebugRelated struct {
+	*hubbub.RelatedConversation
+	Tags []tag.Tag `json:"tags"`
+}
+
+// newDebugItem returns the debug view of a conversation
+func newDebugItem(co *hubbub.Conversation, matchedBy map[string][]string) *DebugItem {
+	related := func(rcs []*hubbub.RelatedConversation) []*DebugRelated {
+		ds := []*DebugRelated{}
+		for _, rc := range rcs {
+			ds = append(ds, &DebugRelated{RelatedConversation: rc, Tags: tagList(rc.Tags)})
+		}
+		return ds
+	}
+
+	return &DebugItem{
+		Conversation:    co,
+		Tags:            tagList(co.Tags),
+		IssueRefs:       related(co.IssueRefs),
+		PullRequestRefs: related(co.PullRequestRefs),
+		Similar:         related(co.Similar),
+		MatchedBy:       matchedBy,
+	}
+}
+
+// tagList returns a set of tags, sorted by ID
+func tagList(m map[tag.Tag]bool) []tag.Tag {
+	ts := []tag.Tag{}
+	for t := range m {
+		ts = append(ts, t)
+	}
+	sort.Slice(ts, func(i, j int) bool { return ts[i].ID < ts[j].ID })
+	return ts
+}
+
+// DebugIssue dumps the cached conversation for an item as JSON, for debugging rules.
+// Like refresh, it requires a logged in user or the refresh token, who may view a collection containing the item.
+func (h *Handlers) DebugIssue() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		klog.Infof("GET %s: %v", r.URL.Path, r.URL.Query())
+
+		if !h.refreshAllowed(r) {
+			http.Error(w, "debugging requires a login or a valid refresh token", http.StatusUnauthorized)
+			return
+		}
+
+		repo := strings.Trim(r.URL.Query().Get("repo"), "/")
+		num, err := strconv.Atoi(r.URL.Query().Get("number"))
+		if repo == "" || err != nil {
+			http.Error(w, "repo (owner/name or a repository URL) and number are required", http.StatusBadRequest)
+			return
+		}
+
+		var co *hubbub.Conversation
+		for _, u := range itemURLs(repo, num) {
+			if co = h.party.LookupItem(u); co != nil {
+				break
+			}
+		}
+
+		if co == nil {
+			http.Error(w, fmt.Sprintf("%s#%d has not been seen by any rule", repo, num), http.StatusNotFound)
+			return
+		}
+
+		// Items are only shown to those who may view a collection which contains them
+		matchedBy := h.matchedBy(r, co.URL)
+		if len(matchedBy) == 0 {
+			http.Error(w, fmt.Sprintf("%s#%d is not part of any collection you may view", repo, num), http.StatusNotFound)
+			return
+		}
+
+		d := newDebugItem(co, matchedBy)
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(d); err != nil {
+			klog.Errorf("encode: %v", err)
+		}
+	}
+}
+
+// itemURLs returns the possible issue and PR URLs for an item number within a repository
+func itemURLs(repo string, num int) []string {
+	base := repo
+	if !strings.Contains(repo, "://") {
+		base = "https://github.com/" + repo
+	}
+
+	urls := []string{}
+	for _, path := range []string{"issues", "pull", "-/issues", "-/merge_requests"} {
+		urls = append(urls, fmt.Sprintf("%s/%s/%d", base, path, num))
+	}
+	return urls
+}
+
+// matchedBy returns the rules which matched an item, by collection ID
+func (h *Handlers) matchedBy(r *http.Request, url string) map[string][]string {
+	m := map[string][]string{}
+	sts, err := h.party.ListCollections()
+	if err != nil {
+		klog.Errorf("collections: %v", err)
+		return m
+	}
+
+	for _, s := range h.visible(r, sts) {
+		cr := h.updater.Cached(s.ID)
+		if cr == nil {
+			continue
+		}
+		for _, rule := range cr.MatchedBy[url] {
+			m[s.ID] = append(m[s.ID], rule.ID)
+		}
+	}
+	return m
+}
@@ -0,0 +1,39 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/triage-party/pkg/hubbub"
+	"github.com/google/triage-party/pkg/tag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebugItemJSON(t *testing.T) {
+	co := &hubbub.Conversation{
+		URL:             "https://github.com/org/project/issues/1",
+		Tags:            map[tag.Tag]bool{tag.Similar: true, tag.Assigned: true},
+		PullRequestRefs: []*hubbub.RelatedConversation{{ID: 2, Tags: map[tag.Tag]bool{tag.Similar: true}}},
+	}
+
+	bs, err := json.Marshal(newDebugItem(co, map[string][]string{"c": {"r"}}))
+	assert.NoError(t, err)
+	s := string(bs)
+	assert.True(t, strings.Contains(s, `"tags":[{"id":"assigned"`), s)
+	assert.True(t, strings.Contains(s, `"matched_by":{"c":["r"]}`), s)
+}
@@ -0,0 +1,145 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/triage-party/pkg/triage"
+	"k8s.io/klog/v2"
+)
+
+// Export formats for a collection, as selected by ?format=
+const (
+	ExportCSV      = "csv"
+	ExportMarkdown = "md"
+)
+
+// exportHeader are the columns of an export
+var exportHeader = []string{"Number", "Title", "URL", "Author", "Age (days)", "Labels", "Rule"}
+
+// exportRows returns one row per item in a collection, listing every rule which matched it
+func exportRows(cr *triage.CollectionResult, now time.Time) [][]string {
+	rows := [][]string{}
+	if cr == nil {
+		return rows
+	}
+
+	index := map[string]int{}
+	for _, rr := range cr.RuleResults {
+		for _, co := range rr.Items {
+			if i, ok := index[co.URL]; ok {
+				rows[i][6] += "; " + rr.Rule.Name
+				continue
+			}
+
+			labels := []string{}
+			for _, l := range co.Labels {
+				labels = append(labels, l.GetName())
+			}
+
+			index[co.URL] = len(rows)
+			rows = append(rows, []string{
+				strconv.Itoa(co.ID),
+				co.Title,
+				co.URL,
+				co.Author.GetLogin(),
+				strconv.Itoa(int(now.Sub(co.Created).Hours() / 24)),
+				strings.Join(labels, ", "),
+				rr.Rule.Name,
+			})
+		}
+	}
+	return rows
+}
+
+// csvCell quotes a value which a spreadsheet would otherwise evaluate as a formula, such as a title starting with =
+func csvCell(s string) string {
+	if s != "" && strings.ContainsAny(s[:1], "=+-@\t\r") {
+		return "'" + s
+	}
+	return s
+}
+
+// writeCSV writes rows as CSV, with a header
+func writeCSV(w io.Writer, rows [][]string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(exportHeader); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, c := range row {
+			cells[i] = csvCell(c)
+		}
+		if err := cw.Write(cells); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// markdownCell escapes a value for use within a Markdown table
+func markdownCell(s string) string {
+	s = strings.NewReplacer("\\", "\\\\", "|", "\\|", "\r", " ", "\n", " ").Replace(s)
+	return strings.TrimSpace(s)
+}
+
+// writeMarkdown writes rows as a Markdown table
+func writeMarkdown(w io.Writer, rows [][]string) error {
+	lines := []string{
+		"| " + strings.Join(exportHeader, " | ") + " |",
+		"|" + strings.Repeat(" --- |", len(exportHeader)),
+	}
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, c := range row {
+			cells[i] = markdownCell(c)
+		}
+		lines = append(lines, "| "+strings.Join(cells, " | ")+" |")
+	}
+	_, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+// export writes the items of a collection page as a download, returning false if the format is unknown
+func (h *Handlers) export(w http.ResponseWriter, p *Page, format string) bool {
+	var contentType string
+	var write func(io.Writer, [][]string) error
+
+	switch format {
+	case ExportCSV:
+		contentType, write = "text/csv; charset=utf-8", writeCSV
+	case ExportMarkdown:
+		contentType, write = "text/markdown; charset=utf-8", writeMarkdown
+	default:
+		return false
+	}
+
+	now := time.Now()
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s.%s"`, p.ID, now.Format("2006-01-02"), format))
+	if err := write(w, exportRows(p.CollectionResult, now)); err != nil {
+		klog.Errorf("export %s: %v", format, err)
+	}
+	return true
+}
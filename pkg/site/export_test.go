@@ -0,0 +1,62 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/triage-party/pkg/hubbub"
+	"github.com/google/triage-party/pkg/provider"
+	"github.com/google/triage-party/pkg/triage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExport(t *testing.T) {
+	now := time.Now()
+	login := "octocat"
+	bug := "kind/bug"
+	co := &hubbub.Conversation{
+		ID:      7,
+		Title:   "crash | on, start",
+		URL:     "https://github.com/org/project/issues/7",
+		Author:  &provider.User{Login: &login},
+		Created: now.Add(-72 * time.Hour),
+		Labels:  []*provider.Label{{Name: &bug}},
+	}
+	cr := &triage.CollectionResult{
+		RuleResults: []*triage.RuleResult{
+			{Rule: triage.Rule{Name: "Bugs"}, Items: []*hubbub.Conversation{co}},
+			{Rule: triage.Rule{Name: "Old"}, Items: []*hubbub.Conversation{co}},
+		},
+	}
+
+	rows := exportRows(cr, now)
+	assert.Equal(t, [][]string{{"7", "crash | on, start", co.URL, "octocat", "3", "kind/bug", "Bugs; Old"}}, rows)
+
+	var b bytes.Buffer
+	assert.NoError(t, writeCSV(&b, rows))
+	assert.Equal(t, "Number,Title,URL,Author,Age (days),Labels,Rule\n7,\"crash | on, start\","+co.URL+",octocat,3,kind/bug,Bugs; Old\n", b.String())
+
+	b.Reset()
+	assert.NoError(t, writeMarkdown(&b, rows))
+	assert.Contains(t, b.String(), "| 7 | crash \\| on, start | "+co.URL+" | octocat | 3 | kind/bug | Bugs; Old |\n")
+
+	// Cells which a spreadsheet would evaluate as formulas are quoted
+	b.Reset()
+	assert.NoError(t, writeCSV(&b, [][]string{{"=HYPERLINK(\"x\")", "+1", "-1", "@SUM(A1)", "a=b"}}))
+	assert.Equal(t, "Number,Title,URL,Author,Age (days),Labels,Rule\n\"'=HYPERLINK(\"\"x\"\")\",'+1,'-1,'@SUM(A1),a=b\n", b.String())
+}
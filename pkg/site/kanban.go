@@ -15,6 +15,7 @@
 package site
 
 import (
+	"errors"
 	"fmt"
 	"html/template"
 	"math"
@@ -50,7 +51,7 @@ func avatarWide(u *provider.User) template.HTML {
 	return template.HTML(fmt.Sprintf(`<a href="%s" title="%s"><img src="%s" width="96" height="96"></a>`, u.GetHTMLURL(), u.GetLogin(), u.GetAvatarURL()))
 }
 
-func groupByUser(results []*triage.RuleResult, milestoneID int, dedup bool) []*Swimlane {
+func groupByUser(results []*triage.RuleResult, milestoneID int) []*Swimlane {
 	lanes := map[string]*Swimlane{}
 	seenItem := map[string]bool{}
 
@@ -69,7 +70,7 @@ func groupByUser(results []*triage.RuleResult, milestoneID int, dedup bool) []*S
 
 			for _, a := range assignees {
 				// Dedup across users and columns
-				if dedup && seenItem[co.URL] {
+				if r.Dedup && seenItem[co.URL] {
 					continue
 				}
 
@@ -115,15 +116,16 @@ func (h *Handlers) Kanban() http.HandlerFunc {
 		"toYAML":        toYAML,
 		"toJSfunc":      toJSfunc,
 		"toDays":        toDays,
-		"HumanDuration": humanDuration,
-		"RoughTime":     roughTime,
+		"HumanDuration": h.humanDuration,
+		"RoughTime":     h.roughTime,
 		"LateTime":      lateTime,
 		"UnixNano":      unixNano,
 		"Avatar":        avatarWide,
 		"Class":         className,
+		"Markdown":      markdown,
 	}
 
-	t := template.Must(template.New("kanban").Funcs(fmap).ParseFiles(
+	t := template.Must(template.New("kanban").Funcs(withTemplateFuncs(fmap)).ParseFiles(
 		filepath.Join(h.baseDir, "kanban.tmpl"),
 		filepath.Join(h.baseDir, "base.tmpl"),
 	))
@@ -132,7 +134,11 @@ func (h *Handlers) Kanban() http.HandlerFunc {
 		id := strings.TrimPrefix(r.URL.Path, "/k/")
 		milestoneID := getInt(r.URL, "milestone", -1)
 
-		p, err := h.collectionPage(r.Context(), id, isRefresh(r))
+		p, err := h.collectionPage(r, id, isRefresh(r))
+		if errors.Is(err, errForbidden) {
+			h.forbidden(w, r)
+			return
+		}
 		if err != nil {
 			http.Error(w, fmt.Sprintf("collection page for %q: %v", id, err), 500)
 			klog.Errorf("page: %v", err)
@@ -144,7 +150,7 @@ func (h *Handlers) Kanban() http.HandlerFunc {
 			klog.Infof("milestones chosen: %d, choices: %+v", milestoneID, milestones)
 
 			p.Description = p.Collection.Description
-			p.Swimlanes = groupByUser(p.CollectionResult.RuleResults, chosen.GetNumber(), p.Collection.Dedup)
+			p.Swimlanes = groupByUser(p.CollectionResult.RuleResults, chosen.GetNumber())
 			p.SelectorOptions = milestones
 			p.SelectorVar = "milestone"
 			p.Milestone = chosen
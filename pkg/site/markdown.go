@@ -0,0 +1,118 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"regexp"
+	"strings"
+)
+
+var (
+	mdHeadingRe = regexp.MustCompile(`^(#{1,3})\s+(.*)$`)
+	mdListRe    = regexp.MustCompile(`^[-*]\s+(.*)$`)
+	mdLinkRe    = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	mdBoldRe    = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	mdEmRe      = regexp.MustCompile(`\*([^*\s][^*]*)\*`)
+
+	// safeURLRe matches link destinations which may be rendered
+	safeURLRe = regexp.MustCompile(`^(https?://|mailto:|/|#)`)
+)
+
+// markdown renders a small, safe subset of markdown: paragraphs, headings, lists,
+// links, bold, emphasis, and code. All input is escaped, so raw HTML is never passed through.
+func markdown(s string) template.HTML {
+	var b strings.Builder
+	var para []string
+	inList := false
+
+	flush := func() {
+		if len(para) > 0 {
+			b.WriteString("<p>" + strings.Join(para, " ") + "</p>\n")
+			para = nil
+		}
+		if inList {
+			b.WriteString("</ul>\n")
+			inList = false
+		}
+	}
+
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			flush()
+			continue
+		}
+
+		if m := mdHeadingRe.FindStringSubmatch(line); m != nil {
+			flush()
+			level := len(m[1]) + 3
+			b.WriteString(fmt.Sprintf("<h%d>%s</h%d>\n", level, mdInline(m[2]), level))
+			continue
+		}
+
+		if m := mdListRe.FindStringSubmatch(line); m != nil {
+			if len(para) > 0 {
+				b.WriteString("<p>" + strings.Join(para, " ") + "</p>\n")
+				para = nil
+			}
+			if !inList {
+				b.WriteString("<ul>\n")
+				inList = true
+			}
+			b.WriteString("<li>" + mdInline(m[1]) + "</li>\n")
+			continue
+		}
+
+		if inList {
+			flush()
+		}
+		para = append(para, mdInline(line))
+	}
+	flush()
+
+	return template.HTML(b.String())
+}
+
+// mdInline renders inline markdown for a single line of text
+func mdInline(s string) string {
+	// Odd segments are within `code` spans, and are not processed further
+	parts := strings.Split(s, "`")
+	for i, p := range parts {
+		p = html.EscapeString(p)
+		if i%2 == 1 && i < len(parts)-1 {
+			parts[i] = "<code>" + p + "</code>"
+			continue
+		}
+
+		p = mdLinkRe.ReplaceAllStringFunc(p, func(l string) string {
+			m := mdLinkRe.FindStringSubmatch(l)
+			if !safeURLRe.MatchString(html.UnescapeString(m[2])) {
+				return m[1]
+			}
+			return fmt.Sprintf(`<a href="%s">%s</a>`, m[2], m[1])
+		})
+		p = mdBoldRe.ReplaceAllString(p, "<strong>$1</strong>")
+		p = mdEmRe.ReplaceAllString(p, "<em>$1</em>")
+		if i%2 == 1 {
+			// unmatched backtick
+			p = "`" + p
+		}
+		parts[i] = p
+	}
+	return strings.Join(parts, "")
+}
@@ -0,0 +1,41 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"html/template"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdown(t *testing.T) {
+	tests := []struct {
+		in   string
+		want template.HTML
+	}{
+		{"PRs awaiting **security** review", "<p>PRs awaiting <strong>security</strong> review</p>\n"},
+		{"ping #security if stale", "<p>ping #security if stale</p>\n"},
+		{"# Title\nsome `x<y` code", "<h4>Title</h4>\n<p>some <code>x&lt;y</code> code</p>\n"},
+		{"intro\n\n  * one\n  * *two*", "<p>intro</p>\n<ul>\n<li>one</li>\n<li><em>two</em></li>\n</ul>\n"},
+		{"see [docs](https://example.com/a?b=1&c=2)", "<p>see <a href=\"https://example.com/a?b=1&amp;c=2\">docs</a></p>\n"},
+		{"[bad](javascript:void)", "<p>bad</p>\n"},
+		{"<script>alert(1)</script>", "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>\n"},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.want, markdown(tc.in), tc.in)
+	}
+}
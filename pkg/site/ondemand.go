@@ -0,0 +1,82 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/triage-party/pkg/triage"
+
+	"golang.org/x/time/rate"
+	"k8s.io/klog/v2"
+)
+
+// onDemandRate limits how often page views may fetch items again, across the site
+var onDemandRate = rate.Every(2 * time.Second)
+
+// onDemandBurst is how many items a single page view may fetch at once
+const onDemandBurst = 5
+
+// onDemandRefresh fetches each shown item whose data is older than the on-demand age again, in the background.
+// Only that item is fetched, and then replaced within the cached results. Returns how many fetches were started.
+func (h *Handlers) onDemandRefresh(result *triage.CollectionResult) int {
+	if h.onDemandAge == 0 {
+		return 0
+	}
+
+	started := 0
+	seen := map[string]bool{}
+	for _, rr := range result.RuleResults {
+		for _, co := range rr.Items {
+			if seen[co.URL] || time.Since(co.Seen) < h.onDemandAge {
+				continue
+			}
+			seen[co.URL] = true
+
+			// Checked first, so that views of recently fetched items do not consume the site-wide budget
+			if h.refreshedWithin(co.URL) {
+				continue
+			}
+
+			if !h.onDemandLimiter.Allow() {
+				klog.V(1).Infof("on-demand fetch of %s is rate limited", co.URL)
+				return started
+			}
+
+			if wait := h.reserveRefresh(co.URL); wait > 0 {
+				continue
+			}
+
+			klog.Infof("%s data is %s old, fetching it on demand", co.URL, time.Since(co.Seen).Round(time.Second))
+			started++
+			go func(url string) {
+				if err := h.updater.RefreshItem(context.Background(), url); err != nil {
+					klog.Errorf("on-demand fetch of %s: %v", url, err)
+				}
+			}(co.URL)
+		}
+	}
+	return started
+}
+
+// refreshedWithin returns true if a collection or item was refreshed on demand within the refresh interval
+func (h *Handlers) refreshedWithin(id string) bool {
+	h.refreshMu.Lock()
+	defer h.refreshMu.Unlock()
+
+	last, ok := h.lastRefresh[id]
+	return ok && time.Since(last) < h.refreshInterval
+}
@@ -0,0 +1,80 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/triage-party/pkg/hubbub"
+	"github.com/google/triage-party/pkg/persist"
+	"github.com/google/triage-party/pkg/provider"
+	"github.com/google/triage-party/pkg/triage"
+	"github.com/google/triage-party/pkg/updater"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeGetter records which items are fetched, and is otherwise offline
+type fakeGetter struct {
+	fakeWriter
+}
+
+func (f *fakeGetter) IssuesGet(_ context.Context, sp provider.SearchParams) (*provider.Issue, *provider.Response, error) {
+	f.record(fmt.Sprintf("%s/%s#%d", sp.Repo.Organization, sp.Repo.Project, sp.IssueNumber))
+	return nil, nil, provider.ErrOffline
+}
+
+func TestOnDemandRefresh(t *testing.T) {
+	f := &fakeGetter{fakeWriter{Provider: provider.Offline()}}
+	c, err := persist.NewMemory(persist.Config{})
+	if err != nil {
+		t.Fatalf("memory: %v", err)
+	}
+	tp, err := triage.New(triage.Config{Cache: c, Provider: f})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	if err := tp.Load(strings.NewReader(actionConfig)); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	h := New(&Config{Party: tp, Updater: updater.New(updater.Config{Party: tp}), OnDemandAge: time.Minute, RefreshInterval: time.Hour})
+
+	stale := &hubbub.Conversation{URL: "https://github.com/org/project/issues/1", Seen: time.Now().Add(-time.Hour)}
+	fresh := &hubbub.Conversation{URL: "https://github.com/org/project/issues/2", Seen: time.Now()}
+	r := &triage.CollectionResult{RuleResults: []*triage.RuleResult{
+		{Items: []*hubbub.Conversation{stale, fresh}},
+		{Items: []*hubbub.Conversation{stale}},
+	}}
+
+	// Only the stale item is fetched, once, even when viewed again
+	assert.Equal(t, 1, h.onDemandRefresh(r))
+	assert.Equal(t, 0, h.onDemandRefresh(r))
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		f.mu.Lock()
+		calls := append([]string{}, f.calls...)
+		f.mu.Unlock()
+		if len(calls) > 0 || time.Now().After(deadline) {
+			assert.Equal(t, []string{"org/project#1"}, calls)
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"sort"
+
+	"github.com/google/triage-party/pkg/triage"
+)
+
+// orderCollections returns collections in navigation order: config order by default,
+// or by the item count of their latest results (largest first) if SortBySize is set.
+func (h *Handlers) orderCollections(sts []triage.Collection) []triage.Collection {
+	if !h.sortBySize {
+		return sts
+	}
+
+	totals := map[string]int{}
+	for _, s := range sts {
+		if r := h.updater.Cached(s.ID); r != nil {
+			totals[s.ID] = r.Total
+		}
+	}
+
+	ordered := make([]triage.Collection, len(sts))
+	copy(ordered, sts)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].Hidden != ordered[j].Hidden {
+			return !ordered[i].Hidden
+		}
+		return totals[ordered[i].ID] > totals[ordered[j].ID]
+	})
+	return ordered
+}
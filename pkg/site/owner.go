@@ -0,0 +1,69 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"sort"
+
+	"github.com/google/triage-party/pkg/triage"
+	"k8s.io/klog/v2"
+)
+
+// ruleOwners returns the sorted owners of a set of rule results
+func ruleOwners(rrs []*triage.RuleResult) []string {
+	seen := map[string]bool{}
+	owners := []string{}
+	for _, rr := range rrs {
+		if rr.Rule.Owner != "" && !seen[rr.Rule.Owner] {
+			seen[rr.Rule.Owner] = true
+			owners = append(owners, rr.Rule.Owner)
+		}
+	}
+	sort.Strings(owners)
+	return owners
+}
+
+// ownerFilter returns a result with only the rules owned by owner
+func ownerFilter(result *triage.CollectionResult, owner string) *triage.CollectionResult {
+	klog.Infof("Filtering for rules owned by %q", owner)
+
+	os := []*triage.RuleResult{}
+	for _, o := range result.RuleResults {
+		if o.Rule.Owner == owner {
+			os = append(os, o)
+		}
+	}
+
+	r := triage.SummarizeCollectionResult(result.Collection, os)
+	r.Created = result.Created
+	r.NewerThan = result.NewerThan
+	r.OldestInput = result.OldestInput
+	return r
+}
+
+// ownedCollections returns the collections which contain at least one rule owned by owner
+func (h *Handlers) ownedCollections(sts []triage.Collection, owner string) []triage.Collection {
+	owned := []triage.Collection{}
+	for _, s := range sts {
+		for _, id := range s.RuleIDs {
+			t, err := h.party.LookupRule(id)
+			if err == nil && t.Owner == owner {
+				owned = append(owned, s)
+				break
+			}
+		}
+	}
+	return owned
+}
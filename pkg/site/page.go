@@ -15,9 +15,9 @@
 package site
 
 import (
-	"context"
 	"fmt"
 	"html/template"
+	"net/http"
 	"time"
 
 	"github.com/google/triage-party/pkg/hubbub"
@@ -32,17 +32,26 @@ const (
 	VelocityStatsName = "__velocity__"
 )
 
-func (h *Handlers) collectionPage(ctx context.Context, id string, refresh bool) (*Page, error) {
+func (h *Handlers) collectionPage(r *http.Request, id string, refresh bool) (*Page, error) {
+	ctx := r.Context()
 	start := time.Now()
 
 	defer func() {
 		klog.Infof("Served %q request within %s", id, time.Since(start))
 	}()
 
+	if !h.onBoardID(id) {
+		return nil, fmt.Errorf("collection %q is not on this board", id)
+	}
+
 	s, err := h.party.LookupCollection(id)
 	if err != nil {
 		return nil, fmt.Errorf("lookup collection: %w", err)
 	}
+	if !h.canView(r, s) {
+		return nil, errForbidden
+	}
+	h.views.record(id)
 
 	sts, err := h.party.ListCollections()
 	if err != nil {
@@ -63,6 +72,15 @@ func (h *Handlers) collectionPage(ctx context.Context, id string, refresh bool)
 		}
 	}
 
+	// Rules using "involves: @me" depend on who is looking
+	user := h.user(r)
+	viewed := false
+	if result.RuleResults != nil {
+		filtered := viewerFilter(result, user)
+		viewed = filtered != result
+		result = filtered
+	}
+
 	total := 0
 	for _, o := range result.RuleResults {
 		total += len(o.Items)
@@ -73,10 +91,11 @@ func (h *Handlers) collectionPage(ctx context.Context, id string, refresh bool)
 	p := &Page{
 		ID:               s.ID,
 		Version:          VERSION,
-		SiteName:         h.siteName,
+		SiteName:         h.displayName(),
+		Branding:         h.branding,
 		Title:            s.Name,
 		Collection:       s,
-		Collections:      sts,
+		Collections:      h.orderCollections(h.visible(r, h.onBoard(sts))),
 		Description:      s.Description,
 		CollectionResult: result,
 		Total:            len(unique),
@@ -84,15 +103,25 @@ func (h *Handlers) collectionPage(ctx context.Context, id string, refresh bool)
 		UniqueItems:      unique,
 		ResultAge:        time.Since(result.OldestInput),
 		Status:           h.updater.Status(),
+		WriteMode:        h.writeMode,
+		Login:            h.oauth != nil,
+		BasePath:         h.basePath,
+		Heat:             h.party.HeatEnabled(),
 	}
 
-	if result.RuleResults == nil {
+	if result.RuleResults != nil && !refresh && h.onDemandRefresh(result) > 0 {
+		p.Notification = template.HTML("Fetching updates for some items in the background. Reload in a moment for the latest data.")
+	} else if result.RuleResults == nil {
 		p.Notification = template.HTML(fmt.Sprintf("No cached data found - performing initial data download (%d issues examined) ...", h.party.ConversationsTotal()))
 	} else if p.ResultAge > h.warnAge {
 		p.Notification = template.HTML(fmt.Sprintf(`Refreshing data in the background. Displayed data may be up to %s old. Use <a href="https://en.wikipedia.org/wiki/Wikipedia:Bypass_your_cache#Bypassing_cache">Shift-Reload</a> to force a data refresh at any time.`, humanDuration(time.Since(result.OldestInput))))
 		p.Stale = true
 	}
 
+	if viewed && user == "" && p.Notification == "" && h.oauth != nil {
+		p.Notification = template.HTML(`Some rules only show items involving you. <a href="` + h.basePath + `/login">Log in</a> to see them.`)
+	}
+
 	if result.Collection != nil && result.Collection.Velocity != "" {
 		p.VelocityStats = h.updater.Lookup(ctx, result.Collection.Velocity, false)
 	} else {
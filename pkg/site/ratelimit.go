@@ -0,0 +1,126 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/klog/v2"
+)
+
+// rateLimitIdle is how long a client goes without requests before its bucket is forgotten
+const rateLimitIdle = 10 * time.Minute
+
+// RateLimiter limits how often each client may make requests, using a token bucket per address
+type RateLimiter struct {
+	limit   rate.Limit
+	burst   int
+	trusted []*net.IPNet
+
+	mu      sync.Mutex
+	clients map[string]*rateClient
+	swept   time.Time
+}
+
+type rateClient struct {
+	limiter *rate.Limiter
+	seen    time.Time
+}
+
+// NewRateLimiter returns a limiter which allows each client perSecond requests, in bursts of up to burst.
+// X-Forwarded-For is believed for trusted proxies, as with NewAllowlist.
+func NewRateLimiter(perSecond float64, burst int, trustedProxies []string) (*RateLimiter, error) {
+	if perSecond <= 0 {
+		return nil, fmt.Errorf("rate must be positive, got %v", perSecond)
+	}
+	if burst < 1 {
+		return nil, fmt.Errorf("burst must be at least 1, got %d", burst)
+	}
+
+	trusted, err := parseNets(trustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("trusted proxies: %w", err)
+	}
+
+	return &RateLimiter{
+		limit:   rate.Limit(perSecond),
+		burst:   burst,
+		trusted: trusted,
+		clients: map[string]*rateClient{},
+		swept:   time.Now(),
+	}, nil
+}
+
+// reserve takes a token from the bucket of a client, returning how long to wait if none are left
+func (l *RateLimiter) reserve(key string, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.swept) > rateLimitIdle {
+		for k, c := range l.clients {
+			if now.Sub(c.seen) > rateLimitIdle {
+				delete(l.clients, k)
+			}
+		}
+		l.swept = now
+	}
+
+	c := l.clients[key]
+	if c == nil {
+		c = &rateClient{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.clients[key] = c
+	}
+	c.seen = now
+
+	if c.limiter.AllowN(now, 1) {
+		return 0
+	}
+
+	r := c.limiter.ReserveN(now, 1)
+	wait := r.DelayFrom(now)
+	r.CancelAt(now)
+	return wait
+}
+
+// Handler rejects requests from clients which exceed their rate with a 429. Static assets and health checks are exempt.
+func (l *RateLimiter) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isStatic(r.URL.Path) || r.URL.Path == "/healthz" || r.URL.Path == "/readyz" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ip := clientIP(r, l.trusted)
+		key := r.RemoteAddr
+		if ip != nil {
+			key = ip.String()
+		}
+
+		if wait := l.reserve(key, time.Now()); wait > 0 {
+			klog.V(1).Infof("rate limiting %s %s from %s (remote %s)", r.Method, r.URL.Path, key, r.RemoteAddr)
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			http.Error(w, "too many requests", http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,58 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	l, err := NewRateLimiter(0.001, 2, []string{"172.16.0.1"})
+	assert.NoError(t, err)
+	h := l.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	get := func(remote string, xff string, path string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", path, nil)
+		r.RemoteAddr = remote
+		if xff != "" {
+			r.Header.Set("X-Forwarded-For", xff)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, get("10.1.2.3:1234", "", "/").Code)
+	assert.Equal(t, http.StatusOK, get("10.1.2.3:5678", "", "/").Code)
+	w := get("10.1.2.3:1234", "", "/")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusOK, get("10.1.2.3:1234", "", "/static/tp.css").Code)
+	assert.Equal(t, http.StatusOK, get("10.1.2.3:1234", "", "/healthz").Code)
+
+	// Clients behind a trusted proxy have their own buckets
+	assert.Equal(t, http.StatusOK, get("172.16.0.1:1234", "10.9.9.9", "/").Code)
+	assert.Equal(t, http.StatusOK, get("172.16.0.1:1234", "10.9.9.9", "/").Code)
+	assert.Equal(t, http.StatusTooManyRequests, get("172.16.0.1:1234", "10.9.9.9", "/").Code)
+	assert.Equal(t, http.StatusOK, get("172.16.0.1:1234", "10.8.8.8", "/").Code)
+
+	_, err = NewRateLimiter(0, 1, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,112 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/triage-party/pkg/triage"
+	"k8s.io/klog/v2"
+)
+
+// RefreshResponse is returned by the refresh endpoint
+type RefreshResponse struct {
+	// Refreshed is the time of the latest results for each refreshed collection
+	Refreshed map[string]time.Time `json:"refreshed"`
+}
+
+// Refresh refreshes one or all collections on demand, at most once per refresh interval.
+func (h *Handlers) Refresh() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		klog.Infof("%s %s", r.Method, r.URL.Path)
+
+		if r.Method != http.MethodPost {
+			http.Error(w, "POST required", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if !h.refreshAllowed(r) {
+			http.Error(w, "refresh requires a login or a valid refresh token", http.StatusUnauthorized)
+			return
+		}
+
+		id := r.FormValue("collection")
+		var sts []triage.Collection
+		if id != "" {
+			if !h.onBoardID(id) {
+				http.Error(w, fmt.Sprintf("collection %q is not on this board", id), http.StatusNotFound)
+				return
+			}
+			s, err := h.party.LookupCollection(id)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("collection: %v", err), http.StatusNotFound)
+				return
+			}
+			if !h.canView(r, s) {
+				h.forbidden(w, r)
+				return
+			}
+			sts = append(sts, s)
+		} else {
+			var err error
+			sts, err = h.party.ListCollections()
+			if err != nil {
+				http.Error(w, fmt.Sprintf("list collections: %v", err), http.StatusInternalServerError)
+				return
+			}
+			sts = h.visible(r, h.onBoard(sts))
+		}
+
+		if wait := h.reserveRefresh(id); wait > 0 {
+			w.Header().Set("Retry-After", fmt.Sprintf("%d", int(wait.Seconds())+1))
+			http.Error(w, fmt.Sprintf("refreshed recently, try again in %s", wait.Round(time.Second)), http.StatusTooManyRequests)
+			return
+		}
+
+		resp := RefreshResponse{Refreshed: map[string]time.Time{}}
+		for _, s := range sts {
+			if cr := h.updater.ForceRefresh(r.Context(), s.ID); cr != nil {
+				resp.Refreshed[s.ID] = cr.Created
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			klog.Errorf("encode: %v", err)
+		}
+	}
+}
+
+// refreshAllowed returns true if the request is from a logged in user or has a valid refresh token
+func (h *Handlers) refreshAllowed(r *http.Request) bool {
+	return h.tokenAllowed(r) || h.user(r) != ""
+}
+
+// reserveRefresh records a manual refresh, returning how long to wait if one happened too recently
+func (h *Handlers) reserveRefresh(id string) time.Duration {
+	h.refreshMu.Lock()
+	defer h.refreshMu.Unlock()
+
+	if last, ok := h.lastRefresh[id]; ok {
+		if wait := h.refreshInterval - time.Since(last); wait > 0 {
+			return wait
+		}
+	}
+	h.lastRefresh[id] = time.Now()
+	return 0
+}
@@ -0,0 +1,77 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/triage-party/pkg/provider"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestReloadWhileServing is most useful with -race
+func TestReloadWhileServing(t *testing.T) {
+	config := func(i int) string {
+		return fmt.Sprintf(`settings:
+  name: site-%d
+  repos: [https://github.com/org/project]
+  label_colors:
+    bug: "#ff0000"
+collections:
+  - id: daily-%d
+    rules: [open]
+rules:
+  open:
+    filters:
+      - state: open
+`, i, i)
+	}
+
+	tp := testParty(t, config(0))
+	h := New(&Config{Party: tp})
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 1; i < 50; i++ {
+			assert.NoError(t, tp.Reload(strings.NewReader(config(i))))
+		}
+	}()
+
+	for i := 0; i < 50; i++ {
+		w := httptest.NewRecorder()
+		h.Config()(w, httptest.NewRequest("GET", "/config", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		r := httptest.NewRequest("GET", "/", nil)
+		tp.HeatEnabled()
+		tp.Restricted()
+		tp.Permitted(r.Context(), "someone", nil)
+		tp.LookupItem("https://github.com/org/project/issues/1")
+		tp.ConversationsTotal()
+		tp.LabelColor(&provider.Label{})
+		tp.Name()
+		if _, err := tp.ListRules(); err != nil {
+			t.Errorf("list rules: %v", err)
+		}
+	}
+	wg.Wait()
+}
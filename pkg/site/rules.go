@@ -0,0 +1,164 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/google/triage-party/pkg/provider"
+	"github.com/google/triage-party/pkg/triage"
+	"gopkg.in/yaml.v2"
+	"k8s.io/klog/v2"
+)
+
+// RuleInfo describes a loaded rule, for tools which introspect the configuration
+type RuleInfo struct {
+	ID          string              `json:"id"`
+	Name        string              `json:"name"`
+	Resolution  string              `json:"resolution,omitempty"`
+	Type        string              `json:"type,omitempty"`
+	Owner       string              `json:"owner,omitempty"`
+	Search      string              `json:"search,omitempty"`
+	Repos       []string            `json:"repos"`
+	Collections []string            `json:"collections"`
+	Filters     []map[string]string `json:"filters"`
+}
+
+// Rules returns the loaded rules, with the collections which show them, as JSON
+func (h *Handlers) Rules() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		klog.Infof("GET %s: %v", r.URL.Path, r.URL.Query())
+
+		if !h.refreshAllowed(r) {
+			http.Error(w, "listing rules requires a login or a valid refresh token", http.StatusUnauthorized)
+			return
+		}
+
+		ts, err := h.party.ListRules()
+		if err != nil {
+			http.Error(w, fmt.Sprintf("list rules: %v", err), 500)
+			return
+		}
+
+		sts, err := h.party.ListCollections()
+		if err != nil {
+			http.Error(w, fmt.Sprintf("list collections: %v", err), 500)
+			return
+		}
+
+		shown := h.visible(r, h.onBoard(sts))
+		rs, err := ruleInfos(withoutHidden(ts, sts, shown), shown, h.board != nil)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("rules: %v", err), 500)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(rs); err != nil {
+			klog.Errorf("encode: %v", err)
+		}
+	}
+}
+
+// ruleInfos describes rules in ID order. If onlyShown is set, rules outside of the collections are omitted.
+func ruleInfos(ts []triage.Rule, sts []triage.Collection, onlyShown bool) ([]RuleInfo, error) {
+	shownIn := map[string][]string{}
+	for _, s := range sts {
+		for _, id := range s.RuleIDs {
+			shownIn[id] = append(shownIn[id], s.ID)
+		}
+	}
+
+	rs := []RuleInfo{}
+	for _, t := range ts {
+		if onlyShown && len(shownIn[t.ID]) == 0 {
+			continue
+		}
+
+		fs, err := filterMaps(t.Filters)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %w", t.ID, err)
+		}
+
+		cs := shownIn[t.ID]
+		if cs == nil {
+			cs = []string{}
+		}
+
+		rs = append(rs, RuleInfo{
+			ID:          t.ID,
+			Name:        t.Name,
+			Resolution:  t.Resolution,
+			Type:        t.Type,
+			Owner:       t.Owner,
+			Search:      t.Search,
+			Repos:       t.Repos,
+			Collections: cs,
+			Filters:     fs,
+		})
+	}
+
+	sort.Slice(rs, func(i, j int) bool { return rs[i].ID < rs[j].ID })
+	return rs, nil
+}
+
+// withoutHidden omits rules which only collections outside of shown refer to, such as those the viewer may not access
+func withoutHidden(ts []triage.Rule, sts []triage.Collection, shown []triage.Collection) []triage.Rule {
+	visible := map[string]bool{}
+	for _, s := range shown {
+		for _, id := range s.RuleIDs {
+			visible[id] = true
+		}
+	}
+
+	hidden := map[string]bool{}
+	for _, s := range sts {
+		for _, id := range s.RuleIDs {
+			if !visible[id] {
+				hidden[id] = true
+			}
+		}
+	}
+
+	kept := []triage.Rule{}
+	for _, t := range ts {
+		if !hidden[t.ID] {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
+
+// filterMaps converts filters to maps keyed by the same names as the configuration file
+func filterMaps(fs []provider.Filter) ([]map[string]string, error) {
+	ms := []map[string]string{}
+	for _, f := range fs {
+		bs, err := yaml.Marshal(f)
+		if err != nil {
+			return nil, err
+		}
+		m := map[string]string{}
+		if err := yaml.Unmarshal(bs, &m); err != nil {
+			return nil, err
+		}
+		ms = append(ms, m)
+	}
+	return ms, nil
+}
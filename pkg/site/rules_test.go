@@ -0,0 +1,54 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"testing"
+
+	"github.com/google/triage-party/pkg/provider"
+	"github.com/google/triage-party/pkg/triage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuleInfos(t *testing.T) {
+	ts := []triage.Rule{
+		{ID: "stale", Name: "Stale", Filters: []provider.Filter{{State: "open", Updated: "+90d"}}},
+		{ID: "new", Name: "New", Type: "issue", Filters: []provider.Filter{{RawLabel: "!triage/.*"}}},
+	}
+	sts := []triage.Collection{{ID: "daily", RuleIDs: []string{"new"}}}
+
+	rs, err := ruleInfos(ts, sts, false)
+	if err != nil {
+		t.Fatalf("ruleInfos: %v", err)
+	}
+	assert.Equal(t, []string{"new", "stale"}, []string{rs[0].ID, rs[1].ID})
+	assert.Equal(t, []string{"daily"}, rs[0].Collections)
+	assert.Equal(t, []string{}, rs[1].Collections)
+	assert.Equal(t, []map[string]string{{"label": "!triage/.*"}}, rs[0].Filters)
+	assert.Equal(t, []map[string]string{{"state": "open", "updated": "+90d"}}, rs[1].Filters)
+
+	rs, err = ruleInfos(ts, sts, true)
+	if err != nil {
+		t.Fatalf("ruleInfos: %v", err)
+	}
+	assert.Len(t, rs, 1)
+
+	// Rules only shown by collections the viewer may not see are left out, while unused rules are kept
+	all := append(sts, triage.Collection{ID: "private", RuleIDs: []string{"secret", "new"}})
+	ts = append(ts, triage.Rule{ID: "secret"})
+	kept := withoutHidden(ts, all, sts)
+	assert.Equal(t, []string{"stale", "new"}, []string{kept[0].ID, kept[1].ID})
+	assert.Len(t, kept, 2)
+}
@@ -26,15 +26,20 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/triage-party/pkg/provider"
 
 	"github.com/google/triage-party/pkg/hubbub"
+	"github.com/google/triage-party/pkg/persist"
 	"github.com/google/triage-party/pkg/triage"
 	"github.com/google/triage-party/pkg/updater"
 
 	"github.com/dustin/go-humanize"
+	"golang.org/x/oauth2"
+	"golang.org/x/time/rate"
 	"gopkg.in/yaml.v2"
 
 	"k8s.io/klog/v2"
@@ -76,17 +81,104 @@ type Config struct {
 	WarnAge       time.Duration
 	Updater       *updater.Updater
 	Party         *triage.Party
+
+	// WriteMode enables label and close actions for logged in users with write access
+	WriteMode bool
+	OAuth     *oauth2.Config
+
+	Branding Branding
+
+	// SortBySize orders collections by their latest item count, rather than config order
+	SortBySize bool
+
+	// RefreshToken allows scripts to call the refresh endpoint without logging in
+	RefreshToken string
+	// RefreshInterval is the minimum time between manual refreshes of a collection
+	RefreshInterval time.Duration
+
+	// ConfigRequiresAuth only serves /config to logged in users, or requests with the refresh token
+	ConfigRequiresAuth bool
+
+	// WebhookSecrets verify GitHub webhook payloads. More than one may be given while rotating secrets.
+	WebhookSecrets []string
+
+	// OnDemandAge is how old results may be before a page view triggers a background refresh (0 disables)
+	OnDemandAge time.Duration
+
+	// Cache is used to retain view counts across restarts, if set
+	Cache persist.Cacher
+
+	// BasePath is the URL path the site is served under, such as "/triage" (default: the root)
+	BasePath string
+
+	// Density is the default item layout: comfortable or compact
+	Density string
+
+	// Ages is how ages are displayed: humanized (default) or exact. Filters always use exact values.
+	Ages string
+}
+
+// Branding customizes the appearance of the site
+type Branding struct {
+	// LogoURL replaces the logo shown next to the site name
+	LogoURL string
+	// FaviconURL replaces the default favicon
+	FaviconURL string
+	// Footer is trusted HTML shown at the bottom of each page
+	Footer template.HTML
 }
 
 func New(c *Config) *Handlers {
-	return &Handlers{
+	h := &Handlers{
 		baseDir:   c.BaseDirectory,
 		updater:   c.Updater,
 		party:     c.Party,
-		siteName:  c.Name,
+		name:      &atomic.Value{},
 		warnAge:   c.WarnAge,
 		startTime: time.Now(),
+		writeMode: c.WriteMode,
+		oauth:     c.OAuth,
+		branding:  c.Branding,
+
+		sortBySize: c.SortBySize,
+
+		refreshToken:    c.RefreshToken,
+		refreshInterval: c.RefreshInterval,
+		refreshMu:       &sync.Mutex{},
+		lastRefresh:     map[string]time.Time{},
+
+		webhookSecrets: c.WebhookSecrets,
+
+		configRequiresAuth: c.ConfigRequiresAuth,
+
+		onDemandAge:     c.OnDemandAge,
+		onDemandLimiter: rate.NewLimiter(onDemandRate, onDemandBurst),
+
+		views: newViewCounter(c.Cache),
+
+		basePath: c.BasePath,
+		density:  c.Density,
+		ages:     c.Ages,
+
+		cookiePath: c.BasePath + "/",
 	}
+
+	h.name.Store(c.Name)
+
+	if h.density == "" {
+		h.density = ComfortableDensity
+	}
+
+	if h.ages == "" {
+		h.ages = HumanizedAges
+	}
+
+	if h.oauth != nil {
+		h.sessionKey = newSessionKey()
+	} else if h.party != nil && h.party.Restricted() {
+		klog.Warningf("access lists are configured, but login is not: restricted collections will only be served with the refresh token")
+	}
+	return h
 }
 
 // Handlers is a mix of config and client interfaces to connect with.
@@ -97,6 +189,56 @@ type Handlers struct {
 	siteName  string
 	warnAge   time.Duration
 	startTime time.Time
+
+	// name is the site name, shared with board handlers; siteName overrides it for a board
+	name *atomic.Value
+
+	writeMode  bool
+	oauth      *oauth2.Config
+	sessionKey []byte
+
+	branding   Branding
+	sortBySize bool
+
+	refreshToken    string
+	refreshInterval time.Duration
+	refreshMu       *sync.Mutex
+	lastRefresh     map[string]time.Time
+
+	webhookSecrets []string
+
+	configRequiresAuth bool
+
+	onDemandAge     time.Duration
+	onDemandLimiter *rate.Limiter
+
+	views *viewCounter
+
+	basePath string
+	density  string
+	ages     string
+
+	// board lists the collections shown, if restricted to a board
+	board []string
+
+	// boardAccess lists who may view the board, if restricted
+	boardAccess []string
+
+	// cookiePath is shared by all boards, so that one login covers them
+	cookiePath string
+}
+
+// SetName changes the site name, for example after the configuration is reloaded
+func (h *Handlers) SetName(n string) {
+	h.name.Store(n)
+}
+
+// displayName is the name shown for these handlers
+func (h *Handlers) displayName() string {
+	if h.siteName != "" {
+		return h.siteName
+	}
+	return h.name.Load().(string)
 }
 
 // Root redirects to leaderboard.
@@ -107,7 +249,12 @@ func (h *Handlers) Root() http.HandlerFunc {
 			klog.Errorf("collections: %v", err)
 			return
 		}
-		http.Redirect(w, r, fmt.Sprintf("/s/%s", sts[0].ID), http.StatusSeeOther)
+		sts = h.orderCollections(h.visible(r, h.onBoard(sts)))
+		if len(sts) == 0 {
+			h.forbidden(w, r)
+			return
+		}
+		http.Redirect(w, r, fmt.Sprintf("%s/s/%s", h.basePath, sts[0].ID), http.StatusSeeOther)
 	}
 }
 
@@ -115,6 +262,7 @@ func (h *Handlers) Root() http.HandlerFunc {
 type Page struct {
 	Version      string
 	SiteName     string
+	Branding     Branding
 	ID           string
 	Title        string
 	Description  string
@@ -139,10 +287,14 @@ type Page struct {
 
 	ClosedPerDay float64
 
+	// Actionable is how many shown items do not match the collection count_filter
+	Actionable int
+
 	Collection  triage.Collection
 	Collections []triage.Collection
 
 	Swimlanes            []*Swimlane
+	Heat                 bool
 	CollectionResult     *triage.CollectionResult
 	SelectorVar          string
 	SelectorOptions      []Choice
@@ -152,12 +304,70 @@ type Page struct {
 	MilestoneCountOffset int
 	MilestoneVeryLate    bool
 
+	Changes      *triage.CollectionChanges
+	ChangesSince time.Time
+
+	// SinceParam is the since query of the changes view, defaulting to 7d
+	SinceParam string
+
+	WriteMode bool
+	Login     bool
+	User      string
+	// CSRFToken must be posted with write actions
+	CSRFToken string
+
+	// BasePath prefixes links within the site
+	BasePath string
+
+	// Density is the item layout: comfortable or compact
+	Density string
+
+	// Owner is the rule owner being filtered for, and Owners are the owners of rules within the collection
+	Owner  string
+	Owners []string
+
+	// IgnoredRules are requested rules which are not part of this collection
+	IgnoredRules []string
+	// SmallRules are names of rules hidden for matching fewer than their min_items
+	SmallRules []string
+
+	ItemGroups []*ItemGroup
+	SLARows    []*SLARow
+
+	// GroupBy is the label prefix items are grouped by, or empty to group by assignee
+	GroupBy string
+
+	// BotGroups are pull requests from bots collapsed into summary rows, by rule ID
+	BotGroups map[string][]*BotGroup
+	// BotCollapsed are the URLs of items shown within a BotGroup rather than as rows, by rule ID
+	BotCollapsed map[string]map[string]bool
+
+	// FlatItems are the items of every collection, for the flat view
+	FlatItems []*FlatItem
+	// FlatSort is the order of FlatItems
+	FlatSort string
+
 	OpenStats     *triage.CollectionResult
 	VelocityStats *triage.CollectionResult
 	GetVars       string
 	Status        string
 }
 
+// Columns returns how many columns each rule table has
+func (p *Page) Columns() int {
+	n := 14
+	if p.Heat {
+		n++
+	}
+	if p.WriteMode && p.User != "" {
+		n++
+	}
+	if p.CollectionResult != nil && len(p.CollectionResult.Pinned) > 0 {
+		n++
+	}
+	return n
+}
+
 // Choice is a selector choice
 type Choice struct {
 	Value    int
@@ -189,6 +399,16 @@ func getInt(url *url.URL, key string, fallback int) int {
 	return fallback
 }
 
+// withTemplateFuncs adds the functions available to user-provided templates, such as lower and date
+func withTemplateFuncs(fmap template.FuncMap) template.FuncMap {
+	for k, v := range triage.TemplateFuncs {
+		if _, ok := fmap[k]; !ok {
+			fmap[k] = v
+		}
+	}
+	return fmap
+}
+
 func toYAML(v interface{}) string {
 	s, err := yaml.Marshal(v)
 	if err != nil {
@@ -265,6 +485,12 @@ func textColor(s string) template.CSS {
 	return "fff"
 }
 
+// labelStyle colors a label chip, with text which contrasts with its background
+func (h *Handlers) labelStyle(l *provider.Label) template.CSS {
+	c := h.party.LabelColor(l)
+	return template.CSS(fmt.Sprintf("background-color: #%s; color: #%s;", c, textColor(c)))
+}
+
 func unixNano(t time.Time) int64 {
 	return t.UnixNano()
 }
@@ -326,12 +552,112 @@ func playerFilter(result *triage.CollectionResult, player int, players int) *tri
 			}
 		}
 
-		os = append(os, triage.SummarizeRuleResult(o.Rule, cs, seen))
+		rr := triage.SummarizeRuleResult(o.Rule, cs, seen)
+		rr.Stale = o.Stale
+		rr.Error = o.Error
+		rr.Dedup = o.Dedup
+		rr.Denied = o.Denied
+		os = append(os, rr)
 	}
 
 	return triage.SummarizeCollectionResult(result.Collection, os)
 }
 
+// viewerFilter returns a result where rules using "involves: @me" only contain items involving the user
+func viewerFilter(result *triage.CollectionResult, user string) *triage.CollectionResult {
+	os := []*triage.RuleResult{}
+	seen := map[string]*triage.Rule{}
+	filtered := false
+
+	for _, o := range result.RuleResults {
+		if !o.Rule.InvolvesViewer() {
+			os = append(os, o)
+			continue
+		}
+
+		filtered = true
+		cs := []*hubbub.Conversation{}
+		for _, i := range o.Items {
+			if user != "" && hubbub.Involves(i, user) {
+				cs = append(cs, i)
+			}
+		}
+
+		rr := triage.SummarizeRuleResult(o.Rule, cs, seen)
+		rr.Stale = o.Stale
+		rr.Error = o.Error
+		rr.Dedup = o.Dedup
+		rr.Denied = o.Denied
+		os = append(os, rr)
+	}
+
+	if !filtered {
+		return result
+	}
+
+	r := triage.SummarizeCollectionResult(result.Collection, os)
+	r.Pinned = result.Pinned
+	r.Created = result.Created
+	r.NewerThan = result.NewerThan
+	r.OldestInput = result.OldestInput
+	return r
+}
+
+// sizeFilter returns a result without rules which match fewer than their min_items, and the names of those rules
+func sizeFilter(result *triage.CollectionResult) (*triage.CollectionResult, []string) {
+	os := []*triage.RuleResult{}
+	small := []string{}
+	for _, o := range result.RuleResults {
+		if len(o.Items) < o.Rule.MinItems {
+			small = append(small, o.Rule.Name)
+			continue
+		}
+		os = append(os, o)
+	}
+
+	if len(small) == 0 {
+		return result, nil
+	}
+
+	r := triage.SummarizeCollectionResult(result.Collection, os)
+	r.Created = result.Created
+	r.NewerThan = result.NewerThan
+	r.OldestInput = result.OldestInput
+	return r, small
+}
+
+// ruleFilter returns a result containing only the requested rules, and which requested rules were not found
+func ruleFilter(result *triage.CollectionResult, ids []string) (*triage.CollectionResult, []string) {
+	klog.Infof("Filtering for rules: %v", ids)
+
+	want := map[string]bool{}
+	for _, id := range ids {
+		want[id] = true
+	}
+
+	os := []*triage.RuleResult{}
+	found := map[string]bool{}
+	for _, o := range result.RuleResults {
+		if want[o.Rule.ID] {
+			os = append(os, o)
+			found[o.Rule.ID] = true
+		}
+	}
+
+	invalid := []string{}
+	for _, id := range ids {
+		if !found[id] {
+			invalid = append(invalid, id)
+		}
+	}
+
+	r := triage.SummarizeCollectionResult(result.Collection, os)
+	r.Created = result.Created
+	r.NewerThan = result.NewerThan
+	r.OldestInput = result.OldestInput
+	return r, invalid
+}
+
 // Healthz returns a dummy healthz page - it's always happy here!
 func (h *Handlers) Healthz() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -340,10 +666,61 @@ func (h *Handlers) Healthz() http.HandlerFunc {
 	}
 }
 
+// Readyz returns 200 once results are available and the update loop is running, and 503 otherwise
+func (h *Handlers) Readyz() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := h.updater.Ready(h.warnAge); err != nil {
+			http.Error(w, fmt.Sprintf("not ready: %v", err), http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(fmt.Sprintf("ready: %s", h.updater.Status())))
+	}
+}
+
+// Config returns the effective configuration the server is running with
+func (h *Handlers) Config() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		klog.Infof("GET %s?%s", r.URL.Path, r.URL.RawQuery)
+		if h.configRequiresAuth && !h.refreshAllowed(r) {
+			http.Error(w, "the configuration requires a login or a valid refresh token", http.StatusUnauthorized)
+			return
+		}
+
+		if !h.permitted(r, h.boardAccess) {
+			h.forbidden(w, r)
+			return
+		}
+
+		sts, err := h.party.ListCollections()
+		if err != nil {
+			http.Error(w, fmt.Sprintf("collections: %v", err), 500)
+			return
+		}
+
+		boards := []triage.Board{}
+		for _, b := range h.party.Boards() {
+			if h.permitted(r, b.Access) {
+				boards = append(boards, b)
+			}
+		}
+
+		bs, err := h.party.EffectiveConfig(h.visible(r, h.onBoard(sts)), boards)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("effective config: %v", err), 500)
+			klog.Errorf("effective config: %v", err)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write(bs)
+	}
+}
+
 // Threadz returns a threadz page
 func (h *Handlers) Threadz() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		klog.Infof("GET %s: %v", r.URL.Path, r.Header)
+		klog.Infof("GET %s?%s", r.URL.Path, r.URL.RawQuery)
 		w.WriteHeader(http.StatusOK)
 		w.Write(stack())
 	}
@@ -0,0 +1,40 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"testing"
+
+	"github.com/google/triage-party/pkg/hubbub"
+	"github.com/google/triage-party/pkg/provider"
+	"github.com/google/triage-party/pkg/triage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFiltersKeepDenied(t *testing.T) {
+	mine := triage.Rule{ID: "mine", Filters: []provider.Filter{{Involves: provider.InvolvesViewer}}}
+	denied := []string{"https://github.com/org/secret"}
+	r := &triage.CollectionResult{RuleResults: []*triage.RuleResult{
+		{Rule: mine, Items: []*hubbub.Conversation{{ID: 1, URL: "a"}}, Denied: denied, Stale: true},
+	}}
+
+	for name, got := range map[string]*triage.CollectionResult{
+		"viewer": viewerFilter(r, "someone"),
+		"player": playerFilter(r, 1, 2),
+	} {
+		assert.Equal(t, denied, got.RuleResults[0].Denied, name)
+		assert.True(t, got.RuleResults[0].Stale, name)
+	}
+}
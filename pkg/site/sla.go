@@ -0,0 +1,119 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// SLARow is the SLA summary for a single collection
+type SLARow struct {
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	Updated       time.Time `json:"updated"`
+	Items         int       `json:"items"`
+	MedianAgeDays float64   `json:"median_age_days"`
+	SLADays       float64   `json:"sla_days,omitempty"`
+	Breached      int       `json:"breached"`
+}
+
+// SLA shows item counts, median ages, and SLA breaches for each collection, as HTML or JSON.
+func (h *Handlers) SLA() http.HandlerFunc {
+	fmap := template.FuncMap{
+		"toJS":          toJS,
+		"toYAML":        toYAML,
+		"toJSfunc":      toJSfunc,
+		"toDays":        toDays,
+		"HumanDuration": h.humanDuration,
+		"RoughTime":     h.roughTime,
+		"UnixNano":      unixNano,
+		"Avatar":        avatar,
+		"Class":         className,
+		"TextColor":     textColor,
+	}
+	t := template.Must(template.New("sla").Funcs(withTemplateFuncs(fmap)).ParseFiles(
+		filepath.Join(h.baseDir, "sla.tmpl"),
+		filepath.Join(h.baseDir, "base.tmpl"),
+	))
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		sts, err := h.party.ListCollections()
+		if err != nil {
+			http.Error(w, fmt.Sprintf("list collections: %v", err), 500)
+			return
+		}
+		sts = h.visible(r, h.onBoard(sts))
+
+		rows := []*SLARow{}
+		for _, s := range sts {
+			if s.Hidden {
+				continue
+			}
+
+			cr := h.updater.Lookup(r.Context(), s.ID, false)
+			if cr != nil && cr.RuleResults != nil {
+				cr = viewerFilter(cr, h.user(r))
+			}
+			if cr == nil || cr.SLA == nil {
+				continue
+			}
+
+			rows = append(rows, &SLARow{
+				ID:            s.ID,
+				Name:          s.Name,
+				Updated:       cr.Created,
+				Items:         cr.SLA.Items,
+				MedianAgeDays: cr.SLA.MedianAge.Hours() / 24,
+				SLADays:       cr.SLA.SLA.Hours() / 24,
+				Breached:      cr.SLA.Breached,
+			})
+		}
+
+		if strings.HasSuffix(r.URL.Path, ".json") {
+			w.Header().Set("Content-Type", "application/json")
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(rows); err != nil {
+				klog.Errorf("encode: %v", err)
+			}
+			return
+		}
+
+		p := &Page{
+			Version:     VERSION,
+			SiteName:    h.displayName(),
+			Branding:    h.branding,
+			Title:       "SLA report",
+			Collections: h.orderCollections(sts),
+			Status:      h.updater.Status(),
+			SLARows:     rows,
+			BasePath:    h.basePath,
+		}
+
+		err = t.ExecuteTemplate(w, "base", p)
+		if err != nil {
+			klog.Errorf("tmpl: %v", err)
+			return
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/triage-party/pkg/triage"
+	"k8s.io/klog/v2"
+)
+
+// SnapshotHeader describes the board a snapshot was taken of
+type SnapshotHeader struct {
+	Version  string    `json:"version"`
+	SiteName string    `json:"site_name"`
+	Created  time.Time `json:"created"`
+	Status   string    `json:"status"`
+}
+
+// SnapshotCollection is the latest result of a collection within a snapshot
+type SnapshotCollection struct {
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	Hidden      bool           `json:"hidden,omitempty"`
+	Created     time.Time      `json:"created"`
+	OldestInput time.Time      `json:"oldest_input"`
+	Total       int            `json:"total"`
+	Parked      int            `json:"parked,omitempty"`
+	Rules       []SnapshotRule `json:"rules"`
+}
+
+// SnapshotRule is the latest result of a rule within a snapshot
+type SnapshotRule struct {
+	ID         string       `json:"id"`
+	Name       string       `json:"name"`
+	Resolution string       `json:"resolution,omitempty"`
+	Created    time.Time    `json:"created"`
+	Stale      bool         `json:"stale,omitempty"`
+	Error      string       `json:"error,omitempty"`
+	Denied     []string     `json:"denied,omitempty"`
+	Truncated  int          `json:"truncated,omitempty"`
+	Items      []*DebugItem `json:"items"`
+}
+
+// newSnapshotCollection returns the snapshot of a collection result
+func newSnapshotCollection(s triage.Collection, cr *triage.CollectionResult) SnapshotCollection {
+	sc := SnapshotCollection{
+		ID:          s.ID,
+		Name:        s.Name,
+		Hidden:      s.Hidden,
+		Created:     cr.Created,
+		OldestInput: cr.OldestInput,
+		Total:       cr.Total,
+		Parked:      cr.Parked,
+		Rules:       []SnapshotRule{},
+	}
+
+	for _, rr := range cr.RuleResults {
+		sr := SnapshotRule{
+			ID:         rr.Rule.ID,
+			Name:       rr.Rule.Name,
+			Resolution: rr.Rule.Resolution,
+			Created:    rr.Created,
+			Stale:      rr.Stale,
+			Error:      rr.Error,
+			Denied:     rr.Denied,
+			Truncated:  rr.Truncated,
+			Items:      []*DebugItem{},
+		}
+		for _, co := range rr.Items {
+			sr.Items = append(sr.Items, newDebugItem(co, nil))
+		}
+		sc.Rules = append(sc.Rules, sr)
+	}
+	return sc
+}
+
+// Snapshot streams the latest results of every collection as a single JSON document
+func (h *Handlers) Snapshot() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		klog.Infof("GET %s: %v", r.URL.Path, r.URL.Query())
+
+		if !h.refreshAllowed(r) {
+			http.Error(w, "snapshots require a login or a valid refresh token", http.StatusUnauthorized)
+			return
+		}
+
+		sts, err := h.party.ListCollections()
+		if err != nil {
+			http.Error(w, fmt.Sprintf("list collections: %v", err), 500)
+			return
+		}
+		sts = h.visible(r, h.onBoard(sts))
+
+		// Taken at once, so that the snapshot is consistent even if a refresh completes while streaming
+		crs := h.updater.Results()
+
+		hdr, err := json.Marshal(SnapshotHeader{Version: VERSION, SiteName: h.displayName(), Created: time.Now(), Status: h.updater.Status()})
+		if err != nil {
+			http.Error(w, fmt.Sprintf("header: %v", err), 500)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		flusher, _ := w.(http.Flusher)
+
+		// The header fields are written first, followed by each collection as it is encoded
+		fmt.Fprintf(w, "%s,\"collections\":[", hdr[:len(hdr)-1])
+		first := true
+		for _, s := range sts {
+			cr := crs[s.ID]
+			if cr == nil || cr.RuleResults == nil {
+				continue
+			}
+			cr = viewerFilter(cr, h.user(r))
+
+			b, err := json.Marshal(newSnapshotCollection(s, cr))
+			if err != nil {
+				// Headers have already been sent, so the document is left incomplete
+				klog.Errorf("snapshot %q: %v", s.ID, err)
+				return
+			}
+			if !first {
+				fmt.Fprint(w, ",\n")
+			}
+			first = false
+			if _, err := w.Write(b); err != nil {
+				klog.Errorf("write: %v", err)
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		fmt.Fprint(w, "]}\n")
+	}
+}
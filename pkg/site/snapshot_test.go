@@ -0,0 +1,42 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/triage-party/pkg/hubbub"
+	"github.com/google/triage-party/pkg/tag"
+	"github.com/google/triage-party/pkg/triage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSnapshotCollection(t *testing.T) {
+	co := &hubbub.Conversation{ID: 7, URL: "https://github.com/org/project/issues/7", Tags: map[tag.Tag]bool{tag.Send: true}}
+	cr := &triage.CollectionResult{
+		Total:       1,
+		RuleResults: []*triage.RuleResult{{Rule: triage.Rule{ID: "untriaged", Name: "Untriaged"}, Items: []*hubbub.Conversation{co}, Truncated: 3}},
+	}
+
+	sc := newSnapshotCollection(triage.Collection{ID: "daily", Name: "Daily"}, cr)
+	assert.Equal(t, "daily", sc.ID)
+	assert.Equal(t, 3, sc.Rules[0].Truncated)
+	assert.Equal(t, 7, sc.Rules[0].Items[0].ID)
+
+	// Tags are keyed by struct, so must be converted to be encodable
+	_, err := json.Marshal(sc)
+	assert.NoError(t, err)
+}
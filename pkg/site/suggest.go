@@ -0,0 +1,128 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/triage-party/pkg/hubbub"
+	"github.com/google/triage-party/pkg/triage"
+	"k8s.io/klog/v2"
+)
+
+// SuggestAssignee suggests an assignee for an item from the configured assignee pools.
+// GET returns the suggestion as JSON, and POST applies it in write mode.
+func (h *Handlers) SuggestAssignee() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodPost {
+			http.Error(w, "GET or POST required", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, fmt.Sprintf("parse form: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		url := r.Form.Get("url")
+		if url == "" {
+			http.Error(w, "url is required", http.StatusBadRequest)
+			return
+		}
+
+		co, load := h.openItems(url)
+		s, err := h.party.SuggestAssignee(url, co, load)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("suggest: %v", err), http.StatusNotFound)
+			return
+		}
+
+		if r.Method == http.MethodGet {
+			w.Header().Set("Content-Type", "application/json")
+			if err := json.NewEncoder(w).Encode(s); err != nil {
+				klog.Errorf("encode: %v", err)
+			}
+			return
+		}
+
+		if !h.writeMode {
+			http.Error(w, "write mode is disabled", http.StatusForbidden)
+			return
+		}
+
+		user := h.user(r)
+		if user == "" {
+			http.Redirect(w, r, h.basePath+"/login", http.StatusSeeOther)
+			return
+		}
+
+		if !h.validCSRF(r) {
+			klog.Warningf("%s posted an assignment without a valid CSRF token", user)
+			http.Error(w, "invalid CSRF token", http.StatusForbidden)
+			return
+		}
+
+		a := triage.Action{URL: url, Kind: triage.AssignAction, Assignee: s.Assignee}
+		if err := h.party.Act(r.Context(), user, a); err != nil {
+			klog.Errorf("%s action %+v: %v", user, a, err)
+			if errors.Is(err, triage.ErrPermissionDenied) {
+				http.Error(w, fmt.Sprintf("%s may not modify %s", user, a.URL), http.StatusForbidden)
+				return
+			}
+			http.Error(w, fmt.Sprintf("action failed: %v", err), http.StatusInternalServerError)
+			return
+		}
+		h.party.RecordAssignment(s)
+		go h.refreshItem(a)
+
+		id := r.PostForm.Get("collection")
+		if id == "" {
+			http.Redirect(w, r, h.basePath+"/", http.StatusSeeOther)
+			return
+		}
+
+		http.Redirect(w, r, fmt.Sprintf("%s/s/%s", h.basePath, id), http.StatusSeeOther)
+	}
+}
+
+// openItems returns the cached conversation for a URL, if any, and open assignment counts across all collections
+func (h *Handlers) openItems(url string) (*hubbub.Conversation, map[string]int) {
+	sts, err := h.party.ListCollections()
+	if err != nil {
+		klog.Errorf("collections: %v", err)
+		return nil, nil
+	}
+
+	var found *hubbub.Conversation
+	cos := []*hubbub.Conversation{}
+	for _, s := range sts {
+		cr := h.updater.Cached(s.ID)
+		if cr == nil {
+			continue
+		}
+		for _, rr := range cr.RuleResults {
+			for _, co := range rr.Items {
+				if co.URL == url {
+					found = co
+				}
+				cos = append(cos, co)
+			}
+		}
+	}
+	return found, triage.OpenAssignments(cos)
+}
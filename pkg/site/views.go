@@ -0,0 +1,118 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/triage-party/pkg/persist"
+	"github.com/google/triage-party/pkg/provider"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// viewsKey is the cache key view counts are stored under
+	viewsKey = "site-view-counts"
+	// viewsSaveInterval is the minimum time between saving view counts to the cache
+	viewsSaveInterval = time.Minute
+)
+
+// viewCounter counts page views per collection
+type viewCounter struct {
+	mu       sync.Mutex
+	cache    persist.Cacher
+	counts   map[string]int
+	last     map[string]time.Time
+	lastSave time.Time
+}
+
+// newViewCounter returns a view counter, restoring any counts saved within the cache
+func newViewCounter(c persist.Cacher) *viewCounter {
+	v := &viewCounter{cache: c, counts: map[string]int{}, last: map[string]time.Time{}}
+	if c == nil {
+		return v
+	}
+
+	if t := c.GetNewerThan(viewsKey, time.Time{}); t != nil {
+		for id, n := range t.Counts {
+			v.counts[id] = n
+		}
+		klog.Infof("restored view counts for %d collections", len(v.counts))
+	}
+	return v
+}
+
+// record records a view of a collection
+func (v *viewCounter) record(id string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.counts[id]++
+	v.last[id] = time.Now()
+
+	if v.cache == nil || time.Since(v.lastSave) < viewsSaveInterval {
+		return
+	}
+
+	counts := map[string]int{}
+	for k, n := range v.counts {
+		counts[k] = n
+	}
+	if err := v.cache.Set(viewsKey, &provider.Thing{Counts: counts}); err != nil {
+		klog.Errorf("set %q failed: %v", viewsKey, err)
+	}
+	v.lastSave = time.Now()
+}
+
+// Stats shows how often each collection has been viewed, to help find unused collections
+func (h *Handlers) Stats() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sts, err := h.party.ListCollections()
+		if err != nil {
+			http.Error(w, fmt.Sprintf("collections: %v", err), 500)
+			return
+		}
+		sts = h.visible(r, h.onBoard(sts))
+
+		h.views.mu.Lock()
+		counts := map[string]int{}
+		last := map[string]time.Time{}
+		for _, s := range sts {
+			counts[s.ID] = h.views.counts[s.ID]
+			last[s.ID] = h.views.last[s.ID]
+		}
+		h.views.mu.Unlock()
+
+		sort.SliceStable(sts, func(i, j int) bool { return counts[sts[i].ID] > counts[sts[j].ID] })
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(tw, "COLLECTION\tVIEWS\tLAST VIEWED\n")
+		for _, s := range sts {
+			lv := "-"
+			if !last[s.ID].IsZero() {
+				lv = humanDuration(time.Since(last[s.ID])) + " ago"
+			}
+			fmt.Fprintf(tw, "%s\t%d\t%s\n", s.ID, counts[s.ID], lv)
+		}
+		fmt.Fprintf(tw, "\nLast viewed times are since the server started %s ago.\n", humanDuration(time.Since(h.startTime)))
+		tw.Flush()
+	}
+}
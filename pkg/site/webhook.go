@@ -0,0 +1,135 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// maxWebhookPayload is the largest webhook payload GitHub sends
+const maxWebhookPayload = 25 << 20
+
+// webhookEvents are the GitHub events which trigger a refresh
+var webhookEvents = map[string]bool{
+	"issues":                      true,
+	"issue_comment":               true,
+	"pull_request":                true,
+	"pull_request_review":         true,
+	"pull_request_review_comment": true,
+}
+
+// webhookPayload is the part of a GitHub webhook payload needed to find the affected repository
+type webhookPayload struct {
+	Repository struct {
+		HTMLURL string `json:"html_url"`
+	} `json:"repository"`
+}
+
+// WebhookResponse is returned by the webhook endpoint
+type WebhookResponse struct {
+	// Collections are the IDs of the collections being refreshed
+	Collections []string `json:"collections"`
+}
+
+// Webhook accepts GitHub webhook events, refreshing the collections which include the affected repository
+func (h *Handlers) Webhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		event := r.Header.Get("X-GitHub-Event")
+		klog.Infof("%s %s: event=%q delivery=%q", r.Method, r.URL.Path, event, r.Header.Get("X-GitHub-Delivery"))
+
+		if r.Method != http.MethodPost {
+			http.Error(w, "POST required", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if len(h.webhookSecrets) == 0 {
+			http.Error(w, "webhooks are not enabled", http.StatusNotFound)
+			return
+		}
+
+		body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookPayload))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("read: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		if !validWebhookSignature(h.webhookSecrets, body, r.Header.Get("X-Hub-Signature-256")) {
+			klog.Warningf("webhook %q from %s has an invalid signature", event, r.RemoteAddr)
+			http.Error(w, "invalid X-Hub-Signature-256", http.StatusUnauthorized)
+			return
+		}
+
+		if event == "ping" {
+			w.Write([]byte("pong"))
+			return
+		}
+
+		if !webhookEvents[event] {
+			klog.V(1).Infof("ignoring webhook event %q", event)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		var p webhookPayload
+		if err := json.Unmarshal(body, &p); err != nil {
+			http.Error(w, fmt.Sprintf("payload: %v", err), http.StatusBadRequest)
+			return
+		}
+		if p.Repository.HTMLURL == "" {
+			http.Error(w, "payload has no repository", http.StatusBadRequest)
+			return
+		}
+
+		ids := h.party.CollectionsForRepo(p.Repository.HTMLURL)
+		if len(ids) > 0 {
+			// GitHub gives up on deliveries which take longer than 10 seconds
+			go h.updater.RefreshRepo(context.Background(), p.Repository.HTMLURL, time.Now())
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		if err := json.NewEncoder(w).Encode(WebhookResponse{Collections: ids}); err != nil {
+			klog.Errorf("encode: %v", err)
+		}
+	}
+}
+
+// validWebhookSignature returns true if a payload was signed by any of the secrets
+func validWebhookSignature(secrets []string, body []byte, signature string) bool {
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil || !strings.HasPrefix(signature, "sha256=") {
+		return false
+	}
+
+	for _, s := range secrets {
+		m := hmac.New(sha256.New, []byte(s))
+		m.Write(body)
+		if hmac.Equal(m.Sum(nil), got) {
+			return true
+		}
+	}
+	return false
+}
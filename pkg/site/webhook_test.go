@@ -0,0 +1,37 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidWebhookSignature(t *testing.T) {
+	body := []byte(`{"action":"labeled"}`)
+	m := hmac.New(sha256.New, []byte("new"))
+	m.Write(body)
+	sig := "sha256=" + hex.EncodeToString(m.Sum(nil))
+
+	assert.True(t, validWebhookSignature([]string{"old", "new"}, body, sig))
+	assert.False(t, validWebhookSignature([]string{"old"}, body, sig))
+	assert.False(t, validWebhookSignature([]string{"new"}, []byte(`{"action":"closed"}`), sig))
+	assert.False(t, validWebhookSignature([]string{"new"}, body, ""))
+	assert.False(t, validWebhookSignature([]string{"new"}, body, "sha1="+hex.EncodeToString(m.Sum(nil))))
+}
@@ -0,0 +1,63 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// loadAccess validates an access list of logins and teams
+func loadAccess(access []string) error {
+	for _, a := range access {
+		if strings.TrimSpace(a) == "" || strings.ContainsAny(a, " \t") {
+			return fmt.Errorf("invalid user or team: %q", a)
+		}
+		if strings.Count(strings.TrimPrefix(a, "@"), "/") > 1 {
+			return fmt.Errorf("invalid team %q: expected org/team", a)
+		}
+	}
+	return nil
+}
+
+// Permitted returns true if a user is within an access list: an empty list permits everyone
+func (p *Party) Permitted(ctx context.Context, login string, access []string) bool {
+	if len(access) == 0 {
+		return true
+	}
+	if login == "" {
+		return false
+	}
+	return p.loadedEngine().UserMatches(ctx, login, access)
+}
+
+// Restricted returns true if any collection or board has an access list
+func (p *Party) Restricted() bool {
+	p.reposMu.RLock()
+	defer p.reposMu.RUnlock()
+
+	for _, c := range p.collections {
+		if len(c.Access) > 0 {
+			return true
+		}
+	}
+	for _, b := range p.settings.Boards {
+		if len(b.Access) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadAccess(t *testing.T) {
+	assert.NoError(t, loadAccess(nil))
+	assert.NoError(t, loadAccess([]string{"tstromberg", "@kubernetes/sig-cli", "google/triage"}))
+	assert.Error(t, loadAccess([]string{""}))
+	assert.Error(t, loadAccess([]string{"two words"}))
+	assert.Error(t, loadAccess([]string{"org/team/child"}))
+}
+
+func TestPermitted(t *testing.T) {
+	p := &Party{}
+	assert.True(t, p.Permitted(context.Background(), "", nil))
+	assert.False(t, p.Permitted(context.Background(), "", []string{"tstromberg"}))
+}
@@ -0,0 +1,150 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/google/triage-party/pkg/constants"
+	"github.com/google/triage-party/pkg/provider"
+	"k8s.io/klog/v2"
+)
+
+// Kinds of actions that may be taken in write mode
+const (
+	LabelAction   = "label"
+	UnlabelAction = "unlabel"
+	CloseAction   = "close"
+	AssignAction  = "assign"
+)
+
+// ErrPermissionDenied is returned if a user may not act on a repository
+var ErrPermissionDenied = errors.New("permission denied")
+
+// Action is a write operation against a single issue or PR
+type Action struct {
+	URL      string
+	Kind     string
+	Label    string
+	Assignee string
+}
+
+// Act performs an action on behalf of a github.com user, if they have write access to the repository
+func (p *Party) Act(ctx context.Context, user string, a Action) error {
+	sp, err := parseItemURL(a.URL)
+	if err != nil {
+		return fmt.Errorf("parse %q: %w", a.URL, err)
+	}
+
+	// Logins are github.com accounts, which say nothing about who owns the same username elsewhere
+	if sp.Repo.Host != constants.GitHubProviderHost {
+		klog.Warningf("%s is a github.com login, denying %s on %s", user, a.Kind, sp.Repo.Host)
+		return ErrPermissionDenied
+	}
+
+	pr := p.provider(sp.Repo.Host)
+	if pr == nil {
+		return fmt.Errorf("no provider configured for %s", sp.Repo.Host)
+	}
+
+	level, _, err := pr.RepositoriesGetPermissionLevel(ctx, sp, user)
+	if err != nil {
+		return fmt.Errorf("permission level: %w", err)
+	}
+
+	if level != provider.AdminPermission && level != provider.WritePermission {
+		klog.Warningf("%s has %q permission to %s/%s, denying %s", user, level, sp.Repo.Organization, sp.Repo.Project, a.Kind)
+		return ErrPermissionDenied
+	}
+
+	klog.Infof("%s is performing %s %q on %s", user, a.Kind, a.Label, a.URL)
+
+	switch a.Kind {
+	case LabelAction:
+		_, err = pr.IssuesAddLabelsToIssue(ctx, sp, []string{a.Label})
+	case UnlabelAction:
+		_, err = pr.IssuesRemoveLabelForIssue(ctx, sp, a.Label)
+	case CloseAction:
+		state := constants.ClosedState
+		_, err = pr.IssuesEdit(ctx, sp, &provider.IssueRequest{State: &state})
+	case AssignAction:
+		if a.Assignee == "" {
+			return fmt.Errorf("assign requires an assignee")
+		}
+		_, err = pr.IssuesEdit(ctx, sp, &provider.IssueRequest{Assignees: &[]string{a.Assignee}})
+	default:
+		return fmt.Errorf("unknown action: %q", a.Kind)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", a.Kind, err)
+	}
+	return nil
+}
+
+func (p *Party) provider(host string) provider.Provider {
+	p.reposMu.RLock()
+	pr, ok := p.hosts[host]
+	p.reposMu.RUnlock()
+	if ok {
+		return pr
+	}
+	if host == constants.GitLabProviderHost {
+		return p.gitlab
+	}
+	return p.github
+}
+
+// parseItemURL parses an issue or PR URL into search parameters, for example:
+// https://github.com/org/project/issues/1 or https://gitlab.com/org/project/-/merge_requests/1
+func parseItemURL(rawURL string) (provider.SearchParams, error) {
+	sp := provider.SearchParams{}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return sp, err
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 4 {
+		return sp, fmt.Errorf("expected at least 4 path parts, got %d: %v", len(parts), parts)
+	}
+
+	num, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil {
+		return sp, fmt.Errorf("item number: %w", err)
+	}
+
+	repoParts := parts[:len(parts)-2]
+	if repoParts[len(repoParts)-1] == "-" {
+		repoParts = repoParts[:len(repoParts)-1]
+	}
+
+	sp.Repo, err = parseRepo(fmt.Sprintf("%s://%s/%s", u.Scheme, u.Host, strings.Join(repoParts, "/")))
+	if err != nil {
+		return sp, err
+	}
+	sp.IssueNumber = num
+
+	switch parts[len(parts)-2] {
+	case "pull", "pulls", "merge_requests":
+		sp.PullRequest = true
+	}
+	return sp, nil
+}
@@ -0,0 +1,220 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/triage-party/pkg/constants"
+	"github.com/google/triage-party/pkg/hubbub"
+	"github.com/google/triage-party/pkg/provider"
+)
+
+// Assignment strategies for assignee pools
+const (
+	RoundRobin  = "round-robin"
+	LeastLoaded = "least-loaded"
+)
+
+// AssigneePool is a set of people who share triage for a repository or area
+type AssigneePool struct {
+	Name string `yaml:"name"`
+	// Repos the pool applies to (default: all)
+	Repos []string `yaml:"repos,omitempty"`
+	// Labels the pool applies to, any of which must be present (default: all)
+	Labels []string `yaml:"labels,omitempty"`
+	// Strategy is round-robin (default) or least-loaded
+	Strategy string `yaml:"strategy,omitempty"`
+	// Members maps logins to weights: a member with weight 2 is suggested twice as often
+	Members map[string]int `yaml:"members"`
+}
+
+// Suggestion is a suggested assignee for an item
+type Suggestion struct {
+	Assignee string `json:"assignee"`
+	Pool     string `json:"pool"`
+	Strategy string `json:"strategy"`
+}
+
+type assigneePool struct {
+	AssigneePool
+	repos  []provider.Repo
+	labels map[string]bool
+
+	mu       sync.Mutex
+	assigned map[string]int
+}
+
+// loadAssigneePools validates assignee pools
+func loadAssigneePools(ps []AssigneePool) ([]*assigneePool, error) {
+	pools := []*assigneePool{}
+	for _, ap := range ps {
+		if ap.Name == "" {
+			return nil, fmt.Errorf("assignee pool requires a name")
+		}
+		if len(ap.Members) == 0 {
+			return nil, fmt.Errorf("assignee pool %q has no members", ap.Name)
+		}
+
+		switch ap.Strategy {
+		case "":
+			ap.Strategy = RoundRobin
+		case RoundRobin, LeastLoaded:
+		default:
+			return nil, fmt.Errorf("assignee pool %q: unknown strategy %q", ap.Name, ap.Strategy)
+		}
+
+		for m, w := range ap.Members {
+			if w <= 0 {
+				return nil, fmt.Errorf("assignee pool %q: %s has a non-positive weight: %d", ap.Name, m, w)
+			}
+		}
+
+		p := &assigneePool{AssigneePool: ap, labels: map[string]bool{}, assigned: map[string]int{}}
+		for _, r := range ap.Repos {
+			repo, err := parseRepo(r)
+			if err != nil {
+				return nil, fmt.Errorf("assignee pool %q: repo %q: %w", ap.Name, r, err)
+			}
+			p.repos = append(p.repos, repo)
+		}
+		for _, l := range ap.Labels {
+			p.labels[strings.ToLower(l)] = true
+		}
+		pools = append(pools, p)
+	}
+	return pools, nil
+}
+
+// matches returns whether an item within a repository is covered by the pool
+func (ap *assigneePool) matches(repo provider.Repo, co *hubbub.Conversation) bool {
+	if len(ap.repos) > 0 {
+		found := false
+		for _, r := range ap.repos {
+			if strings.EqualFold(r.Host, repo.Host) && strings.EqualFold(r.Organization, repo.Organization) && strings.EqualFold(r.Project, repo.Project) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(ap.labels) == 0 {
+		return true
+	}
+	if co == nil {
+		return false
+	}
+	for _, l := range co.Labels {
+		if ap.labels[strings.ToLower(l.GetName())] {
+			return true
+		}
+	}
+	return false
+}
+
+// pick returns the member with the lowest weighted count, excluding the item author
+func (ap *assigneePool) pick(co *hubbub.Conversation, load map[string]int) string {
+	ap.mu.Lock()
+	defer ap.mu.Unlock()
+
+	counts := ap.assigned
+	if ap.Strategy == LeastLoaded {
+		counts = load
+	}
+
+	logins := []string{}
+	for m := range ap.Members {
+		if co != nil && strings.EqualFold(co.Author.GetLogin(), m) && len(ap.Members) > 1 {
+			continue
+		}
+		logins = append(logins, m)
+	}
+	sort.Strings(logins)
+
+	best := ""
+	bestScore := 0.0
+	for _, m := range logins {
+		score := float64(counts[m]) / float64(ap.Members[m])
+		if best == "" || score < bestScore {
+			best = m
+			bestScore = score
+		}
+	}
+	return best
+}
+
+// record notes that a member was assigned an item, advancing the round-robin
+func (ap *assigneePool) record(login string) {
+	ap.mu.Lock()
+	defer ap.mu.Unlock()
+	ap.assigned[login]++
+}
+
+// SuggestAssignee suggests an assignee for an item URL from the first matching pool.
+// co may be nil if the item has not been seen, in which case only repository-wide pools match.
+// load is the number of open items assigned to each login, used by the least-loaded strategy.
+func (p *Party) SuggestAssignee(url string, co *hubbub.Conversation, load map[string]int) (*Suggestion, error) {
+	sp, err := parseItemURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("parse %q: %w", url, err)
+	}
+
+	for _, ap := range p.assignPools() {
+		if !ap.matches(sp.Repo, co) {
+			continue
+		}
+		return &Suggestion{Assignee: ap.pick(co, load), Pool: ap.Name, Strategy: ap.Strategy}, nil
+	}
+	return nil, fmt.Errorf("no assignee pool matches %s", url)
+}
+
+// assignPools returns the assignee pools, which Reload may replace
+func (p *Party) assignPools() []*assigneePool {
+	p.reposMu.RLock()
+	defer p.reposMu.RUnlock()
+	return p.pools
+}
+
+// RecordAssignment notes that a suggestion was applied
+func (p *Party) RecordAssignment(s *Suggestion) {
+	for _, ap := range p.assignPools() {
+		if ap.Name == s.Pool {
+			ap.record(s.Assignee)
+			return
+		}
+	}
+}
+
+// OpenAssignments counts the open items assigned to each login
+func OpenAssignments(cos []*hubbub.Conversation) map[string]int {
+	load := map[string]int{}
+	seen := map[string]bool{}
+	for _, co := range cos {
+		if seen[co.URL] || co.State != constants.OpenState {
+			continue
+		}
+		seen[co.URL] = true
+		for _, a := range co.Assignees {
+			load[a.GetLogin()]++
+		}
+	}
+	return load
+}
@@ -0,0 +1,55 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"testing"
+
+	"github.com/google/triage-party/pkg/hubbub"
+	"github.com/google/triage-party/pkg/provider"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuggestAssignee(t *testing.T) {
+	pools, err := loadAssigneePools([]AssigneePool{
+		{Name: "ui", Labels: []string{"area/ui"}, Strategy: LeastLoaded, Members: map[string]int{"alice": 2, "bob": 1}},
+		{Name: "rest", Repos: []string{"https://github.com/org/project"}, Members: map[string]int{"carol": 2, "dave": 1}},
+	})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	p := &Party{pools: pools}
+	url := "https://github.com/org/project/issues/1"
+
+	ui := &hubbub.Conversation{Labels: []*provider.Label{{Name: strPtr("area/ui")}}}
+	s, err := p.SuggestAssignee(url, ui, map[string]int{"alice": 3, "bob": 1})
+	assert.NoError(t, err)
+	assert.Equal(t, &Suggestion{Assignee: "bob", Pool: "ui", Strategy: LeastLoaded}, s)
+
+	// carol has twice dave's weight, so is suggested twice as often
+	got := []string{}
+	for i := 0; i < 6; i++ {
+		s, err := p.SuggestAssignee(url, nil, nil)
+		assert.NoError(t, err)
+		p.RecordAssignment(s)
+		got = append(got, s.Assignee)
+	}
+	assert.Equal(t, []string{"carol", "dave", "carol", "carol", "dave", "carol"}, got)
+
+	_, err = p.SuggestAssignee("https://github.com/org/other/issues/1", nil, nil)
+	assert.Error(t, err)
+}
+
+func strPtr(s string) *string { return &s }
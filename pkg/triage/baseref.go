@@ -0,0 +1,74 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/triage-party/pkg/hubbub"
+	"github.com/google/triage-party/pkg/provider"
+)
+
+// loadBaseRefExclusions validates the exclude_base_refs setting
+func loadBaseRefExclusions(refs []string) error {
+	for _, r := range refs {
+		f := provider.Filter{RawBaseRef: r}
+		if err := f.LoadBaseRefRegex(); err != nil {
+			return fmt.Errorf("%q: %w", r, err)
+		}
+	}
+	return nil
+}
+
+// excludeBaseRefs adds a negated base-ref filter for each excluded ref to rules which may show pull requests,
+// unless they set all_base_refs or have a base-ref filter of their own
+func excludeBaseRefs(rules map[string]Rule, refs []string) map[string]Rule {
+	if len(refs) == 0 {
+		return rules
+	}
+
+	for id, r := range rules {
+		if r.AllBaseRefs || strings.ToLower(r.Type) == hubbub.Issue || hasBaseRef(r.Filters) {
+			continue
+		}
+
+		fs := append([]provider.Filter{}, r.Filters...)
+		for _, ref := range refs {
+			fs = append(fs, provider.Filter{RawBaseRef: negateBaseRef(ref)})
+		}
+		r.Filters = fs
+		rules[id] = r
+	}
+	return rules
+}
+
+// hasBaseRef returns whether filters include a base-ref filter
+func hasBaseRef(fs []provider.Filter) bool {
+	for _, f := range fs {
+		if f.RawBaseRef != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// negateBaseRef inverts a base-ref filter, so that "!default" becomes "default"
+func negateBaseRef(ref string) string {
+	if strings.HasPrefix(ref, "!") {
+		return strings.TrimPrefix(ref, "!")
+	}
+	return "!" + ref
+}
@@ -0,0 +1,79 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"fmt"
+	"regexp"
+)
+
+var boardIDRe = regexp.MustCompile(`^[a-z0-9-]+$`)
+
+// Board is a named subset of collections, served under its own path
+type Board struct {
+	// ID is used as the URL path, such as /maintainers
+	ID   string `yaml:"id"`
+	Name string `yaml:"name"`
+	// Collections shown on this board, by ID, in display order
+	Collections []string `yaml:"collections"`
+	// LogoURL replaces the site logo for this board
+	LogoURL string `yaml:"logo_url,omitempty"`
+	// Access lists the users and teams, such as org/team, who may view the board (default: everyone)
+	Access []string `yaml:"access,omitempty"`
+}
+
+// loadBoards validates boards against the configured collections
+func loadBoards(bs []Board, cs []Collection) error {
+	known := map[string]bool{}
+	for _, c := range cs {
+		known[c.ID] = true
+	}
+
+	seen := map[string]bool{}
+	var errs ConfigErrors
+	for _, b := range bs {
+		if !boardIDRe.MatchString(b.ID) {
+			errs = errs.add("settings.boards", fmt.Errorf("invalid board id %q: expected lowercase letters, digits and dashes", b.ID))
+			continue
+		}
+		key := "settings.boards." + b.ID
+		if seen[b.ID] {
+			errs = errs.add(key, fmt.Errorf("duplicate board"))
+		}
+		seen[b.ID] = true
+
+		if len(b.Collections) == 0 {
+			errs = errs.add(key, fmt.Errorf("no collections"))
+		}
+		for _, id := range b.Collections {
+			if !known[id] {
+				errs = errs.add(key, fmt.Errorf("unknown collection %q", id))
+			}
+		}
+		errs = errs.add(key+".access", loadAccess(b.Access))
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Boards returns the configured boards
+func (p *Party) Boards() []Board {
+	p.reposMu.RLock()
+	defer p.reposMu.RUnlock()
+	return p.settings.Boards
+}
@@ -0,0 +1,58 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/triage-party/pkg/provider"
+	"k8s.io/klog/v2"
+)
+
+// refreshedKey is the cache key for when a collection was last refreshed
+func refreshedKey(id string) string {
+	return fmt.Sprintf("collection-%s-refreshed", id)
+}
+
+// lastRefreshed returns when a collection was last refreshed without failures, or the zero time
+func (p *Party) lastRefreshed(id string) time.Time {
+	if p.cache == nil {
+		return time.Time{}
+	}
+	if t := p.cache.GetNewerThan(refreshedKey(id), time.Time{}); t != nil {
+		return t.Created
+	}
+	return time.Time{}
+}
+
+// recordRefresh records when a collection was refreshed, for rules which match items changed since then
+func (p *Party) recordRefresh(id string, t time.Time) {
+	if p.cache == nil {
+		return
+	}
+	if err := p.cache.Set(refreshedKey(id), &provider.Thing{Created: t}); err != nil {
+		klog.Errorf("set %q failed: %v", refreshedKey(id), err)
+	}
+}
+
+// changedFilter returns a filter matching items updated since a timestamp. If there is no timestamp, nothing matches.
+func changedFilter(since time.Time, now time.Time) provider.Filter {
+	d := time.Duration(0)
+	if !since.IsZero() {
+		d = now.Sub(since)
+	}
+	return provider.Filter{Updated: fmt.Sprintf("-%s", d)}
+}
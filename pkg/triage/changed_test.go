@@ -0,0 +1,29 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/triage-party/pkg/provider"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangedFilter(t *testing.T) {
+	now := time.Now()
+	assert.Equal(t, provider.Filter{Updated: "-0s"}, changedFilter(time.Time{}, now))
+	assert.Equal(t, provider.Filter{Updated: "-1h30m0s"}, changedFilter(now.Add(-90*time.Minute), now))
+}
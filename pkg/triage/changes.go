@@ -0,0 +1,85 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"time"
+
+	"github.com/google/triage-party/pkg/hubbub"
+)
+
+// CollectionChanges is the delta between two results of the same collection
+type CollectionChanges struct {
+	Since time.Time
+	Until time.Time
+
+	// Added are items which were not previously part of the collection
+	Added []*hubbub.Conversation
+	// Removed are items which are no longer part of the collection
+	Removed []*hubbub.Conversation
+	// Stale are items which remain in the collection, but have not been updated since the older result
+	Stale []*hubbub.Conversation
+}
+
+// CompareCollectionResults returns what changed between an older and a newer collection result
+func CompareCollectionResults(older *CollectionResult, newer *CollectionResult) *CollectionChanges {
+	c := &CollectionChanges{
+		Since: older.Created,
+		Until: newer.Created,
+	}
+
+	before := resultItems(older)
+	after := resultItems(newer)
+
+	for _, co := range after.items {
+		prev, ok := before.seen[co.URL]
+		if !ok {
+			c.Added = append(c.Added, co)
+			continue
+		}
+
+		if !co.Updated.After(prev.Updated) && co.Updated.Before(older.Created) {
+			c.Stale = append(c.Stale, co)
+		}
+	}
+
+	for _, co := range before.items {
+		if _, ok := after.seen[co.URL]; !ok {
+			c.Removed = append(c.Removed, co)
+		}
+	}
+
+	return c
+}
+
+type itemSet struct {
+	items []*hubbub.Conversation
+	seen  map[string]*hubbub.Conversation
+}
+
+// resultItems returns the unique items within a collection result, in order of appearance
+func resultItems(r *CollectionResult) itemSet {
+	s := itemSet{seen: map[string]*hubbub.Conversation{}}
+	for _, rr := range r.RuleResults {
+		for _, co := range rr.Items {
+			if _, ok := s.seen[co.URL]; ok {
+				continue
+			}
+			s.seen[co.URL] = co
+			s.items = append(s.items, co)
+		}
+	}
+	return s
+}
@@ -0,0 +1,53 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/triage-party/pkg/hubbub"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareCollectionResults(t *testing.T) {
+	then := time.Now().Add(-7 * 24 * time.Hour)
+	now := time.Now()
+
+	kept := &hubbub.Conversation{URL: "kept", Updated: then.Add(-time.Hour)}
+	updated := &hubbub.Conversation{URL: "updated", Updated: now}
+	removed := &hubbub.Conversation{URL: "removed"}
+	added := &hubbub.Conversation{URL: "added", Updated: now}
+
+	older := &CollectionResult{
+		Created: then,
+		RuleResults: []*RuleResult{
+			{Items: []*hubbub.Conversation{kept, removed, {URL: "updated", Updated: then.Add(-time.Hour)}}},
+		},
+	}
+	newer := &CollectionResult{
+		Created: now,
+		RuleResults: []*RuleResult{
+			{Items: []*hubbub.Conversation{kept, added}},
+			{Items: []*hubbub.Conversation{updated, kept}},
+		},
+	}
+
+	c := CompareCollectionResults(older, newer)
+	assert.Equal(t, []*hubbub.Conversation{added}, c.Added)
+	assert.Equal(t, []*hubbub.Conversation{removed}, c.Removed)
+	assert.Equal(t, []*hubbub.Conversation{kept}, c.Stale)
+	assert.Equal(t, then, c.Since)
+}
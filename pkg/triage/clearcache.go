@@ -0,0 +1,83 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/triage-party/pkg/provider"
+
+	"k8s.io/klog/v2"
+)
+
+// ClearCache deletes cached data for a repository, or for everything if org is empty, so that it is fetched again.
+// It returns how many cache entries were deleted.
+func (p *Party) ClearCache(org string, project string) (int, error) {
+	prefix := ""
+	except := []string{}
+	if org != "" {
+		known := p.knownRepos()
+
+		// Cache keys use the case of the configured repository
+		for _, r := range known {
+			if strings.EqualFold(r.Organization, org) && strings.EqualFold(r.Project, project) {
+				org, project = r.Organization, r.Project
+				break
+			}
+		}
+		prefix = repoKeyPrefix(org, project)
+
+		// Keys for org/project-foo also start with the prefix for org/project
+		for _, r := range known {
+			if rp := repoKeyPrefix(r.Organization, r.Project); rp != prefix && strings.HasPrefix(rp, prefix) {
+				except = append(except, rp)
+			}
+		}
+	}
+
+	n, err := p.cache.DeletePrefix(prefix, except...)
+	if err != nil {
+		return n, fmt.Errorf("delete %q: %w", prefix, err)
+	}
+
+	if e := p.loadedEngine(); e != nil {
+		forgotten := e.Forget(org, project)
+		klog.Infof("cleared %d cache entries and %d conversations for %q", n, forgotten, prefix)
+	}
+	return n, nil
+}
+
+// repoKeyPrefix returns the prefix of every cache key for a repository
+func repoKeyPrefix(org string, project string) string {
+	return fmt.Sprintf("%s-%s-", org, project)
+}
+
+// knownRepos returns the configured repositories, and those of every item seen
+func (p *Party) knownRepos() []provider.Repo {
+	rs := []provider.Repo{}
+	for _, u := range p.loadedSettings().Repos {
+		r, err := parseRepo(u)
+		if err != nil || r.Project == ownerWildcard {
+			continue
+		}
+		rs = append(rs, r)
+	}
+
+	if e := p.loadedEngine(); e != nil {
+		rs = append(rs, e.SeenRepos()...)
+	}
+	return rs
+}
@@ -0,0 +1,65 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/triage-party/pkg/persist"
+	"github.com/google/triage-party/pkg/provider"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClearCache(t *testing.T) {
+	c, err := persist.NewMemory(persist.Config{})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	assert.Nil(t, c.Initialize())
+
+	p := &Party{cache: c}
+	config := `settings:
+  repos:
+    - https://github.com/org/project
+    - https://github.com/org/project-foo
+collections:
+  - id: daily
+    rules:
+      - open
+rules:
+  open:
+    filters:
+      - state: open
+`
+	if err := p.Load(strings.NewReader(config)); err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+
+	keys := []string{"org-project-1-timeline", "org-project-open-issues", "org-project-foo-1-timeline", "org-projects-1-timeline"}
+	for _, k := range keys {
+		assert.Nil(t, c.Set(k, &provider.Thing{}))
+	}
+
+	// The repository may be given in any case
+	n, err := p.ClearCache("Org", "Project")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+	assert.Nil(t, c.GetNewerThan("org-project-1-timeline", time.Time{}))
+	assert.Nil(t, c.GetNewerThan("org-project-open-issues", time.Time{}))
+	assert.NotNil(t, c.GetNewerThan("org-project-foo-1-timeline", time.Time{}))
+	assert.NotNil(t, c.GetNewerThan("org-projects-1-timeline", time.Time{}))
+}
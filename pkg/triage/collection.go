@@ -17,6 +17,8 @@ package triage
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/triage-party/pkg/provider"
@@ -31,10 +33,45 @@ type Collection struct {
 	Name         string   `yaml:"name"`
 	Description  string   `yaml:"description,omitempty"`
 	RuleIDs      []string `yaml:"rules"`
+	Repos        []string `yaml:"repos,omitempty"`
+	Type         string   `yaml:"type,omitempty"`
+	SLA          string   `yaml:"sla,omitempty"`
+	SLAAge       string   `yaml:"sla_age,omitempty"`
 	Dedup        bool     `yaml:"dedup,omitempty"`
 	Hidden       bool     `yaml:"hidden,omitempty"`
 	UsedForStats bool     `yaml:"used_for_statistics,omitempty"`
 
+	// Enabled may be set to false to skip the collection without removing it (default: true)
+	Enabled *bool `yaml:"enabled,omitempty"`
+
+	// CollapseBots shows pull requests opened by each bot within a rule as a single summary row
+	CollapseBots bool `yaml:"collapse_bots,omitempty"`
+
+	// Limit is how many items each rule shows, overriding the limit setting (0 for unlimited)
+	Limit *int `yaml:"limit,omitempty"`
+
+	// Access lists the users and teams, such as org/team, who may view the collection (default: everyone)
+	Access []string `yaml:"access,omitempty"`
+
+	// Priority orders refreshes: higher priority collections are refreshed first (default: 0)
+	Priority int `yaml:"priority,omitempty"`
+
+	// Refresh bounds, overriding --min-refresh and --max-refresh for this collection, such as 5m or 1d
+	MinRefresh string `yaml:"min_refresh,omitempty"`
+	MaxRefresh string `yaml:"max_refresh,omitempty"`
+
+	// CountFilter matches parked items, which are listed but not counted as actionable
+	CountFilter []provider.Filter `yaml:"count_filter,omitempty"`
+
+	// Pinned are issue URLs or numbers shown first within each rule, in the order listed
+	Pinned []string `yaml:"pinned,omitempty"`
+	pins   []pin
+
+	// Age bounds, applied to every rule in the collection
+	MinAge     string `yaml:"min_age,omitempty"`
+	MaxAge     string `yaml:"max_age,omitempty"`
+	ageFilters []provider.Filter
+
 	// Kanban option
 	Display  string `yaml:"display"`
 	Overflow int    `yaml:"overflow"`
@@ -52,9 +89,23 @@ type CollectionResult struct {
 
 	RuleResults []*RuleResult
 
+	// MatchedBy lists the rules which matched each item, by URL, in collection order
+	MatchedBy map[string][]Rule
+
+	// SLAStatus is the SLA status of each item, by URL, if the collection has an SLA
+	SLAStatus map[string]string
+
+	// Pinned ranks pinned items by URL, counting down from the number of pins for the first
+	Pinned map[string]int
+
 	Total             int
 	TotalPullRequests int
 	TotalIssues       int
+	Hidden            int
+	Excluded          int
+
+	// Parked is how many unique items match the collection count_filter
+	Parked int
 
 	AvgAge             time.Duration
 	AvgCurrentHold     time.Duration
@@ -63,18 +114,16 @@ type CollectionResult struct {
 	TotalAgeDays             float64
 	TotalCurrentHoldDays     float64
 	TotalAccumulatedHoldDays float64
-}
 
-// ExecuteCollection executes a collection.
-func (p *Party) ExecuteCollection(ctx context.Context, s Collection, newerThan time.Time) (*CollectionResult, error) {
-	klog.V(1).Infof("executing collection %q: %s (newer than %s)", s.ID, s.RuleIDs, newerThan)
-	start := time.Now()
+	SLA *SLAReport
+}
 
-	os := []*RuleResult{}
-	seen := map[string]*Rule{}
+// collectionRules returns the rules of a collection, as they apply to that collection
+func (p *Party) collectionRules(s *Collection, start time.Time) ([]Rule, error) {
 	seenRule := map[string]bool{}
-	oldest := time.Now()
+	lastRefresh := p.lastRefreshed(s.ID)
 
+	rules := []Rule{}
 	for _, tid := range s.RuleIDs {
 		if seenRule[tid] {
 			klog.Errorf("collection %q has a duplicate rule: %q - ignoring", s.ID, tid)
@@ -87,17 +136,65 @@ func (p *Party) ExecuteCollection(ctx context.Context, s Collection, newerThan t
 		if err != nil {
 			return nil, err
 		}
+		t.Repos = p.collectionRepos(*s, t)
+		if len(s.ageFilters) > 0 {
+			t.Filters = append(append([]provider.Filter{}, t.Filters...), s.ageFilters...)
+		}
+		if t.ChangedSinceLastRefresh {
+			t.Filters = append(append([]provider.Filter{}, t.Filters...), changedFilter(lastRefresh, start))
+		}
+		if t.Type == "" {
+			t.Type = s.Type
+		}
+		t.collection = s
+		rules = append(rules, t)
+	}
+	return rules, nil
+}
+
+// ExecuteCollection executes a collection.
+func (p *Party) ExecuteCollection(ctx context.Context, s Collection, newerThan time.Time) (*CollectionResult, error) {
+	klog.V(1).Infof("executing collection %q: %s (newer than %s)", s.ID, s.RuleIDs, newerThan)
+	start := time.Now()
 
-		hidden := s.Hidden && s.UsedForStats
+	os := []*RuleResult{}
+	seen := map[string]*Rule{}
+	oldest := time.Now()
+	failed := RuleErrors{}
+	hidden := s.Hidden && s.UsedForStats
+
+	rules, err := p.collectionRules(&s, start)
+	if err != nil {
+		return nil, err
+	}
 
+	// Rules are fetched in fetch_order, but deduplicated and listed in config order
+	results := make([]*RuleResult, len(rules))
+	errs := make([]error, len(rules))
+	for _, i := range p.fetchOrder(rules) {
 		sp := provider.SearchParams{
 			NewerThan: newerThan,
 			Hidden:    hidden,
 		}
-		ro, err := p.ExecuteRule(ctx, sp, t, seen)
+		results[i], errs[i] = p.ExecuteRule(ctx, sp, rules[i], nil)
+	}
+
+	for i, t := range rules {
+		ro, err := results[i], errs[i]
 		if err != nil {
-			return nil, fmt.Errorf("rule %q: %w", t.Name, err)
+			klog.Errorf("collection %q rule %q failed: %v", s.ID, t.ID, err)
+			failed[t.ID] = err
+			os = append(os, &RuleResult{Rule: t, Stale: true, Error: err.Error(), OldestInput: newerThan, Dedup: dedup(s, t)})
+			continue
 		}
+		ro.Dedup = dedup(s, t)
+		if t.Sample > 0 {
+			ro = sampleResult(ro, seen, sampleSeed(p.now()))
+		}
+		ro.Items = pinFirst(ro.Items, s.pins)
+		ro.Items, ro.Truncated = limitItems(ro.Items, p.itemLimit(s), p.HeatEnabled(), s.pins)
+		// Only items which are shown count as seen by later rules
+		markDuplicates(ro, seen)
 
 		if ro.OldestInput.Before(oldest) {
 			oldest = ro.OldestInput
@@ -107,31 +204,138 @@ func (p *Party) ExecuteCollection(ctx context.Context, s Collection, newerThan t
 	}
 
 	r := SummarizeCollectionResult(&s, os)
+	r.Pinned = pinnedRanks(os, s.pins)
 	r.NewerThan = newerThan
 	r.OldestInput = oldest
 	r.Created = time.Now()
 
 	klog.V(1).Infof("collection %q took %s, results as of %s", s.ID, time.Since(start), r.OldestInput)
+	if len(failed) > 0 {
+		return r, failed
+	}
+
+	p.recordRefresh(s.ID, start)
 	return r, nil
 }
 
+// dedup returns whether a rule omits items shown by earlier rules, preferring the rule setting over the collection
+func dedup(s Collection, t Rule) bool {
+	if t.Dedup != nil {
+		return *t.Dedup
+	}
+	return s.Dedup
+}
+
+// RuleErrors is returned alongside a partial result when some rules within a collection fail
+type RuleErrors map[string]error
+
+func (e RuleErrors) Error() string {
+	ids := []string{}
+	for id := range e {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	msgs := []string{}
+	for _, id := range ids {
+		msgs = append(msgs, fmt.Sprintf("rule %q: %v", id, e[id]))
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// itemType normalizes an item type setting: issue, pull_request (or pr), or any
+func itemType(s string) (string, error) {
+	switch strings.ToLower(s) {
+	case "", "any":
+		return "", nil
+	case hubbub.Issue:
+		return hubbub.Issue, nil
+	case hubbub.PullRequest, "pr":
+		return hubbub.PullRequest, nil
+	default:
+		return "", fmt.Errorf("unknown type %q, expected issue, pull_request, or any", s)
+	}
+}
+
+// collectionRepos returns the repositories a rule should be evaluated against within a collection.
+// Rules which define their own repos are scoped to the subset of the collection's repos they list.
+func (p *Party) collectionRepos(s Collection, t Rule) []string {
+	if len(p.overrideRepos()) > 0 || len(s.Repos) == 0 {
+		return t.Repos
+	}
+
+	scoped := p.ruleDefs()[t.ID].Repos
+	if len(scoped) == 0 {
+		return s.Repos
+	}
+
+	inCollection := map[string]bool{}
+	for _, r := range s.Repos {
+		inCollection[r] = true
+	}
+
+	repos := []string{}
+	for _, r := range scoped {
+		if !inCollection[r] && !inCollection[r[:strings.LastIndex(r, "/")+1]+ownerWildcard] {
+			klog.Warningf("rule %q lists %s, which is not part of collection %q - ignoring", t.ID, r, s.ID)
+			continue
+		}
+		repos = append(repos, r)
+	}
+	return repos
+}
+
+// loadAgeFilters validates collection age bounds, and converts them to created filters
+func (s *Collection) loadAgeFilters() error {
+	s.ageFilters = nil
+	for _, b := range []struct {
+		key  string
+		val  string
+		sign string
+	}{{"min_age", s.MinAge, "+"}, {"max_age", s.MaxAge, "-"}} {
+		if b.val == "" {
+			continue
+		}
+		created := b.sign + b.val
+		if d, _, _ := hubbub.ParseDuration(created); d <= 0 {
+			return fmt.Errorf("%s: unable to parse %q as a duration", b.key, b.val)
+		}
+		s.ageFilters = append(s.ageFilters, provider.Filter{Created: created})
+	}
+	return nil
+}
+
 // SummarizeCollectionResult adds together statistics about collection results {
 func SummarizeCollectionResult(s *Collection, os []*RuleResult) *CollectionResult {
 	klog.V(1).Infof("Summarizing collection result with %d rules...", len(os))
 
+	now := time.Now()
 	r := &CollectionResult{
 		Collection: s,
+		SLA:        summarizeSLA(s, os, now),
+		MatchedBy:  map[string][]Rule{},
+		SLAStatus:  map[string]string{},
+		Parked:     countParked(s, os, now),
 	}
 
 	for _, oc := range os {
-		r.Total += len(oc.Items)
+		for _, c := range oc.Items {
+			r.MatchedBy[c.URL] = append(r.MatchedBy[c.URL], oc.Rule)
+			if st := slaStatus(s, c, now); st != "" {
+				r.SLAStatus[c.URL] = st
+			}
+		}
+
+		r.Total += len(oc.Items) + oc.Truncated
 		if oc.Rule.Type == hubbub.PullRequest {
-			r.TotalPullRequests += len(oc.Items)
+			r.TotalPullRequests += len(oc.Items) + oc.Truncated
 		} else {
-			r.TotalIssues += len(oc.Items)
+			r.TotalIssues += len(oc.Items) + oc.Truncated
 		}
 
 		r.RuleResults = append(r.RuleResults, oc)
+		r.Hidden += oc.Hidden
+		r.Excluded += oc.Excluded
 
 		r.TotalAgeDays += oc.TotalAgeDays
 		r.TotalCurrentHoldDays += oc.TotalCurrentHoldDays
@@ -155,12 +359,15 @@ func avgDayDuration(total float64, count int) time.Duration {
 
 // ListCollections a fully resolved collections
 func (p *Party) ListCollections() ([]Collection, error) {
+	p.reposMu.RLock()
+	defer p.reposMu.RUnlock()
 	return p.collections, nil
 }
 
 // Return a fully resolved collection
 func (p *Party) LookupCollection(id string) (Collection, error) {
-	for _, s := range p.collections {
+	sts, _ := p.ListCollections()
+	for _, s := range sts {
 		if s.ID == id {
 			return s, nil
 		}
@@ -0,0 +1,173 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/triage-party/pkg/hubbub"
+	"github.com/google/triage-party/pkg/provider"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadAgeFilters(t *testing.T) {
+	s := &Collection{MinAge: "90d", MaxAge: "365d"}
+	assert.Nil(t, s.loadAgeFilters())
+	assert.Equal(t, []provider.Filter{{Created: "+90d"}, {Created: "-365d"}}, s.ageFilters)
+
+	s = &Collection{MaxAge: "soon"}
+	assert.NotNil(t, s.loadAgeFilters())
+}
+
+func TestValidateRefreshBounds(t *testing.T) {
+	assert.Nil(t, validateRefreshBounds(&Collection{MinRefresh: "2m", MaxRefresh: "10m"}))
+	assert.NotNil(t, validateRefreshBounds(&Collection{MinRefresh: "often"}))
+	assert.NotNil(t, validateRefreshBounds(&Collection{MinRefresh: "1d", MaxRefresh: "1h"}))
+}
+
+func TestPinFirst(t *testing.T) {
+	s := &Collection{Pinned: []string{"https://github.com/org/repo/issues/3", "7", "other/repo#1"}}
+	assert.Nil(t, s.loadPins())
+
+	one := &hubbub.Conversation{URL: "1", ID: 1, Organization: "org", Project: "repo"}
+	three := &hubbub.Conversation{URL: "3", ID: 3, Organization: "Org", Project: "repo"}
+	seven := &hubbub.Conversation{URL: "7", ID: 7, Organization: "org", Project: "repo"}
+	nine := &hubbub.Conversation{URL: "9", ID: 9, Organization: "org", Project: "repo"}
+
+	cs := pinFirst([]*hubbub.Conversation{nine, seven, one, three}, s.pins)
+	assert.Equal(t, []*hubbub.Conversation{three, seven, nine, one}, cs)
+	assert.Equal(t, map[string]int{"3": 3, "7": 2}, pinnedRanks([]*RuleResult{{Items: cs}}, s.pins))
+
+	s = &Collection{Pinned: []string{"tracking issue"}}
+	assert.NotNil(t, s.loadPins())
+}
+
+func TestExcludeListed(t *testing.T) {
+	ex, err := loadExcludes([]string{"https://github.com/org/repo/issues/1/", "org/repo#2"})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	cs := []*hubbub.Conversation{
+		{ID: 1, Organization: "org", Project: "repo", URL: "https://github.com/org/repo/issues/1"},
+		{ID: 2, Organization: "org", Project: "repo", URL: "https://github.com/org/repo/issues/2"},
+		{ID: 3, Organization: "org", Project: "repo", URL: "https://github.com/org/repo/issues/3"},
+	}
+	kept, n := excludeListed(cs, ex)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, cs[2:], kept)
+
+	_, err = loadExcludes([]string{"#3"})
+	assert.NotNil(t, err)
+}
+
+func TestRemoveDisabled(t *testing.T) {
+	off := false
+	rs := map[string]Rule{"a": {}, "b": {Enabled: &off}}
+	cs := []Collection{
+		{ID: "mixed", RuleIDs: []string{"a", "b"}},
+		{ID: "only-disabled", RuleIDs: []string{"b"}},
+		{ID: "disabled", RuleIDs: []string{"a"}, Enabled: &off},
+	}
+
+	gotCs, gotRs := removeDisabled(cs, rs)
+	assert.Equal(t, []Collection{{ID: "mixed", RuleIDs: []string{"a"}}}, gotCs)
+	assert.Equal(t, map[string]Rule{"a": {}}, gotRs)
+}
+
+func TestDedup(t *testing.T) {
+	off := false
+	on := true
+	assert.True(t, dedup(Collection{Dedup: true}, Rule{}))
+	assert.False(t, dedup(Collection{Dedup: true}, Rule{Dedup: &off}))
+	assert.True(t, dedup(Collection{}, Rule{Dedup: &on}))
+	assert.False(t, dedup(Collection{}, Rule{}))
+}
+
+func TestCountParked(t *testing.T) {
+	s := &Collection{CountFilter: []provider.Filter{{RawLabel: "snoozed"}}}
+	if err := s.loadCountFilter(); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	snoozed := &hubbub.Conversation{URL: "a", Labels: []*provider.Label{{Name: strPtr("snoozed")}}}
+	open := &hubbub.Conversation{URL: "b"}
+	os := []*RuleResult{{Items: []*hubbub.Conversation{snoozed, open}}, {Items: []*hubbub.Conversation{snoozed}}}
+
+	assert.Equal(t, 1, countParked(s, os, time.Now()))
+	assert.Equal(t, 1, SummarizeCollectionResult(s, os).Parked)
+	assert.Equal(t, 0, countParked(&Collection{}, os, time.Now()))
+}
+
+func TestLimitItems(t *testing.T) {
+	now := time.Now()
+	old := &hubbub.Conversation{URL: "old", Created: now.Add(-48 * time.Hour), Heat: 9}
+	mid := &hubbub.Conversation{URL: "mid", Created: now.Add(-24 * time.Hour), Heat: 1}
+	recent := &hubbub.Conversation{URL: "recent", Created: now, Heat: 5}
+	cs := []*hubbub.Conversation{old, mid, recent}
+
+	got, truncated := limitItems(cs, 2, false, nil)
+	assert.Equal(t, []*hubbub.Conversation{recent, mid}, got)
+	assert.Equal(t, 1, truncated)
+
+	got, _ = limitItems(cs, 2, true, nil)
+	assert.Equal(t, []*hubbub.Conversation{old, recent}, got)
+
+	got, truncated = limitItems(cs, 0, false, nil)
+	assert.Equal(t, cs, got)
+	assert.Equal(t, 0, truncated)
+
+	// The oldest item is past the limit, but pinned
+	s := &Collection{Pinned: []string{"#1"}}
+	assert.Nil(t, s.loadPins())
+	old.ID = 1
+	got, truncated = limitItems(pinFirst(cs, s.pins), 2, false, s.pins)
+	assert.Equal(t, []*hubbub.Conversation{old, recent}, got)
+	assert.Equal(t, 1, truncated)
+
+	zero, twenty := 0, 20
+	p := &Party{settings: Settings{Limit: 20}}
+	assert.Equal(t, 20, p.itemLimit(Collection{}))
+	assert.Equal(t, 0, p.itemLimit(Collection{Limit: &zero}))
+	assert.Equal(t, 20, p.itemLimit(Collection{Limit: &twenty}))
+}
+
+func TestEmptyRules(t *testing.T) {
+	item := []*hubbub.Conversation{{URL: "a"}}
+	crs := []*CollectionResult{
+		{RuleResults: []*RuleResult{{Rule: Rule{ID: "used"}}, {Rule: Rule{ID: "dead"}}, {Rule: Rule{ID: "failing"}, Stale: true}}},
+		{RuleResults: []*RuleResult{{Rule: Rule{ID: "used"}, Items: item}, {Rule: Rule{ID: "capped"}, Truncated: 3}}},
+		nil,
+	}
+	assert.Equal(t, []string{"dead"}, EmptyRules(crs))
+}
+
+func TestReplaceItem(t *testing.T) {
+	open := &hubbub.Conversation{URL: "1", State: "open"}
+	other := &hubbub.Conversation{URL: "2", State: "open"}
+	rr := &RuleResult{Rule: Rule{Filters: []provider.Filter{{State: "open"}}}, Items: []*hubbub.Conversation{open, other}}
+	r := SummarizeCollectionResult(&Collection{}, []*RuleResult{rr})
+
+	labeled := &hubbub.Conversation{URL: "1", State: "open", Title: "labeled"}
+	got := ReplaceItem(r, labeled)
+	assert.Equal(t, []*hubbub.Conversation{labeled, other}, got.RuleResults[0].Items)
+	assert.Equal(t, []*hubbub.Conversation{open, other}, r.RuleResults[0].Items)
+
+	closed := &hubbub.Conversation{URL: "1", State: "closed"}
+	got = ReplaceItem(r, closed)
+	assert.Equal(t, []*hubbub.Conversation{other}, got.RuleResults[0].Items)
+	assert.Equal(t, 1, got.Total)
+}
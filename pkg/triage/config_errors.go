@@ -0,0 +1,173 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// yamlLine matches the line number embedded within YAML decode errors
+var yamlLine = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// ConfigError is a configuration problem, located within the configuration file where possible
+type ConfigError struct {
+	File   string
+	Line   int
+	Column int
+	// Key is the path to the offending setting, such as rules.my-rule
+	Key string
+	Err error
+}
+
+func (e *ConfigError) Error() string {
+	var sb strings.Builder
+	if e.File != "" {
+		sb.WriteString(e.File)
+		if e.Line > 0 {
+			sb.WriteString(fmt.Sprintf(":%d", e.Line))
+			if e.Column > 0 {
+				sb.WriteString(fmt.Sprintf(":%d", e.Column))
+			}
+		}
+		sb.WriteString(": ")
+	} else if e.Line > 0 {
+		sb.WriteString(fmt.Sprintf("line %d", e.Line))
+		if e.Column > 0 {
+			sb.WriteString(fmt.Sprintf(", column %d", e.Column))
+		}
+		sb.WriteString(": ")
+	}
+
+	if e.Key != "" {
+		sb.WriteString(e.Key + ": ")
+	}
+	sb.WriteString(e.Err.Error())
+	return sb.String()
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// ConfigErrors is a list of configuration problems, reported together
+type ConfigErrors []*ConfigError
+
+func (es ConfigErrors) Error() string {
+	if len(es) == 1 {
+		return es[0].Error()
+	}
+
+	msgs := []string{fmt.Sprintf("%d configuration errors:", len(es))}
+	for _, e := range es {
+		msgs = append(msgs, "  "+e.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// add appends an error, flattening ConfigErrors, and wrapping other errors with a key
+func (es ConfigErrors) add(key string, err error) ConfigErrors {
+	if err == nil {
+		return es
+	}
+
+	var ces ConfigErrors
+	if errors.As(err, &ces) {
+		return append(es, ces...)
+	}
+
+	var ce *ConfigError
+	if errors.As(err, &ce) {
+		return append(es, ce)
+	}
+	return append(es, &ConfigError{Key: key, Err: err})
+}
+
+// locate fills in the file, line, and column of each error, by searching the configuration for its key.
+// Errors already located within an included file are left as they are.
+func (es ConfigErrors) locate(file string, bs []byte) {
+	lines := strings.Split(string(bs), "\n")
+	for _, e := range es {
+		if e.File != "" {
+			continue
+		}
+		e.File = file
+		if e.Line == 0 && e.Key != "" {
+			e.Line, e.Column = findKey(lines, e.Key)
+		}
+	}
+}
+
+// findKey returns the line and column of a key path, such as rules.my-rule, settings.exclude, or
+// collections.daily (matched by id). Returns zeroes if the key cannot be found.
+func findKey(lines []string, key string) (int, int) {
+	parts := strings.SplitN(key, ".", 2)
+
+	section := -1
+	for i, l := range lines {
+		if strings.HasPrefix(l, parts[0]+":") {
+			section = i
+			break
+		}
+	}
+
+	if section < 0 {
+		return 0, 0
+	}
+
+	if len(parts) == 1 {
+		return section + 1, 1
+	}
+
+	for i := section + 1; i < len(lines); i++ {
+		l := lines[i]
+		// Another top-level section
+		if l != "" && !strings.HasPrefix(l, " ") && !strings.HasPrefix(l, "\t") && !strings.HasPrefix(l, "-") && !strings.HasPrefix(l, "#") {
+			break
+		}
+
+		t := strings.TrimLeft(strings.TrimPrefix(strings.TrimLeft(l, " \t"), "- "), " ")
+		val := strings.Trim(strings.TrimSpace(strings.TrimPrefix(t, "id:")), `"'`)
+		if strings.HasPrefix(t, parts[1]+":") || (strings.HasPrefix(t, "id:") && val == parts[1]) {
+			return i + 1, len(l) - len(t) + 1
+		}
+	}
+	return section + 1, 1
+}
+
+// decodeErrors converts a YAML decode error into ConfigErrors, one per problem
+func decodeErrors(err error) ConfigErrors {
+	msgs := []string{err.Error()}
+	var te *yaml.TypeError
+	if errors.As(err, &te) {
+		msgs = te.Errors
+	}
+
+	es := ConfigErrors{}
+	for _, m := range msgs {
+		e := &ConfigError{Err: errors.New(m)}
+		if ms := yamlLine.FindStringSubmatch(m); ms != nil {
+			e.Line, _ = strconv.Atoi(ms[1])
+			e.Err = errors.New(ms[2])
+		}
+		es = append(es, e)
+	}
+	return es
+}
@@ -0,0 +1,122 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadErrors(t *testing.T) {
+	config := `settings:
+  name: test
+collections:
+  - id: daily
+    rules:
+      - bad-label
+      - missing
+    sla: soon
+rules:
+  bad-label:
+    filters:
+      - label: "("
+  bad-state:
+    filters:
+      - milestone-state: pending
+`
+	p := &Party{}
+	err := p.Load(strings.NewReader(config))
+
+	var errs ConfigErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Load() = %v, want ConfigErrors", err)
+	}
+
+	got := []string{}
+	for _, e := range errs {
+		got = append(got, e.Error())
+	}
+	assert.Equal(t, []string{
+		"line 10, column 3: rules.bad-label: label: error parsing regexp: missing closing ): `(`",
+		"line 13, column 3: rules.bad-state: milestone-state: unknown value \"pending\", expected open or closed",
+	}, got)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	p := &Party{}
+	err := p.Load(strings.NewReader("collections:\n  - id: [1, 2]\n"))
+
+	var errs ConfigErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Load() = %v, want ConfigErrors", err)
+	}
+	assert.Len(t, errs, 1)
+	assert.Equal(t, 2, errs[0].Line)
+}
+
+func TestValidationErrors(t *testing.T) {
+	config := `collections:
+  - id: daily
+    sla: soon
+    rules:
+      - missing
+rules:
+  ok:
+    filters:
+      - label: bug
+`
+	p := &Party{}
+	err := p.Load(strings.NewReader(config))
+
+	var errs ConfigErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Load() = %v, want ConfigErrors", err)
+	}
+	assert.Len(t, errs, 2)
+	for _, e := range errs {
+		assert.Equal(t, "collections.daily", e.Key)
+		assert.Equal(t, 2, e.Line)
+	}
+}
+
+func TestGitHubHostErrors(t *testing.T) {
+	config := `
+settings:
+  github_hosts:
+    - host: ghe.example.com
+      api_url: ftp://ghe.example.com/api/v3/
+      reuse_github_token: true
+    - api_url: https://ghe.example.com/api/v3/
+    - host: git.example.org
+      api_url: https://git.example.org/api/v3/
+collections:
+  - id: daily
+    rules:
+      - open
+rules:
+  open:
+    filters:
+      - state: open
+`
+	p := &Party{}
+	err := p.Load(strings.NewReader(config))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "settings.github_hosts: ghe.example.com: api url")
+	assert.Contains(t, err.Error(), "host is required")
+	assert.Contains(t, err.Error(), "git.example.org: token_file or token_env is required")
+}
@@ -0,0 +1,57 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"time"
+
+	"github.com/google/triage-party/pkg/hubbub"
+)
+
+// loadCountFilter validates and precaches the count_filter of a collection
+func (s *Collection) loadCountFilter() error {
+	if len(s.CountFilter) == 0 {
+		return nil
+	}
+
+	r, err := processRule(Rule{ID: s.ID, Filters: s.CountFilter})
+	if err != nil {
+		return err
+	}
+	s.CountFilter = r.Filters
+	return nil
+}
+
+// countParked returns the number of unique items which match the count_filter of a collection
+func countParked(s *Collection, os []*RuleResult, now time.Time) int {
+	if s == nil || len(s.CountFilter) == 0 {
+		return 0
+	}
+
+	parked := 0
+	seen := map[string]bool{}
+	for _, oc := range os {
+		for _, c := range oc.Items {
+			if seen[c.URL] {
+				continue
+			}
+			seen[c.URL] = true
+			if hubbub.MatchConversation(c, s.CountFilter, now) {
+				parked++
+			}
+		}
+	}
+	return parked
+}
@@ -0,0 +1,86 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// DuplicateRulesError rejects configurations which define a rule ID more than once
+	DuplicateRulesError = "error"
+	// DuplicateRulesLastWins uses the last definition of a rule ID
+	DuplicateRulesLastWins = "last-wins"
+	// DuplicateRulesMergeFilters uses the first definition of a rule ID, with the filters of every definition
+	DuplicateRulesMergeFilters = "merge-filters"
+)
+
+// loadDuplicateRules validates the duplicate_rules setting, returning the mode to use
+func loadDuplicateRules(mode string) (string, error) {
+	if mode == "" {
+		return DuplicateRulesError, nil
+	}
+	if mode != DuplicateRulesError && mode != DuplicateRulesLastWins && mode != DuplicateRulesMergeFilters {
+		return "", fmt.Errorf("unknown value %q, expected %s, %s, or %s", mode, DuplicateRulesError, DuplicateRulesLastWins, DuplicateRulesMergeFilters)
+	}
+	return mode, nil
+}
+
+// loadRules decodes the rules of a configuration into rules, which may hold those of other files,
+// resolving rule IDs which are defined more than once by mode
+func loadRules(bs []byte, mode string, rules map[string]Rule) error {
+	// A map would silently keep the last definition, so the order and count of each ID is kept
+	raw := struct {
+		Rules yaml.MapSlice `yaml:"rules"`
+	}{}
+	if err := yaml.Unmarshal(bs, &raw); err != nil {
+		return decodeErrors(err)
+	}
+
+	var errs ConfigErrors
+	for _, item := range raw.Rules {
+		id := fmt.Sprintf("%v", item.Key)
+		key := "rules." + id
+
+		b, err := yaml.Marshal(item.Value)
+		if err != nil {
+			errs = errs.add(key, err)
+			continue
+		}
+		var r Rule
+		if err := yaml.Unmarshal(b, &r); err != nil {
+			errs = errs.add(key, err)
+			continue
+		}
+
+		prev, seen := rules[id]
+		switch {
+		case !seen || mode == DuplicateRulesLastWins:
+			rules[id] = r
+		case mode == DuplicateRulesMergeFilters:
+			prev.Filters = append(prev.Filters, r.Filters...)
+			rules[id] = prev
+		default:
+			errs = errs.add(key, fmt.Errorf("defined more than once (see settings.duplicate_rules)"))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
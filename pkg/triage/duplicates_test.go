@@ -0,0 +1,156 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadRulesDuplicates(t *testing.T) {
+	config := []byte(`rules:
+  untriaged:
+    name: Untriaged
+    filters:
+      - label: "!triage/.*"
+  stale:
+    filters:
+      - updated: +90d
+  untriaged:
+    name: Untriaged issues
+    filters:
+      - type: issue
+`)
+
+	assert.EqualError(t, loadRules(config, DuplicateRulesError, map[string]Rule{}), "rules.untriaged: defined more than once (see settings.duplicate_rules)")
+
+	rules := map[string]Rule{}
+	assert.NoError(t, loadRules(config, DuplicateRulesLastWins, rules))
+	assert.Equal(t, "Untriaged issues", rules["untriaged"].Name)
+	assert.Len(t, rules["untriaged"].Filters, 1)
+	assert.Len(t, rules, 2)
+
+	rules = map[string]Rule{}
+	assert.NoError(t, loadRules(config, DuplicateRulesMergeFilters, rules))
+	assert.Equal(t, "Untriaged", rules["untriaged"].Name)
+	assert.Len(t, rules["untriaged"].Filters, 2)
+
+	mode, err := loadDuplicateRules("")
+	assert.NoError(t, err)
+	assert.Equal(t, DuplicateRulesError, mode)
+	_, err = loadDuplicateRules("first-wins")
+	assert.Error(t, err)
+}
+
+func TestIncludeDuplicates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "include")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		// team/a.yaml includes team/b.yaml, relative to its own directory
+		"team/a.yaml": `include: [b.yaml]
+collections:
+  - id: team-a
+    rules: [untriaged]
+rules:
+  untriaged:
+    name: Team A
+    filters:
+      - label: "team/a"
+`,
+		"team/b.yaml": `collections:
+  - id: team-b
+    rules: [untriaged]
+rules:
+  untriaged:
+    name: Team B
+    filters:
+      - label: "team/b"
+`,
+		"loop.yaml": `include: [loop.yaml]
+`,
+		"settings.yaml": `settings:
+  name: included
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+
+	main := func(mode string) string {
+		return `settings:
+  duplicate_rules: ` + mode + `
+include: [team/a.yaml]
+collections:
+  - id: main
+    rules: [untriaged]
+rules:
+  untriaged:
+    name: Main
+    filters:
+      - state: open
+`
+	}
+
+	load := func(config string) (*Party, error) {
+		p := &Party{configDir: dir}
+		return p, p.load([]byte(config))
+	}
+
+	// Nested includes are loaded before the files including them
+	_, err = load(main(DuplicateRulesError))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), filepath.Join(dir, "team/a.yaml")+":6:3: rules.untriaged: defined more than once")
+
+	p, err := load(main(DuplicateRulesLastWins))
+	assert.NoError(t, err)
+	assert.Equal(t, "Main", p.rules["untriaged"].Name)
+	assert.Len(t, p.rules["untriaged"].Filters, 1)
+	ids := []string{}
+	for _, c := range p.collections {
+		ids = append(ids, c.ID)
+	}
+	assert.Equal(t, []string{"main", "team-b", "team-a"}, ids)
+
+	p, err = load(main(DuplicateRulesMergeFilters))
+	assert.NoError(t, err)
+	assert.Equal(t, "Team B", p.rules["untriaged"].Name)
+	assert.Len(t, p.rules["untriaged"].Filters, 3)
+
+	_, err = load(strings.Replace(main(DuplicateRulesLastWins), "team/a.yaml", "loop.yaml", 1))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "is included by itself")
+
+	_, err = load(strings.Replace(main(DuplicateRulesLastWins), "team/a.yaml", "settings.yaml", 1))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "settings: may only be set in the main configuration")
+
+	_, err = load(strings.Replace(main(DuplicateRulesLastWins), "team/a.yaml", "missing.yaml", 1))
+	assert.Error(t, err)
+}
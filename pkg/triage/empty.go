@@ -0,0 +1,129 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// EmptyRules returns the IDs of rules which matched no items within any of the collection results.
+// Rules which failed to refresh are not considered empty.
+func EmptyRules(crs []*CollectionResult) []string {
+	matched := map[string]bool{}
+	empty := map[string]bool{}
+	for _, cr := range crs {
+		if cr == nil {
+			continue
+		}
+		for _, rr := range cr.RuleResults {
+			if rr.Stale {
+				matched[rr.Rule.ID] = true
+				continue
+			}
+			if len(rr.Items)+rr.Truncated > 0 {
+				matched[rr.Rule.ID] = true
+				continue
+			}
+			empty[rr.Rule.ID] = true
+		}
+	}
+
+	ids := []string{}
+	for id := range empty {
+		if !matched[id] {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// UnmatchedLabels returns the label filters of a rule which match no label within any of its repositories,
+// meaning that the rule can never match. Negated label filters and search rules are not checked.
+func (p *Party) UnmatchedLabels(ctx context.Context, t Rule) ([]string, error) {
+	if t.Search != "" {
+		return nil, nil
+	}
+
+	patterns := []int{}
+	for i, f := range t.Filters {
+		if f.LabelRegex() != nil && !f.LabelNegate() {
+			patterns = append(patterns, i)
+		}
+	}
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+
+	repos, _, err := p.expandRepos(ctx, t.Repos)
+	if err != nil {
+		return nil, err
+	}
+
+	known := map[string]bool{}
+	for _, r := range repos {
+		names, err := p.listLabels(ctx, r)
+		if err != nil {
+			return nil, fmt.Errorf("labels for %s: %w", r, err)
+		}
+		for _, n := range names {
+			known[n] = true
+		}
+	}
+
+	// Aliases may refer to labels which have been renamed
+	for k, vs := range p.loadedSettings().LabelAliases {
+		known[k] = true
+		for _, v := range vs {
+			known[v] = true
+		}
+	}
+
+	missing := []string{}
+	for _, i := range patterns {
+		f := t.Filters[i]
+		found := false
+		for n := range known {
+			if f.LabelRegex().MatchString(n) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, f.RawLabel)
+		}
+	}
+	return missing, nil
+}
+
+// DescribeEmptyRule explains why a rule which matched no items did so, where it can be determined
+func (p *Party) DescribeEmptyRule(ctx context.Context, id string) string {
+	t, err := p.LookupRule(id)
+	if err != nil {
+		return fmt.Sprintf("rule %q: %v", id, err)
+	}
+
+	missing, err := p.UnmatchedLabels(ctx, t)
+	if err != nil {
+		return fmt.Sprintf("rule %q matched no items (unable to check labels: %v)", id, err)
+	}
+	if len(missing) > 0 {
+		return fmt.Sprintf("rule %q can never match: no repository has a label matching %s", id, strings.Join(missing, ", "))
+	}
+	return fmt.Sprintf("rule %q matched no items", id)
+}
@@ -0,0 +1,60 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"k8s.io/klog/v2"
+)
+
+// enabled returns whether an optional enabled setting is true, defaulting to true when unset
+func enabled(b *bool) bool {
+	return b == nil || *b
+}
+
+// removeDisabled drops disabled collections and rules, and collections whose rules are all disabled
+func removeDisabled(cs []Collection, rs map[string]Rule) ([]Collection, map[string]Rule) {
+	rules := map[string]Rule{}
+	for id, r := range rs {
+		if !enabled(r.Enabled) {
+			klog.Warningf("rule %q is disabled", id)
+			continue
+		}
+		rules[id] = r
+	}
+
+	cols := []Collection{}
+	for _, c := range cs {
+		if !enabled(c.Enabled) {
+			klog.Warningf("collection %q is disabled", c.ID)
+			continue
+		}
+
+		ids := []string{}
+		for _, id := range c.RuleIDs {
+			if r, ok := rs[id]; ok && !enabled(r.Enabled) {
+				continue
+			}
+			ids = append(ids, id)
+		}
+
+		if len(ids) == 0 && len(c.RuleIDs) > 0 {
+			klog.Warningf("collection %q only has disabled rules, skipping", c.ID)
+			continue
+		}
+		c.RuleIDs = ids
+		cols = append(cols, c)
+	}
+	return cols, rules
+}
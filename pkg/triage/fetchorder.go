@@ -0,0 +1,176 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/triage-party/pkg/hubbub"
+	"github.com/google/triage-party/pkg/provider"
+	"k8s.io/klog/v2"
+)
+
+// Keys of the fetch_order setting
+const (
+	// FetchByState fetches rules which only need open items before those which need closed items
+	FetchByState = "state"
+	// FetchByType fetches pull request rules before rules for both types, and those before issue rules
+	FetchByType = "type"
+	// FetchByRepo fetches repositories in the order settings.repos lists them
+	FetchByRepo = "repo"
+)
+
+// loadFetchOrder validates the fetch_order setting
+func loadFetchOrder(keys []string) error {
+	seen := map[string]bool{}
+	for _, k := range keys {
+		if k != FetchByState && k != FetchByType && k != FetchByRepo {
+			return fmt.Errorf("unknown key %q, expected %s, %s, or %s", k, FetchByState, FetchByType, FetchByRepo)
+		}
+		if seen[k] {
+			return fmt.Errorf("%q is listed more than once", k)
+		}
+		seen[k] = true
+	}
+	return nil
+}
+
+// fetchRank returns how early a rule should be fetched for a fetch_order key: lower is earlier
+func (p *Party) fetchRank(t Rule, key string) int {
+	switch key {
+	case FetchByState:
+		if hubbub.NeedsClosed(t.Filters) {
+			return 1
+		}
+		return 0
+	case FetchByType:
+		switch t.Type {
+		case hubbub.PullRequest:
+			return 0
+		case hubbub.Issue:
+			return 2
+		}
+		return 1
+	case FetchByRepo:
+		rank := len(p.defaultRepos())
+		for _, r := range t.Repos {
+			if i := p.repoRank(r); i < rank {
+				rank = i
+			}
+		}
+		return rank
+	}
+	return 0
+}
+
+// repoRank returns the position of a repository within settings.repos, or the length of settings.repos if unlisted
+func (p *Party) repoRank(repo string) int {
+	repos := p.defaultRepos()
+	for i, r := range repos {
+		if r == repo {
+			return i
+		}
+	}
+	return len(repos)
+}
+
+// fetchOrder returns the indexes of rules in the order they should be fetched, retaining config order for ties
+func (p *Party) fetchOrder(rules []Rule) []int {
+	order := make([]int, len(rules))
+	for i := range rules {
+		order[i] = i
+	}
+
+	keys := p.loadedSettings().FetchOrder
+	sort.SliceStable(order, func(i, j int) bool {
+		for _, k := range keys {
+			ri, rj := p.fetchRank(rules[order[i]], k), p.fetchRank(rules[order[j]], k)
+			if ri != rj {
+				return ri < rj
+			}
+		}
+		return false
+	})
+	return order
+}
+
+// Prefetch fetches the rules of collections in fetch_order, across all of them, so that a refresh which is
+// cut short has the most important data current. Collections executed afterwards with the same newerThan
+// reuse the cached data. Prefetch does nothing unless fetch_order is set.
+func (p *Party) Prefetch(ctx context.Context, sts []Collection, newerThan time.Time) {
+	if len(p.loadedSettings().FetchOrder) == 0 || len(sts) < 2 {
+		return
+	}
+
+	start := time.Now()
+	rules := []Rule{}
+	hidden := []bool{}
+	seen := map[string]bool{}
+	for i := range sts {
+		s := sts[i]
+		rs, err := p.collectionRules(&s, start)
+		if err != nil {
+			klog.Errorf("collection %q rules: %v", s.ID, err)
+			continue
+		}
+		h := s.Hidden && s.UsedForStats
+		for _, t := range rs {
+			// Collections which share a rule and its repositories share its fetch
+			key := fmt.Sprintf("%s|%s|%s|%v", t.ID, t.Type, strings.Join(t.Repos, ","), h)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			rules = append(rules, t)
+			hidden = append(hidden, h)
+		}
+	}
+
+	klog.Infof("prefetching %d rules across %d collections in fetch order", len(rules), len(sts))
+	for _, i := range p.fetchOrder(rules) {
+		if ctx.Err() != nil {
+			klog.Warningf("prefetch cancelled: %v", ctx.Err())
+			return
+		}
+		sp := provider.SearchParams{NewerThan: newerThan, Hidden: hidden[i]}
+		if _, err := p.ExecuteRule(ctx, sp, rules[i], nil); err != nil {
+			klog.Warningf("prefetch of rule %q failed: %v", rules[i].ID, err)
+		}
+	}
+}
+
+// orderRepos returns repositories in fetch order, if fetch_order includes repo
+func (p *Party) orderRepos(repos []string) []string {
+	byRepo := false
+	for _, k := range p.loadedSettings().FetchOrder {
+		if k == FetchByRepo {
+			byRepo = true
+		}
+	}
+	if !byRepo {
+		return repos
+	}
+
+	ordered := make([]string, len(repos))
+	copy(ordered, repos)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return p.repoRank(ordered[i]) < p.repoRank(ordered[j])
+	})
+	return ordered
+}
@@ -0,0 +1,51 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/triage-party/pkg/provider"
+)
+
+// TemplateFuncs are the functions available to user-provided templates, such as item links.
+// Arguments are ordered so that the value being formatted may be piped in last.
+var TemplateFuncs = map[string]interface{}{
+	"labelNames": labelNames,
+	"lower":      strings.ToLower,
+	"upper":      strings.ToUpper,
+	"join":       func(sep string, s []string) string { return strings.Join(s, sep) },
+	"trimPrefix": func(prefix string, s string) string { return strings.TrimPrefix(s, prefix) },
+	"trimSuffix": func(suffix string, s string) string { return strings.TrimSuffix(s, suffix) },
+	"date":       formatDate,
+}
+
+// labelNames returns the names of a set of labels
+func labelNames(ls []*provider.Label) []string {
+	names := []string{}
+	for _, l := range ls {
+		names = append(names, l.GetName())
+	}
+	return names
+}
+
+// formatDate formats a timestamp using a Go time layout, such as "2006-01-02"
+func formatDate(layout string, t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(layout)
+}
@@ -0,0 +1,125 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/triage-party/pkg/provider"
+	"k8s.io/klog/v2"
+)
+
+var nonSlugRe = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Generator creates one collection per label matching a prefix within a repository
+type Generator struct {
+	Repo        string `yaml:"repo"`
+	LabelPrefix string `yaml:"label-prefix"`
+	// Name is the collection name, where %s is replaced by the label
+	Name string `yaml:"name,omitempty"`
+	// Rule is used for each generated collection, with an additional filter for the label
+	Rule Rule `yaml:"rule"`
+}
+
+// generateCollections discovers labels and returns a collection and rule for each
+func (p *Party) generateCollections(ctx context.Context, gs []Generator) ([]Collection, map[string]Rule, error) {
+	cols := []Collection{}
+	rules := map[string]Rule{}
+	labelFor := map[string]string{}
+
+	for _, g := range gs {
+		if g.LabelPrefix == "" {
+			return nil, nil, fmt.Errorf("generator for %q: label-prefix is required", g.Repo)
+		}
+
+		labels, err := p.listLabels(ctx, g.Repo)
+		if err != nil {
+			return nil, nil, fmt.Errorf("labels for %q: %w", g.Repo, err)
+		}
+
+		for _, l := range labels {
+			if !strings.HasPrefix(l, g.LabelPrefix) {
+				continue
+			}
+
+			id := strings.Trim(nonSlugRe.ReplaceAllString(strings.ToLower(l), "-"), "-")
+			if prev, ok := labelFor[id]; ok {
+				return nil, nil, fmt.Errorf("labels %q and %q both generate the collection ID %q", prev, l, id)
+			}
+			labelFor[id] = l
+			name := l
+			if g.Name != "" {
+				name = strings.ReplaceAll(g.Name, "%s", l)
+			}
+
+			r := g.Rule
+			if r.Name == "" {
+				r.Name = name
+			}
+			r.Repos = []string{g.Repo}
+			r.Filters = append([]provider.Filter{{RawLabel: fmt.Sprintf("^%s$", regexp.QuoteMeta(l))}}, g.Rule.Filters...)
+			rules[id] = r
+
+			cols = append(cols, Collection{
+				ID:      id,
+				Name:    name,
+				RuleIDs: []string{id},
+				Repos:   []string{g.Repo},
+			})
+		}
+	}
+
+	klog.Infof("generated %d collections from labels", len(cols))
+	return cols, rules, nil
+}
+
+// listLabels returns the names of all labels within a repository
+func (p *Party) listLabels(ctx context.Context, repoURL string) ([]string, error) {
+	repo, err := parseRepo(repoURL)
+	if err != nil {
+		return nil, err
+	}
+
+	pr := p.provider(repo.Host)
+	if pr == nil {
+		return nil, fmt.Errorf("no provider configured for %s", repo.Host)
+	}
+
+	sp := provider.SearchParams{
+		Repo:        repo,
+		ListOptions: provider.ListOptions{PerPage: 100},
+	}
+
+	names := []string{}
+	for {
+		ls, resp, err := pr.IssuesListLabels(ctx, sp)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, l := range ls {
+			names = append(names, l.GetName())
+		}
+
+		if resp == nil || resp.NextPage == 0 || sp.ListOptions.Page == resp.NextPage {
+			break
+		}
+		sp.ListOptions.Page = resp.NextPage
+	}
+	return names, nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/triage-party/pkg/persist"
+	"github.com/google/triage-party/pkg/provider"
+	"github.com/stretchr/testify/assert"
+)
+
+// labelProvider lists a fixed set of labels, and is otherwise offline
+type labelProvider struct {
+	provider.Provider
+	labels []string
+}
+
+func (l *labelProvider) IssuesListLabels(context.Context, provider.SearchParams) ([]*provider.Label, *provider.Response, error) {
+	ls := []*provider.Label{}
+	for _, n := range l.labels {
+		n := n
+		ls = append(ls, &provider.Label{Name: &n})
+	}
+	return ls, &provider.Response{}, nil
+}
+
+func TestGenerateCollections(t *testing.T) {
+	config := `generate-collections:
+  - repo: https://github.com/org/project
+    label-prefix: area/
+    rule:
+      filters:
+        - state: open
+`
+	load := func(labels ...string) (*Party, error) {
+		c, err := persist.NewMemory(persist.Config{})
+		if err != nil {
+			t.Fatalf("memory: %v", err)
+		}
+		p, err := New(Config{Cache: c, Provider: &labelProvider{Provider: provider.Offline(), labels: labels}})
+		if err != nil {
+			t.Fatalf("new: %v", err)
+		}
+		return p, p.Load(strings.NewReader(config))
+	}
+
+	p, err := load("area/networking", "area/storage", "kind/bug")
+	if assert.NoError(t, err) {
+		_, err = p.LookupCollection("area-networking")
+		assert.NoError(t, err)
+		_, err = p.LookupCollection("area-storage")
+		assert.NoError(t, err)
+	}
+
+	_, err = load("area/Networking", "area/networking")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `"area-networking"`)
+}
@@ -0,0 +1,69 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"time"
+
+	"github.com/google/triage-party/pkg/hubbub"
+)
+
+// heatHalfLife is how long after an update an item's recency score halves
+const heatHalfLife = 7 * 24 * time.Hour
+
+// HeatWeights configures the composite "heat" score of each item
+type HeatWeights struct {
+	Recency      float64 `yaml:"recency,omitempty"`
+	Comments     float64 `yaml:"comments,omitempty"`
+	Reactions    float64 `yaml:"reactions,omitempty"`
+	Participants float64 `yaml:"participants,omitempty"`
+}
+
+// Enabled returns true if any weight is set
+func (w HeatWeights) Enabled() bool {
+	return w.Recency != 0 || w.Comments != 0 || w.Reactions != 0 || w.Participants != 0
+}
+
+// score calculates the heat of a conversation. Recency ranges from 1 (updated now) towards 0.
+func (w HeatWeights) score(co *hubbub.Conversation, now time.Time) float64 {
+	age := now.Sub(co.Updated)
+	if age < 0 {
+		age = 0
+	}
+	recency := float64(heatHalfLife) / float64(heatHalfLife+age)
+
+	return w.Recency*recency +
+		w.Comments*float64(co.CommentsTotal) +
+		w.Reactions*float64(co.ReactionsTotal) +
+		w.Participants*float64(co.CommentersTotal)
+}
+
+// HeatEnabled returns true if items are given a heat score
+func (p *Party) HeatEnabled() bool {
+	return p.loadedSettings().Heat.Enabled()
+}
+
+// addHeat sets the heat score for each conversation
+func (p *Party) addHeat(cs []*hubbub.Conversation) {
+	heat := p.loadedSettings().Heat
+	if !heat.Enabled() {
+		return
+	}
+
+	now := p.now()
+	for _, co := range cs {
+		co.Heat = heat.score(co, now)
+	}
+}
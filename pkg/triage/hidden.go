@@ -0,0 +1,85 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/triage-party/pkg/hubbub"
+)
+
+// excludeRef matches exclude entries in org/project#number form
+var excludeRef = regexp.MustCompile(`^[\w.-]+/[\w.-]+#\d+$`)
+
+// hideLabeled removes conversations which carry any label listed in the hidden_labels setting
+func hideLabeled(cs []*hubbub.Conversation, labels []string) ([]*hubbub.Conversation, int) {
+	if len(labels) == 0 {
+		return cs, 0
+	}
+
+	hidden := map[string]bool{}
+	for _, l := range labels {
+		hidden[l] = true
+	}
+
+	kept := []*hubbub.Conversation{}
+	for _, c := range cs {
+		if hasLabel(c, hidden) {
+			continue
+		}
+		kept = append(kept, c)
+	}
+	return kept, len(cs) - len(kept)
+}
+
+func hasLabel(c *hubbub.Conversation, labels map[string]bool) bool {
+	for _, l := range c.Labels {
+		if labels[l.GetName()] {
+			return true
+		}
+	}
+	return false
+}
+
+// loadExcludes validates the exclude setting, returning a set of URLs and org/project#number references
+func loadExcludes(entries []string) (map[string]bool, error) {
+	excluded := map[string]bool{}
+	for _, e := range entries {
+		e = strings.TrimSuffix(strings.TrimSpace(e), "/")
+		if !strings.HasPrefix(e, "https://") && !strings.HasPrefix(e, "http://") && !excludeRef.MatchString(e) {
+			return nil, fmt.Errorf("exclude: %q is not an item URL or org/project#number", e)
+		}
+		excluded[e] = true
+	}
+	return excluded, nil
+}
+
+// excludeListed removes conversations listed in the exclude setting
+func excludeListed(cs []*hubbub.Conversation, excluded map[string]bool) ([]*hubbub.Conversation, int) {
+	if len(excluded) == 0 {
+		return cs, 0
+	}
+
+	kept := []*hubbub.Conversation{}
+	for _, c := range cs {
+		if excluded[c.URL] || excluded[fmt.Sprintf("%s/%s#%d", c.Organization, c.Project, c.ID)] {
+			continue
+		}
+		kept = append(kept, c)
+	}
+	return kept, len(cs) - len(kept)
+}
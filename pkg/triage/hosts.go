@@ -0,0 +1,114 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/google/triage-party/pkg/provider"
+	"k8s.io/klog/v2"
+)
+
+// GitHubHost configures an additional GitHub Enterprise instance, used for repos on its host
+type GitHubHost struct {
+	// Host is the hostname used within repository URLs, such as ghe.corp
+	Host string `yaml:"host"`
+	// APIURL is the full API base URL, such as https://ghe.corp/api/v3/
+	APIURL string `yaml:"api_url"`
+	// UploadURL is the full upload URL (defaults to APIURL)
+	UploadURL string `yaml:"upload_url,omitempty"`
+
+	// Token sources for this host, one of which is required unless ReuseGitHubToken is set
+	TokenFile string `yaml:"token_file,omitempty"`
+	TokenEnv  string `yaml:"token_env,omitempty"`
+
+	// ReuseGitHubToken sends the main GitHub token to this host if it has no token of its own
+	ReuseGitHubToken bool `yaml:"reuse_github_token,omitempty"`
+}
+
+// loadGitHubHosts validates additional GitHub hosts, and creates a provider for each
+func (p *Party) loadGitHubHosts(hs []GitHubHost) (map[string]provider.Provider, error) {
+	ps := map[string]provider.Provider{}
+	var errs ConfigErrors
+
+	for _, h := range hs {
+		key := "settings.github_hosts"
+		if h.Host == "" {
+			errs = errs.add(key, fmt.Errorf("host is required"))
+			continue
+		}
+
+		if ps[h.Host] != nil {
+			errs = errs.add(key, fmt.Errorf("%q is listed more than once", h.Host))
+			continue
+		}
+
+		if p.runtime.Offline {
+			ps[h.Host] = provider.Offline()
+			continue
+		}
+
+		token, err := hostToken(h, p.relativePath(h.TokenFile))
+		if err != nil {
+			errs = errs.add(key, fmt.Errorf("%s: %w", h.Host, err))
+			continue
+		}
+		if token == "" {
+			if !h.ReuseGitHubToken {
+				errs = errs.add(key, fmt.Errorf("%s: token_file or token_env is required, unless reuse_github_token is set", h.Host))
+				continue
+			}
+			token = p.runtime.GitHubToken
+		}
+
+		gh, err := provider.NewGitHubEnterprise(provider.WithHTTPClient(context.Background(), p.httpClient), token, h.APIURL, h.UploadURL, p.runtime.UserAgent)
+		if err != nil {
+			errs = errs.add(key, fmt.Errorf("%s: %w", h.Host, err))
+			continue
+		}
+
+		klog.Infof("using %s for repositories on %s", h.APIURL, h.Host)
+		ps[h.Host] = provider.WithRetries(provider.WithConcurrencyLimit(gh, p.runtime.GitHubConcurrency))
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return ps, nil
+}
+
+// hostToken reads the token for a GitHub host, if one is configured. tokenFile is the resolved path of h.TokenFile.
+func hostToken(h GitHubHost, tokenFile string) (string, error) {
+	if tokenFile != "" {
+		bs, err := ioutil.ReadFile(tokenFile)
+		if err != nil {
+			return "", fmt.Errorf("token file: %w", err)
+		}
+		return strings.TrimSpace(string(bs)), nil
+	}
+
+	if h.TokenEnv != "" {
+		t := strings.TrimSpace(os.Getenv(h.TokenEnv))
+		if t == "" && !h.ReuseGitHubToken {
+			return "", fmt.Errorf("token_env %s is empty", h.TokenEnv)
+		}
+		return t, nil
+	}
+	return "", nil
+}
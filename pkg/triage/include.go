@@ -0,0 +1,84 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+)
+
+// includedConfig is a configuration file included by another, which may only define collections, rules, and includes
+type includedConfig struct {
+	Include        []string     `yaml:"include,omitempty"`
+	Settings       *Settings    `yaml:"settings,omitempty"`
+	RawCollections []Collection `yaml:"collections"`
+}
+
+// loadIncludes loads included files depth-first, in order, before the file which includes them.
+// Their rules are added to rules, and their collections returned. Paths are relative to dir.
+func loadIncludes(dir string, paths []string, mode string, rules map[string]Rule, including map[string]bool) ([]Collection, error) {
+	cs := []Collection{}
+	for _, path := range paths {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		path = filepath.Clean(path)
+
+		if including[path] {
+			return nil, fmt.Errorf("%s is included by itself", path)
+		}
+
+		bs, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read: %w", err)
+		}
+
+		ics, err := loadInclude(path, bs, mode, rules, including)
+		if err != nil {
+			errs := ConfigErrors{}.add("", err)
+			errs.locate(path, bs)
+			return nil, errs
+		}
+		cs = append(cs, ics...)
+	}
+	return cs, nil
+}
+
+// loadInclude loads a single included file, and the files it includes in turn
+func loadInclude(path string, bs []byte, mode string, rules map[string]Rule, including map[string]bool) ([]Collection, error) {
+	ic := &includedConfig{}
+	if err := yaml.Unmarshal(bs, &ic); err != nil {
+		return nil, decodeErrors(err)
+	}
+	if ic.Settings != nil {
+		return nil, ConfigErrors{}.add("settings", fmt.Errorf("may only be set in the main configuration"))
+	}
+
+	including[path] = true
+	defer delete(including, path)
+
+	cs, err := loadIncludes(filepath.Dir(path), ic.Include, mode, rules, including)
+	if err != nil {
+		return nil, ConfigErrors{}.add("include", err)
+	}
+
+	if err := loadRules(bs, mode, rules); err != nil {
+		return nil, err
+	}
+	return append(cs, ic.RawCollections...), nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/triage-party/pkg/hubbub"
+)
+
+// RefreshItem fetches the latest data for a single issue or PR URL
+func (p *Party) RefreshItem(ctx context.Context, url string) (*hubbub.Conversation, error) {
+	sp, err := parseItemURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("parse %q: %w", url, err)
+	}
+	return p.loadedEngine().RefreshItem(ctx, sp)
+}
+
+// ReplaceItem returns a copy of a collection result with a refreshed conversation in place of the one shown.
+// The conversation is dropped from rules which it no longer matches.
+func ReplaceItem(r *CollectionResult, co *hubbub.Conversation) *CollectionResult {
+	now := time.Now()
+	rrs := []*RuleResult{}
+	for _, rr := range r.RuleResults {
+		for i, c := range rr.Items {
+			if c.URL != co.URL {
+				continue
+			}
+
+			replaced := *rr
+			replaced.Items = append([]*hubbub.Conversation{}, rr.Items[:i]...)
+			if hubbub.MatchConversation(co, rr.Rule.Filters, now) {
+				replaced.Items = append(replaced.Items, co)
+			}
+			replaced.Items = append(replaced.Items, rr.Items[i+1:]...)
+			rr = &replaced
+			break
+		}
+		rrs = append(rrs, rr)
+	}
+
+	updated := SummarizeCollectionResult(r.Collection, rrs)
+	updated.Pinned = r.Pinned
+	updated.Created = r.Created
+	updated.NewerThan = r.NewerThan
+	updated.OldestInput = r.OldestInput
+	return updated
+}
@@ -0,0 +1,65 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/triage-party/pkg/provider"
+)
+
+// defaultLabelColor is used for labels without a valid color, matching GitHub's default
+const defaultLabelColor = "ededed"
+
+// hexColor matches a 6 digit hex color, without a leading #
+var hexColor = regexp.MustCompile(`^[0-9a-fA-F]{6}$`)
+
+// normalizeColor strips a leading # from a color, returning "" if it is not valid hex
+func normalizeColor(s string) string {
+	s = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(s), "#"))
+	if !hexColor.MatchString(s) {
+		return ""
+	}
+	return s
+}
+
+// loadLabelColors validates the label_colors setting, returning colors by lowercase label name
+func loadLabelColors(m map[string]string) (map[string]string, error) {
+	colors := map[string]string{}
+	for name, c := range m {
+		n := normalizeColor(c)
+		if n == "" {
+			return nil, fmt.Errorf("%q: %q is not a hex color, such as d73a4a", name, c)
+		}
+		colors[strings.ToLower(name)] = n
+	}
+	return colors, nil
+}
+
+// LabelColor returns the hex color to render a label with: the configured color, its own, or a default
+func (p *Party) LabelColor(l *provider.Label) string {
+	p.reposMu.RLock()
+	c, ok := p.labelColors[strings.ToLower(l.GetName())]
+	p.reposMu.RUnlock()
+	if ok {
+		return c
+	}
+	if c := normalizeColor(l.GetColor()); c != "" {
+		return c
+	}
+	return defaultLabelColor
+}
@@ -0,0 +1,39 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"testing"
+
+	"github.com/google/triage-party/pkg/provider"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabelColor(t *testing.T) {
+	colors, err := loadLabelColors(map[string]string{"Kind/Bug": "#B60205"})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	p := &Party{labelColors: colors}
+
+	label := func(name, color string) *provider.Label { return &provider.Label{Name: &name, Color: &color} }
+	assert.Equal(t, "b60205", p.LabelColor(label("kind/bug", "d73a4a")))
+	assert.Equal(t, "0e8a16", p.LabelColor(label("lgtm", "0E8A16")))
+	assert.Equal(t, "0e8a16", p.LabelColor(label("gitlab", "#0e8a16")))
+	assert.Equal(t, defaultLabelColor, p.LabelColor(label("bare", "")))
+
+	_, err = loadLabelColors(map[string]string{"bug": "red"})
+	assert.Error(t, err)
+}
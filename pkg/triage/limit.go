@@ -0,0 +1,69 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/google/triage-party/pkg/hubbub"
+)
+
+// itemLimit returns the maximum number of items each rule shows within a collection (0 for unlimited)
+func (p *Party) itemLimit(s Collection) int {
+	if s.Limit != nil {
+		return *s.Limit
+	}
+	return p.loadedSettings().Limit
+}
+
+// loadLimit validates a collection limit
+func (s *Collection) loadLimit() error {
+	if s.Limit != nil && *s.Limit < 0 {
+		return fmt.Errorf("limit: %d is negative, use 0 for unlimited", *s.Limit)
+	}
+	return nil
+}
+
+// limitItems keeps the n hottest items if heat is enabled, otherwise the n most recently created, in that order.
+// Pinned items are always kept, first, and count towards n.
+func limitItems(cs []*hubbub.Conversation, n int, byHeat bool, pins []pin) ([]*hubbub.Conversation, int) {
+	if n <= 0 || len(cs) <= n {
+		return cs, 0
+	}
+
+	kept := []*hubbub.Conversation{}
+	sorted := []*hubbub.Conversation{}
+	for _, c := range cs {
+		if pinRank(pins, c) >= 0 {
+			kept = append(kept, c)
+			continue
+		}
+		sorted = append(sorted, c)
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if byHeat && sorted[i].Heat != sorted[j].Heat {
+			return sorted[i].Heat > sorted[j].Heat
+		}
+		return sorted[i].Created.After(sorted[j].Created)
+	})
+
+	rest := n - len(kept)
+	if rest < 0 {
+		rest = 0
+	}
+	return append(kept, sorted[:rest]...), len(sorted) - rest
+}
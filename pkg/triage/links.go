@@ -0,0 +1,77 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/google/triage-party/pkg/hubbub"
+	"k8s.io/klog/v2"
+)
+
+// ItemLink is a templated link shown alongside each item, such as to an external dashboard
+type ItemLink struct {
+	Name string `yaml:"name"`
+	// URL is a Go template, executed against each item
+	URL string `yaml:"url"`
+}
+
+// Link is a rendered item link
+type Link struct {
+	Name string
+	URL  string
+}
+
+type itemLinkTemplate struct {
+	name string
+	tmpl *template.Template
+}
+
+// loadItemLinks parses item link templates
+func loadItemLinks(ls []ItemLink) ([]itemLinkTemplate, error) {
+	ts := []itemLinkTemplate{}
+	for _, l := range ls {
+		if l.Name == "" || l.URL == "" {
+			return nil, fmt.Errorf("item link %q requires a name and url", l.Name)
+		}
+
+		t, err := template.New(l.Name).Funcs(TemplateFuncs).Option("missingkey=error").Parse(l.URL)
+		if err != nil {
+			return nil, fmt.Errorf("item link %q: %w", l.Name, err)
+		}
+		ts = append(ts, itemLinkTemplate{name: l.Name, tmpl: t})
+	}
+	return ts, nil
+}
+
+// ItemLinks renders the configured item links for a conversation
+func (p *Party) ItemLinks(co *hubbub.Conversation) []Link {
+	p.reposMu.RLock()
+	links := p.itemLinks
+	p.reposMu.RUnlock()
+
+	ls := []Link{}
+	for _, t := range links {
+		var sb strings.Builder
+		if err := t.tmpl.Execute(&sb, co); err != nil {
+			klog.Errorf("item link %q for %s: %v", t.name, co.URL, err)
+			continue
+		}
+		ls = append(ls, Link{Name: t.name, URL: sb.String()})
+	}
+	return ls
+}
@@ -0,0 +1,49 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/triage-party/pkg/hubbub"
+	"github.com/google/triage-party/pkg/provider"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItemLinks(t *testing.T) {
+	ts, err := loadItemLinks([]ItemLink{{Name: "dash", URL: `https://dash/?id={{ .ID }}&l={{ labelNames .Labels | join "," | urlquery }}`}})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	bug := "kind/bug"
+	p1 := "priority/p1"
+	p := &Party{itemLinks: ts}
+	co := &hubbub.Conversation{ID: 7, Labels: []*provider.Label{{Name: &bug}, {Name: &p1}}}
+	assert.Equal(t, []Link{{Name: "dash", URL: "https://dash/?id=7&l=kind%2Fbug%2Cpriority%2Fp1"}}, p.ItemLinks(co))
+
+	_, err = loadItemLinks([]ItemLink{{Name: "broken", URL: "{{ .ID"}})
+	assert.NotNil(t, err)
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	ts, err := loadItemLinks([]ItemLink{{Name: "fmt", URL: `{{ upper .Organization }}/{{ lower .Project }}/{{ trimPrefix "v" "v1.2" }}/{{ date "2006-01-02" .Created }}`}})
+	assert.NoError(t, err)
+
+	co := &hubbub.Conversation{Organization: "org", Project: "Proj", Created: time.Date(2020, 5, 4, 12, 0, 0, 0, time.UTC)}
+	p := &Party{itemLinks: ts}
+	assert.Equal(t, []Link{{Name: "fmt", URL: "ORG/proj/1.2/2020-05-04"}}, p.ItemLinks(co))
+}
@@ -0,0 +1,81 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/triage-party/pkg/hubbub"
+	"github.com/google/triage-party/pkg/provider"
+	"k8s.io/klog/v2"
+)
+
+// ownerWildcard is the project name which matches every repository of an owner, as in https://github.com/org/*
+const ownerWildcard = "*"
+
+// repoListMaxAge parses the repo_list_refresh setting, returning 0 if unset or invalid
+func repoListMaxAge(s string) time.Duration {
+	if s == "" {
+		return 0
+	}
+	d, _, _ := hubbub.ParseDuration(s)
+	return d
+}
+
+// expandRepos replaces owner/* repository URLs with the repositories of that owner.
+// Owners which the token is not permitted to list are returned as denied.
+func (p *Party) expandRepos(ctx context.Context, repos []string) ([]string, []string, error) {
+	expanded := []string{}
+	denied := []string{}
+	seen := map[string]bool{}
+
+	add := func(u string) {
+		if !seen[u] {
+			seen[u] = true
+			expanded = append(expanded, u)
+		}
+	}
+
+	for _, u := range repos {
+		if !strings.HasSuffix(u, "/"+ownerWildcard) {
+			add(u)
+			continue
+		}
+
+		r, err := parseRepo(u)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		names, err := p.loadedEngine().OwnerRepos(ctx, provider.SearchParams{Repo: r})
+		if err != nil {
+			if provider.AccessDenied(err) {
+				klog.Warningf("access denied listing repositories for %s, skipping: %v", u, err)
+				denied = append(denied, u)
+				continue
+			}
+			return nil, nil, err
+		}
+
+		klog.V(1).Infof("%s expanded to %d repositories", u, len(names))
+		base := strings.TrimSuffix(u, ownerWildcard)
+		for _, n := range names {
+			add(base + n)
+		}
+	}
+	return expanded, denied, nil
+}
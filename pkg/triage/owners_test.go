@@ -0,0 +1,82 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v33/github"
+	"github.com/google/triage-party/pkg/persist"
+	"github.com/google/triage-party/pkg/provider"
+	"github.com/stretchr/testify/assert"
+)
+
+// ownerProvider lists repositories by owner over two pages, counting requests
+type ownerProvider struct {
+	provider.Provider
+	requests int
+}
+
+func (o *ownerProvider) RepositoriesListByOwner(_ context.Context, sp provider.SearchParams) ([]string, *provider.Response, error) {
+	o.requests++
+	switch sp.Repo.Organization {
+	case "secret":
+		return nil, &provider.Response{}, &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusForbidden}}
+	case "broken":
+		return nil, &provider.Response{}, &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusInternalServerError}}
+	}
+
+	if sp.ListOptions.Page == 0 {
+		return []string{"web"}, &provider.Response{NextPage: 2}, nil
+	}
+	return []string{"api"}, &provider.Response{}, nil
+}
+
+func TestExpandRepos(t *testing.T) {
+	c, err := persist.NewMemory(persist.Config{})
+	if err != nil {
+		t.Fatalf("memory: %v", err)
+	}
+	if err := c.Initialize(); err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+	o := &ownerProvider{Provider: provider.Offline()}
+	p, err := New(Config{Cache: c, Provider: o})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	if err := p.Load(strings.NewReader("collections:\n  - id: open\n    rules: [open]\nrules:\n  open:\n    filters:\n      - state: open\n")); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	ctx := context.Background()
+	repos := []string{"https://github.com/org/*", "https://github.com/org/api", "https://github.com/secret/*"}
+	expanded, denied, err := p.expandRepos(ctx, repos)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"https://github.com/org/api", "https://github.com/org/web"}, expanded)
+	assert.Equal(t, []string{"https://github.com/secret/*"}, denied)
+	assert.Equal(t, 3, o.requests)
+
+	// The repository list is cached
+	_, _, err = p.expandRepos(ctx, repos[:1])
+	assert.Nil(t, err)
+	assert.Equal(t, 3, o.requests)
+
+	_, _, err = p.expandRepos(ctx, []string{"https://github.com/broken/*"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,112 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/triage-party/pkg/hubbub"
+)
+
+var (
+	// pinURLRe matches issue and PR URLs, such as https://github.com/org/project/issues/12
+	pinURLRe = regexp.MustCompile(`^https?://[^/]+/([\w.-]+)/([\w.-]+)/(?:-/)?(?:issues|pull|merge_requests)/(\d+)/?$`)
+	// pinRefRe matches #12, 12, and org/project#12
+	pinRefRe = regexp.MustCompile(`^(?:([\w.-]+)/([\w.-]+))?#?(\d+)$`)
+)
+
+// pin is an item pinned to the top of a collection, where an empty org matches any repository
+type pin struct {
+	org     string
+	project string
+	num     int
+}
+
+// loadPins parses the pinned setting of a collection
+func (s *Collection) loadPins() error {
+	s.pins = nil
+	for _, p := range s.Pinned {
+		m := pinURLRe.FindStringSubmatch(p)
+		if m == nil {
+			m = pinRefRe.FindStringSubmatch(p)
+		}
+		if m == nil {
+			return fmt.Errorf("%q is not an issue URL or number", p)
+		}
+
+		num, err := strconv.Atoi(m[3])
+		if err != nil {
+			return fmt.Errorf("%q: %w", p, err)
+		}
+		s.pins = append(s.pins, pin{org: m[1], project: m[2], num: num})
+	}
+	return nil
+}
+
+// pinRank returns the position of an item within the pinned items, or -1 if it is not pinned
+func pinRank(pins []pin, co *hubbub.Conversation) int {
+	for i, p := range pins {
+		if p.num != co.ID {
+			continue
+		}
+		if p.org == "" || (strings.EqualFold(p.org, co.Organization) && strings.EqualFold(p.project, co.Project)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// pinFirst moves pinned items to the front, in pinned order, keeping the order of the remaining items
+func pinFirst(cs []*hubbub.Conversation, pins []pin) []*hubbub.Conversation {
+	if len(pins) == 0 {
+		return cs
+	}
+
+	pinned := make([][]*hubbub.Conversation, len(pins))
+	rest := []*hubbub.Conversation{}
+	for _, c := range cs {
+		if i := pinRank(pins, c); i >= 0 {
+			pinned[i] = append(pinned[i], c)
+			continue
+		}
+		rest = append(rest, c)
+	}
+
+	ordered := []*hubbub.Conversation{}
+	for _, p := range pinned {
+		ordered = append(ordered, p...)
+	}
+	return append(ordered, rest...)
+}
+
+// pinnedRanks returns the rank of each pinned item by URL, with the first pin ranked highest
+func pinnedRanks(rrs []*RuleResult, pins []pin) map[string]int {
+	ranks := map[string]int{}
+	if len(pins) == 0 {
+		return ranks
+	}
+
+	for _, rr := range rrs {
+		for _, c := range rr.Items {
+			if i := pinRank(pins, c); i >= 0 {
+				ranks[c.URL] = len(pins) - i
+			}
+		}
+	}
+	return ranks
+}
@@ -0,0 +1,54 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/triage-party/pkg/hubbub"
+)
+
+// RefreshBounds returns the min_refresh and max_refresh of a collection, or 0 where unset
+func (s *Collection) RefreshBounds() (time.Duration, time.Duration) {
+	return refreshDuration(s.MinRefresh), refreshDuration(s.MaxRefresh)
+}
+
+// refreshDuration parses a refresh bound, returning 0 if unset or invalid
+func refreshDuration(s string) time.Duration {
+	if s == "" {
+		return 0
+	}
+	d, _, _ := hubbub.ParseDuration(s)
+	return d
+}
+
+// validateRefreshBounds checks that the refresh bounds of a collection are durations, in order
+func validateRefreshBounds(s *Collection) error {
+	for _, b := range []struct {
+		key string
+		val string
+	}{{"min_refresh", s.MinRefresh}, {"max_refresh", s.MaxRefresh}} {
+		if b.val != "" && refreshDuration(b.val) <= 0 {
+			return fmt.Errorf("%s: unable to parse %q as a duration", b.key, b.val)
+		}
+	}
+
+	min, max := s.RefreshBounds()
+	if min > 0 && max > 0 && min > max {
+		return fmt.Errorf("min_refresh (%s) is longer than max_refresh (%s)", s.MinRefresh, s.MaxRefresh)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"io"
+
+	"github.com/google/triage-party/pkg/hubbub"
+	"k8s.io/klog/v2"
+)
+
+// Reload parses a new configuration, and replaces the running one with it only if it is valid.
+// Providers, cached data, and what the engine has learned about items are kept, so nothing needs
+// to be fetched again. Callers must ensure that no collection is being executed, such as by reloading
+// through the updater.
+func (p *Party) Reload(r io.Reader) error {
+	np := &Party{
+		runtime:       p.runtime,
+		cache:         p.cache,
+		reposOverride: p.overrideRepos(),
+		debug:         p.debug,
+		now:           p.now,
+		httpClient:    p.httpClient,
+		github:        p.github,
+		gitlab:        p.gitlab,
+		configDir:     p.configDir,
+	}
+
+	if err := np.Load(r); err != nil {
+		return err
+	}
+
+	np.engine.Inherit(p.engine)
+
+	p.reposMu.Lock()
+	defer p.reposMu.Unlock()
+
+	p.engine = np.engine
+	p.settings = np.settings
+	p.collections = np.collections
+	p.rules = np.rules
+	p.itemLinks = np.itemLinks
+	p.excluded = np.excluded
+	p.labelColors = np.labelColors
+	p.hosts = np.hosts
+	p.pools = np.pools
+	p.configDir = np.configDir
+	p.inlineRepos = np.inlineRepos
+
+	klog.Infof("reloaded config: %d collections, %d rules", len(p.collections), len(p.rules))
+	return nil
+}
+
+// loadedEngine returns the engine, which Reload may replace
+func (p *Party) loadedEngine() *hubbub.Engine {
+	p.reposMu.RLock()
+	defer p.reposMu.RUnlock()
+	return p.engine
+}
+
+// loadedSettings returns the settings, which Reload may replace
+func (p *Party) loadedSettings() Settings {
+	p.reposMu.RLock()
+	defer p.reposMu.RUnlock()
+	return p.settings
+}
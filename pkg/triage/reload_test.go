@@ -0,0 +1,60 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReload(t *testing.T) {
+	config := func(name, id string) io.Reader {
+		return strings.NewReader(fmt.Sprintf(`settings:
+  name: %s
+collections:
+  - id: %s
+    rules:
+      - open
+rules:
+  open:
+    filters:
+      - state: open
+`, name, id))
+	}
+
+	p := &Party{}
+	if err := p.Load(config("old", "daily")); err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+
+	err := p.Reload(strings.NewReader("collections:\n  - id: weekly\n    rules:\n      - missing\n"))
+	assert.Error(t, err)
+	assert.Equal(t, "old", p.Name())
+	_, err = p.LookupCollection("daily")
+	assert.NoError(t, err)
+
+	if err := p.Reload(config("new", "weekly")); err != nil {
+		t.Fatalf("Reload() = %v", err)
+	}
+	assert.Equal(t, "new", p.Name())
+	_, err = p.LookupCollection("daily")
+	assert.Error(t, err)
+	_, err = p.LookupCollection("weekly")
+	assert.NoError(t, err)
+}
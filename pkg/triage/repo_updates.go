@@ -0,0 +1,73 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// RepoUpdated records that a repository changed at t, returning the IDs of the collections which include it.
+// The repository is given as a URL, such as https://github.com/org/project.
+func (p *Party) RepoUpdated(repoURL string, t time.Time) []string {
+	r, err := parseRepo(repoURL)
+	if err != nil {
+		klog.Warningf("unable to parse updated repo %q: %v", repoURL, err)
+		return nil
+	}
+
+	ids := p.CollectionsForRepo(repoURL)
+	if e := p.loadedEngine(); len(ids) > 0 && e != nil {
+		e.RepoUpdated(r.Organization, r.Project, t)
+	}
+	return ids
+}
+
+// CollectionsForRepo returns the IDs of the collections with a rule which reads from a repository URL
+func (p *Party) CollectionsForRepo(repoURL string) []string {
+	ids := []string{}
+	sts, _ := p.ListCollections()
+	for _, s := range sts {
+		for _, tid := range s.RuleIDs {
+			t, err := p.LookupRule(tid)
+			if err != nil {
+				continue
+			}
+			if includesRepo(p.collectionRepos(s, t), repoURL) {
+				ids = append(ids, s.ID)
+				break
+			}
+		}
+	}
+	return ids
+}
+
+// includesRepo returns true if a list of repository URLs includes a repository, directly or by owner wildcard
+func includesRepo(repos []string, repoURL string) bool {
+	want := strings.ToLower(strings.TrimSuffix(repoURL, "/"))
+	for _, r := range repos {
+		r = strings.ToLower(strings.TrimSuffix(r, "/"))
+		if r == want {
+			return true
+		}
+		if strings.HasSuffix(r, "/"+ownerWildcard) && strings.HasPrefix(want, strings.TrimSuffix(r, ownerWildcard)) &&
+			!strings.Contains(strings.TrimPrefix(want, strings.TrimSuffix(r, ownerWildcard)), "/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,32 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIncludesRepo(t *testing.T) {
+	repos := []string{"https://github.com/kubernetes/minikube", "https://github.com/google/*"}
+
+	assert.True(t, includesRepo(repos, "https://github.com/kubernetes/minikube"))
+	assert.True(t, includesRepo(repos, "https://github.com/Kubernetes/Minikube/"))
+	assert.True(t, includesRepo(repos, "https://github.com/google/triage-party"))
+	assert.False(t, includesRepo(repos, "https://github.com/kubernetes/kubernetes"))
+	assert.False(t, includesRepo(repos, "https://github.com/googlecloudplatform/skaffold"))
+	assert.False(t, includesRepo(repos, "https://github.com/google/a/b"))
+}
@@ -0,0 +1,119 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/klog/v2"
+)
+
+// readRepoList reads repositories from a file, one per line, ignoring blank lines and # comments
+func readRepoList(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	repos := []string{}
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := s.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line != "" {
+			repos = append(repos, line)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return repos, nil
+}
+
+// withRepoList returns repos followed by those listed within path, if set
+func withRepoList(repos []string, path string) ([]string, error) {
+	if path == "" {
+		return repos, nil
+	}
+	listed, err := readRepoList(path)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]string{}, repos...), listed...), nil
+}
+
+// relativePath returns a path from the config file, resolved relative to its directory
+func (p *Party) relativePath(path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(p.configDir, path)
+}
+
+// defaultRepos returns the repositories rules are evaluated against if they do not list their own
+func (p *Party) defaultRepos() []string {
+	p.reposMu.RLock()
+	defer p.reposMu.RUnlock()
+	return p.settings.Repos
+}
+
+// overrideRepos returns the repositories passed via --repos or --repos-file, which replace all others
+func (p *Party) overrideRepos() []string {
+	p.reposMu.RLock()
+	defer p.reposMu.RUnlock()
+	return p.reposOverride
+}
+
+// ReloadRepos rereads repos_file and --repos-file, keeping the previous repositories if either is invalid
+func (p *Party) ReloadRepos() error {
+	p.reposMu.RLock()
+	reposFile := p.relativePath(p.settings.ReposFile)
+	inline := p.inlineRepos
+	p.reposMu.RUnlock()
+
+	if reposFile == "" && p.runtime.ReposFile == "" {
+		return nil
+	}
+
+	repos, err := withRepoList(inline, reposFile)
+	if err != nil {
+		return fmt.Errorf("repos_file: %w", err)
+	}
+	override, err := withRepoList(p.runtime.Repos, p.runtime.ReposFile)
+	if err != nil {
+		return fmt.Errorf("repos file: %w", err)
+	}
+
+	for _, r := range append(append([]string{}, repos...), override...) {
+		if _, err := parseRepo(r); err != nil {
+			return fmt.Errorf("invalid repo URL %q", r)
+		}
+	}
+
+	p.reposMu.Lock()
+	defer p.reposMu.Unlock()
+	klog.Infof("reloaded repos: %d configured, %d override", len(repos), len(override))
+	p.settings.Repos = repos
+	p.reposOverride = override
+	return nil
+}
@@ -36,6 +36,43 @@ type Rule struct {
 	Repos      []string          `yaml:"repos,omitempty"`
 	Type       string            `yaml:"type,omitempty"`
 	Filters    []provider.Filter `yaml:"filters"`
+
+	// Enabled may be set to false to skip the rule without removing it (default: true)
+	Enabled *bool `yaml:"enabled,omitempty"`
+
+	// Sample is how many matching items to show per day, selected deterministically by date
+	Sample int `yaml:"sample,omitempty"`
+
+	// ChangedSinceLastRefresh only matches items updated since the collection was last refreshed
+	ChangedSinceLastRefresh bool `yaml:"changed_since_last_refresh,omitempty"`
+
+	// MinItems hides the rule from collection pages when fewer items than this match
+	MinItems int `yaml:"min_items,omitempty"`
+
+	// Dedup overrides the collection dedup setting for this rule
+	Dedup *bool `yaml:"dedup,omitempty"`
+
+	// Search is a raw GitHub search query, used instead of listing items per repository
+	Search string `yaml:"search,omitempty"`
+
+	// Owner is the team responsible for triaging this rule
+	Owner string `yaml:"owner,omitempty"`
+
+	// AllBaseRefs shows pull requests targeting any branch, ignoring the exclude_base_refs setting
+	AllBaseRefs bool `yaml:"all_base_refs,omitempty"`
+
+	// collection the rule is being executed within, for sla filters
+	collection *Collection
+}
+
+// InvolvesViewer returns true if the rule filters items by the logged in user
+func (t Rule) InvolvesViewer() bool {
+	for _, f := range t.Filters {
+		if f.Involves == provider.InvolvesViewer {
+			return true
+		}
+	}
+	return false
 }
 
 type RuleResult struct {
@@ -52,12 +89,33 @@ type RuleResult struct {
 	TotalAccumulatedHoldDays float64
 
 	Duplicates map[string]bool
+	// Dedup is whether items shown by earlier rules in the collection are omitted
+	Dedup bool
 
 	// OldestInput is the timestamp of the oldest input data
 	OldestInput time.Time
 
 	// When was this rule result created?
 	Created time.Time
+
+	// Duration is how long the rule took to execute
+	Duration time.Duration
+
+	// Stale is set if the latest refresh of this rule failed
+	Stale bool
+	// Error is why the latest refresh of this rule failed
+	Error string
+
+	// Denied lists repositories which the token is not permitted to read
+	Denied []string
+
+	// Hidden is how many matching items were removed by the hidden_labels setting
+	Hidden int
+	// Excluded is how many matching items were removed by the exclude setting
+	Excluded int
+
+	// Truncated is how many matching items are not shown due to the collection limit
+	Truncated int
 }
 
 // SummarizeRuleResult adds together statistics about a pool of conversations
@@ -67,25 +125,8 @@ func SummarizeRuleResult(t Rule, cs []*hubbub.Conversation, seen map[string]*Rul
 		Duplicates: map[string]bool{},
 	}
 
-	if seen == nil {
-		r.Items = cs
-	} else {
-		for _, c := range cs {
-			dupeRule := seen[c.URL]
-			if dupeRule != nil {
-				// Find a nefarious bug
-				if t.ID == dupeRule.ID {
-					klog.Errorf("LOGIC FAILURE: %s was previously seen by rule %q, which is the same as the current rule: %q", c.URL, dupeRule.ID, t.ID)
-					continue
-				}
-
-				klog.V(2).Infof("dupe: %s (now: %q, previous: %q)", c.URL, t.ID, dupeRule.ID)
-				r.Duplicates[c.URL] = true
-			}
-			r.Items = append(r.Items, c)
-			seen[c.URL] = &t
-		}
-	}
+	r.Items = cs
+	markDuplicates(r, seen)
 
 	if len(cs) == 0 {
 		return r
@@ -110,13 +151,67 @@ func SummarizeRuleResult(t Rule, cs []*hubbub.Conversation, seen map[string]*Rul
 	return r
 }
 
+// markDuplicates marks items within a rule result which were seen by earlier rules, then records them as seen
+func markDuplicates(r *RuleResult, seen map[string]*Rule) {
+	if seen == nil {
+		return
+	}
+
+	t := &r.Rule
+	items := []*hubbub.Conversation{}
+	for _, c := range r.Items {
+		dupeRule := seen[c.URL]
+		if dupeRule != nil {
+			// Find a nefarious bug
+			if t.ID == dupeRule.ID {
+				klog.Errorf("LOGIC FAILURE: %s was previously seen by rule %q, which is the same as the current rule: %q", c.URL, dupeRule.ID, t.ID)
+				continue
+			}
+
+			klog.V(2).Infof("dupe: %s (now: %q, previous: %q)", c.URL, t.ID, dupeRule.ID)
+			r.Duplicates[c.URL] = true
+		}
+		items = append(items, c)
+		seen[c.URL] = t
+	}
+	r.Items = items
+}
+
 // ExecuteRule executes a rule. seen is optional.
 func (p *Party) ExecuteRule(ctx context.Context, sp provider.SearchParams, t Rule, seen map[string]*Rule) (*RuleResult, error) {
 	klog.V(1).Infof("executing rule %q for results newer than %s", t.ID, logu.STime(sp.NewerThan))
 	rcs := []*hubbub.Conversation{}
-	oldest := time.Now()
+	start := time.Now()
+	oldest := start
+
+	e := p.loadedEngine()
+	settings := p.loadedSettings()
+	p.reposMu.RLock()
+	excludedURLs := p.excluded
+	p.reposMu.RUnlock()
+
+	repos, denied, err := p.expandRepos(ctx, t.Repos)
+	if err != nil {
+		return nil, err
+	}
+	repos = p.orderRepos(repos)
+
+	// Search queries may span repositories, and are executed once
+	if t.Search != "" {
+		sp.Query = searchQuery(t)
+		sp.Filters = t.Filters
+
+		cs, ts, err := e.SearchQuery(ctx, sp)
+		if err != nil {
+			return nil, err
+		}
+		rcs = append(rcs, cs...)
+		oldest = ts
+		repos = nil
+	}
+
+	for _, repoUrl := range repos {
 
-	for _, repoUrl := range t.Repos {
 		r, err := parseRepo(repoUrl)
 		if err != nil {
 			return nil, err
@@ -129,17 +224,23 @@ func (p *Party) ExecuteRule(ctx context.Context, sp provider.SearchParams, t Rul
 
 		sp.Repo = r
 		sp.Filters = t.Filters
+		sp.ClosedWindow = hubbub.ClosedWindow(t.Filters)
 
 		switch t.Type {
 		case hubbub.Issue:
-			cs, ts, err = p.engine.SearchIssues(ctx, sp)
+			cs, ts, err = e.SearchIssues(ctx, sp)
 		case hubbub.PullRequest:
-			cs, ts, err = p.engine.SearchPullRequests(ctx, sp)
+			cs, ts, err = e.SearchPullRequests(ctx, sp)
 		default:
-			cs, ts, err = p.engine.SearchAny(ctx, sp)
+			cs, ts, err = e.SearchAny(ctx, sp)
 		}
 
 		if err != nil {
+			if provider.AccessDenied(err) {
+				klog.Warningf("rule %q: access denied to %s, skipping: %v", t.ID, repoUrl, err)
+				denied = append(denied, repoUrl)
+				continue
+			}
 			return nil, err
 		}
 
@@ -150,24 +251,67 @@ func (p *Party) ExecuteRule(ctx context.Context, sp provider.SearchParams, t Rul
 	}
 
 	klog.V(1).Infof("rule %q matched %d items", t.ID, len(rcs))
+	rcs, hidden := hideLabeled(rcs, settings.HiddenLabels)
+	if hidden > 0 {
+		klog.V(1).Infof("rule %q hid %d items via hidden_labels", t.ID, hidden)
+	}
+
+	rcs, excluded := excludeListed(rcs, excludedURLs)
+	if excluded > 0 {
+		klog.V(1).Infof("rule %q excluded %d items via exclude", t.ID, excluded)
+	}
+
+	if t.collection != nil && usesSLA(t.Filters) {
+		rcs = slaMatch(t.collection, rcs, t.Filters, p.now())
+	}
+
+	// Within a collection, rules are sampled once duplicates are known, by sampleResult
+	if t.Sample > 0 && t.collection == nil {
+		rcs = sampleItems(rcs, t.Sample, sampleSeed(p.now()))
+		klog.V(1).Infof("rule %q sampled %d items", t.ID, len(rcs))
+	}
+	p.addHeat(rcs)
 	rr := SummarizeRuleResult(t, rcs, seen)
 	rr.OldestInput = oldest
+	rr.Duration = time.Since(start)
+	rr.Hidden = hidden
+	rr.Excluded = excluded
+	rr.Denied = denied
 	return rr, nil
 }
 
+// searchQuery returns the search query for a rule, scoped to its item type
+func searchQuery(t Rule) string {
+	switch t.Type {
+	case hubbub.Issue:
+		return t.Search + " is:issue"
+	case hubbub.PullRequest:
+		return t.Search + " is:pr"
+	default:
+		return t.Search
+	}
+}
+
+// ruleDefs returns the rules as configured, which Reload may replace
+func (p *Party) ruleDefs() map[string]Rule {
+	p.reposMu.RLock()
+	defer p.reposMu.RUnlock()
+	return p.rules
+}
+
 // Return a fully resolved rule
 func (p *Party) LookupRule(id string) (Rule, error) {
-	t, ok := p.rules[id]
+	t, ok := p.ruleDefs()[id]
 	if !ok {
 		return t, fmt.Errorf("rule %q is undefined - typo?", id)
 	}
 	t.ID = id
-	if len(p.reposOverride) > 0 {
-		t.Repos = p.reposOverride
+	if o := p.overrideRepos(); len(o) > 0 {
+		t.Repos = o
 	}
 
 	if len(t.Repos) == 0 {
-		t.Repos = p.settings.Repos
+		t.Repos = p.defaultRepos()
 	}
 	return t, nil
 }
@@ -175,7 +319,7 @@ func (p *Party) LookupRule(id string) (Rule, error) {
 // ListRules fully resolved rules
 func (p *Party) ListRules() ([]Rule, error) {
 	ts := []Rule{}
-	for k := range p.rules {
+	for k := range p.ruleDefs() {
 		s, err := p.LookupRule(k)
 		if err != nil {
 			return ts, err
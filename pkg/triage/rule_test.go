@@ -15,8 +15,18 @@
 package triage
 
 import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/google/triage-party/pkg/hubbub"
+	"github.com/google/triage-party/pkg/persist"
+	"github.com/google/triage-party/pkg/provider"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -47,3 +57,169 @@ func TestParseRepo(t *testing.T) {
 	assert.Equal(t, repo, r.Project)
 	assert.Equal(t, group, r.Group)
 }
+
+func TestSampleItems(t *testing.T) {
+	cs := []*hubbub.Conversation{}
+	for i := 0; i < 20; i++ {
+		cs = append(cs, &hubbub.Conversation{URL: fmt.Sprintf("https://github.com/org/repo/issues/%d", i)})
+	}
+
+	a := sampleItems(cs, 5, "2020-06-01")
+	assert.Len(t, a, 5)
+	assert.Equal(t, a, sampleItems(cs, 5, "2020-06-01"))
+	assert.NotEqual(t, a, sampleItems(cs, 5, "2020-06-02"))
+	assert.Equal(t, cs, sampleItems(cs, 30, "2020-06-01"))
+
+	// Items shown by an earlier rule are not sampled by a deduplicated rule
+	seen := map[string]*Rule{}
+	for _, c := range cs[:15] {
+		seen[c.URL] = &Rule{ID: "earlier"}
+	}
+	rr := sampleResult(&RuleResult{Rule: Rule{ID: "r", Sample: 5}, Items: cs, Dedup: true}, seen, "2020-06-01")
+	assert.Equal(t, cs[15:], rr.Items)
+	assert.True(t, rr.Dedup)
+}
+
+func TestProcessRulePreservesOptions(t *testing.T) {
+	r, err := processRule(Rule{ID: "r", Type: "pr", MinItems: 3, ChangedSinceLastRefresh: true})
+	assert.Nil(t, err)
+	assert.Equal(t, hubbub.PullRequest, r.Type)
+	assert.Equal(t, 3, r.MinItems)
+	assert.True(t, r.ChangedSinceLastRefresh)
+}
+
+func TestExcludeBaseRefs(t *testing.T) {
+	rules := map[string]Rule{
+		"prs":      {Type: "pr"},
+		"issues":   {Type: "issue"},
+		"releases": {Filters: []provider.Filter{{RawBaseRef: "release-.*"}}},
+		"all":      {AllBaseRefs: true},
+	}
+
+	got := excludeBaseRefs(rules, []string{"!default", "wip"})
+	assert.Equal(t, []provider.Filter{{RawBaseRef: "default"}, {RawBaseRef: "!wip"}}, got["prs"].Filters)
+	assert.Empty(t, got["issues"].Filters)
+	assert.Len(t, got["releases"].Filters, 1)
+	assert.Empty(t, got["all"].Filters)
+
+	assert.Error(t, loadBaseRefExclusions([]string{"release-("}))
+}
+
+func TestFetchOrder(t *testing.T) {
+	a, b := "https://github.com/org/a", "https://github.com/org/b"
+	rules := []Rule{
+		{ID: "closed-issues", Type: hubbub.Issue, Repos: []string{a}, Filters: []provider.Filter{{State: "closed"}}},
+		{ID: "issues-b", Type: hubbub.Issue, Repos: []string{b}},
+		{ID: "issues-a", Type: hubbub.Issue, Repos: []string{a}},
+		{ID: "prs", Type: hubbub.PullRequest, Repos: []string{b}},
+	}
+
+	p := &Party{settings: Settings{Repos: []string{a, b}}}
+	assert.Equal(t, []int{0, 1, 2, 3}, p.fetchOrder(rules))
+
+	p.settings.FetchOrder = []string{FetchByState, FetchByType, FetchByRepo}
+	assert.Equal(t, []int{3, 2, 1, 0}, p.fetchOrder(rules))
+	assert.Equal(t, []string{a, b}, p.orderRepos([]string{b, a}))
+
+	assert.Error(t, loadFetchOrder([]string{"size"}))
+	assert.Error(t, loadFetchOrder([]string{FetchByState, FetchByState}))
+}
+
+// listProvider records which lists are fetched, returning nothing
+type listProvider struct {
+	provider.Provider
+	lists []string
+}
+
+func (l *listProvider) IssuesListByRepo(_ context.Context, sp provider.SearchParams) ([]*provider.Issue, *provider.Response, error) {
+	l.lists = append(l.lists, "issues-"+sp.State)
+	return nil, &provider.Response{}, nil
+}
+
+func (l *listProvider) PullRequestsList(_ context.Context, sp provider.SearchParams) ([]*provider.PullRequest, *provider.Response, error) {
+	l.lists = append(l.lists, "prs-"+sp.State)
+	return nil, &provider.Response{}, nil
+}
+
+func TestPrefetch(t *testing.T) {
+	c, err := persist.NewMemory(persist.Config{})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	assert.Nil(t, c.Initialize())
+
+	l := &listProvider{Provider: provider.Offline()}
+	p, err := New(Config{Cache: c, Provider: l})
+	if err != nil {
+		t.Fatalf("New() = %v", err)
+	}
+	config := `settings:
+  repos:
+    - https://github.com/org/project
+  fetch_order: [state]
+collections:
+  - id: closed
+    rules: [closed-issues]
+  - id: open
+    rules: [open-prs]
+rules:
+  closed-issues:
+    type: issue
+    filters:
+      - state: closed
+  open-prs:
+    type: pull_request
+    filters:
+      - state: open
+`
+	if err := p.Load(strings.NewReader(config)); err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+
+	sts, err := p.ListCollections()
+	if err != nil {
+		t.Fatalf("ListCollections() = %v", err)
+	}
+
+	// Open items of a later collection are fetched before closed items of an earlier one
+	p.Prefetch(context.Background(), sts, time.Time{})
+	assert.NotEmpty(t, l.lists)
+	assert.Equal(t, "prs-open", l.lists[0])
+}
+
+func TestReloadRepos(t *testing.T) {
+	dir, err := ioutil.TempDir("", "repos")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	a, b := "https://github.com/org/a", "https://github.com/org/b"
+	path := filepath.Join(dir, "repos.txt")
+	if err := ioutil.WriteFile(path, []byte("# generated\n\n"+b+" # team b\n"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	p := &Party{configDir: dir, inlineRepos: []string{a}, settings: Settings{ReposFile: "repos.txt"}}
+	assert.NoError(t, p.ReloadRepos())
+	assert.Equal(t, []string{a, b}, p.defaultRepos())
+
+	if err := ioutil.WriteFile(path, []byte("not a repo\n"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	assert.Error(t, p.ReloadRepos())
+	assert.Equal(t, []string{a, b}, p.defaultRepos())
+}
+
+func TestParseItemURL(t *testing.T) {
+	sp, err := parseItemURL("https://gitlab.com/org/group/project/-/merge_requests/12")
+	assert.Nil(t, err)
+	assert.Equal(t, provider.Repo{Host: "gitlab.com", Organization: "org", Group: "group", Project: "project"}, sp.Repo)
+	assert.Equal(t, 12, sp.IssueNumber)
+	assert.True(t, sp.PullRequest)
+
+	sp, err = parseItemURL("https://github.com/org/project/issues/3")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, sp.IssueNumber)
+	assert.False(t, sp.PullRequest)
+}
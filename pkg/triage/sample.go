@@ -0,0 +1,77 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"hash/fnv"
+	"sort"
+	"time"
+
+	"github.com/google/triage-party/pkg/hubbub"
+	"k8s.io/klog/v2"
+)
+
+// sampleSeed returns the seed for today's sample, so that everyone sees the same items for a day
+func sampleSeed(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+// sampleItems deterministically selects n items based on a seed, preserving their original order
+func sampleItems(cs []*hubbub.Conversation, n int, seed string) []*hubbub.Conversation {
+	if n <= 0 || len(cs) <= n {
+		return cs
+	}
+
+	weight := map[*hubbub.Conversation]uint64{}
+	order := map[*hubbub.Conversation]int{}
+	for i, c := range cs {
+		h := fnv.New64a()
+		h.Write([]byte(seed + c.URL))
+		weight[c] = h.Sum64()
+		order[c] = i
+	}
+
+	picked := make([]*hubbub.Conversation, len(cs))
+	copy(picked, cs)
+	sort.Slice(picked, func(i, j int) bool { return weight[picked[i]] < weight[picked[j]] })
+
+	picked = picked[:n]
+	sort.Slice(picked, func(i, j int) bool { return order[picked[i]] < order[picked[j]] })
+	return picked
+}
+
+// sampleResult samples a rule result within a collection. If the rule is deduplicated, items shown by earlier
+// rules are not sampled, so that the rule still shows its sample size.
+func sampleResult(rr *RuleResult, seen map[string]*Rule, seed string) *RuleResult {
+	cs := rr.Items
+	if rr.Dedup {
+		cs = []*hubbub.Conversation{}
+		for _, c := range rr.Items {
+			if seen[c.URL] == nil {
+				cs = append(cs, c)
+			}
+		}
+	}
+
+	sampled := SummarizeRuleResult(rr.Rule, sampleItems(cs, rr.Rule.Sample, seed), nil)
+	sampled.Dedup = rr.Dedup
+	sampled.OldestInput = rr.OldestInput
+	sampled.Duration = rr.Duration
+	sampled.Hidden = rr.Hidden
+	sampled.Excluded = rr.Excluded
+	sampled.Denied = rr.Denied
+	klog.V(1).Infof("rule %q sampled %d of %d items", rr.Rule.ID, len(sampled.Items), len(rr.Items))
+	return sampled
+}
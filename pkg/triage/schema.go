@@ -0,0 +1,73 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// schemaURL is the JSON Schema draft the configuration schema conforms to
+const schemaURL = "http://json-schema.org/draft-07/schema#"
+
+// ConfigSchema returns a JSON Schema for the configuration file, generated from the configuration structs
+func ConfigSchema() ([]byte, error) {
+	s := schemaFor(reflect.TypeOf(diskConfig{}))
+	s["$schema"] = schemaURL
+	s["title"] = "Triage Party configuration"
+	return json.MarshalIndent(s, "", "  ")
+}
+
+// schemaFor returns the schema for a type, using the same field names as the YAML decoder
+func schemaFor(t reflect.Type) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return schemaFor(t.Elem())
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": schemaFor(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": schemaFor(t.Elem())}
+	case reflect.Struct:
+		props := map[string]interface{}{}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+
+			name := strings.ToLower(f.Name)
+			if tag := strings.Split(f.Tag.Get("yaml"), ",")[0]; tag != "" {
+				name = tag
+			}
+			if name == "-" {
+				continue
+			}
+			props[name] = schemaFor(f.Type)
+		}
+		return map[string]interface{}{"type": "object", "properties": props, "additionalProperties": false}
+	default:
+		return map[string]interface{}{}
+	}
+}
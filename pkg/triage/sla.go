@@ -0,0 +1,187 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/triage-party/pkg/hubbub"
+	"github.com/google/triage-party/pkg/provider"
+)
+
+// SLA statuses, as shown on each item and matched by the sla filter
+const (
+	SLAOnTrack  = "on-track"
+	SLAAtRisk   = "at-risk"
+	SLABreached = "breached"
+)
+
+// Ages which an SLA may measure
+const (
+	SLACreatedAge   = "created"
+	SLARespondedAge = "responded"
+	SLAReopenedAge  = "reopened"
+)
+
+// slaAtRisk is the fraction of the SLA after which an item is at risk
+const slaAtRisk = 0.75
+
+// SLAReport summarizes the age of items within a collection against its SLA
+type SLAReport struct {
+	Items     int           `json:"items"`
+	MedianAge time.Duration `json:"median_age"`
+	SLA       time.Duration `json:"sla,omitempty"`
+	Breached  int           `json:"breached"`
+}
+
+// slaDuration returns the SLA configured for a collection, or 0 if unset
+func slaDuration(s *Collection) time.Duration {
+	if s == nil || s.SLA == "" {
+		return 0
+	}
+	d, _, _ := hubbub.ParseDuration(s.SLA)
+	return d
+}
+
+// slaStart returns when the SLA clock started for an item: when it was created, when a
+// member last responded to it if the collection measures responses, or when it was last
+// reopened if the collection measures reopens
+func slaStart(s *Collection, co *hubbub.Conversation) time.Time {
+	if s.SLAAge == SLARespondedAge && co.LatestMemberResponse.After(co.Created) {
+		return co.LatestMemberResponse
+	}
+	if s.SLAAge == SLAReopenedAge {
+		return co.Opened()
+	}
+	return co.Created
+}
+
+// slaStatus returns the SLA status of an item within a collection, or "" if the collection has no SLA
+func slaStatus(s *Collection, co *hubbub.Conversation, now time.Time) string {
+	sla := slaDuration(s)
+	if sla <= 0 {
+		return ""
+	}
+
+	age := now.Sub(slaStart(s, co))
+	switch {
+	case age > sla:
+		return SLABreached
+	case float64(age) > float64(sla)*slaAtRisk:
+		return SLAAtRisk
+	default:
+		return SLAOnTrack
+	}
+}
+
+// validSLAFilter returns an error if an sla filter value is unknown
+func validSLAFilter(v string) error {
+	switch strings.TrimPrefix(v, "!") {
+	case SLAOnTrack, SLAAtRisk, SLABreached:
+		return nil
+	default:
+		return fmt.Errorf("unknown value %q, expected on-track, at-risk, or breached (optionally negated with !)", v)
+	}
+}
+
+// slaMatch returns items which match the sla filters, measured against a collection's SLA
+func slaMatch(s *Collection, cs []*hubbub.Conversation, fs []provider.Filter, now time.Time) []*hubbub.Conversation {
+	matched := []*hubbub.Conversation{}
+	for _, co := range cs {
+		ok := true
+		for _, f := range fs {
+			if f.SLA == "" {
+				continue
+			}
+			want := strings.TrimPrefix(f.SLA, "!")
+			if (slaStatus(s, co, now) == want) == strings.HasPrefix(f.SLA, "!") {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			matched = append(matched, co)
+		}
+	}
+	return matched
+}
+
+// usesSLA returns true if any filter matches on SLA status
+func usesSLA(fs []provider.Filter) bool {
+	for _, f := range fs {
+		if f.SLA != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// summarizeSLA calculates the median age of unique items, and how many have breached the SLA
+func summarizeSLA(s *Collection, os []*RuleResult, now time.Time) *SLAReport {
+	r := &SLAReport{SLA: slaDuration(s)}
+
+	seen := map[string]bool{}
+	ages := []time.Duration{}
+	for _, o := range os {
+		for _, i := range o.Items {
+			if seen[i.URL] {
+				continue
+			}
+			seen[i.URL] = true
+
+			ages = append(ages, now.Sub(i.Created))
+			if slaStatus(s, i, now) == SLABreached {
+				r.Breached++
+			}
+		}
+	}
+
+	r.Items = len(ages)
+	if len(ages) == 0 {
+		return r
+	}
+
+	sort.Slice(ages, func(i, j int) bool { return ages[i] < ages[j] })
+	mid := len(ages) / 2
+	if len(ages)%2 == 1 {
+		r.MedianAge = ages[mid]
+	} else {
+		r.MedianAge = (ages[mid-1] + ages[mid]) / 2
+	}
+	return r
+}
+
+// NewSLABreaches returns items which breached the SLA of a collection between an older and a newer result
+func NewSLABreaches(s *Collection, older *CollectionResult, newer *CollectionResult) []*hubbub.Conversation {
+	if slaDuration(s) <= 0 {
+		return nil
+	}
+
+	before := resultItems(older)
+	breached := []*hubbub.Conversation{}
+	for _, co := range resultItems(newer).items {
+		if slaStatus(s, co, newer.Created) != SLABreached {
+			continue
+		}
+		if prev, ok := before.seen[co.URL]; ok && slaStatus(s, prev, older.Created) == SLABreached {
+			continue
+		}
+		breached = append(breached, co)
+	}
+	return breached
+}
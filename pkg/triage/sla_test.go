@@ -0,0 +1,69 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package triage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/triage-party/pkg/hubbub"
+	"github.com/google/triage-party/pkg/provider"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSLAStatus(t *testing.T) {
+	now := time.Now()
+	day := 24 * time.Hour
+	created := &Collection{SLA: "10d"}
+	responded := &Collection{SLA: "10d", SLAAge: SLARespondedAge}
+
+	fresh := &hubbub.Conversation{URL: "fresh", Created: now.Add(-2 * day)}
+	risky := &hubbub.Conversation{URL: "risky", Created: now.Add(-8 * day)}
+	old := &hubbub.Conversation{URL: "old", Created: now.Add(-30 * day), LatestMemberResponse: now.Add(-1 * day)}
+
+	assert.Equal(t, SLAOnTrack, slaStatus(created, fresh, now))
+	assert.Equal(t, SLAAtRisk, slaStatus(created, risky, now))
+	assert.Equal(t, SLABreached, slaStatus(created, old, now))
+	assert.Equal(t, SLAOnTrack, slaStatus(responded, old, now))
+	assert.Equal(t, "", slaStatus(&Collection{}, old, now))
+
+	reopened := &Collection{SLA: "10d", SLAAge: SLAReopenedAge}
+	bounced := &hubbub.Conversation{URL: "bounced", Created: now.Add(-30 * day), Reopened: now.Add(-3 * day)}
+	assert.Equal(t, SLABreached, slaStatus(created, bounced, now))
+	assert.Equal(t, SLAOnTrack, slaStatus(reopened, bounced, now))
+	assert.Equal(t, SLABreached, slaStatus(reopened, old, now))
+
+	cs := []*hubbub.Conversation{fresh, risky, old}
+	assert.Equal(t, []*hubbub.Conversation{old}, slaMatch(created, cs, []provider.Filter{{SLA: SLABreached}}, now))
+	assert.Equal(t, []*hubbub.Conversation{fresh, risky}, slaMatch(created, cs, []provider.Filter{{SLA: "!breached"}}, now))
+}
+
+func TestNewSLABreaches(t *testing.T) {
+	now := time.Now()
+	day := 24 * time.Hour
+	s := &Collection{SLA: "10d"}
+
+	// Breached before, breached now, and fine in both
+	old := &hubbub.Conversation{URL: "old", Created: now.Add(-30 * day)}
+	crossed := &hubbub.Conversation{URL: "crossed", Created: now.Add(-10*day - time.Hour)}
+	fresh := &hubbub.Conversation{URL: "fresh", Created: now.Add(-2 * day)}
+	late := &hubbub.Conversation{URL: "late", Created: now.Add(-20 * day)}
+
+	older := &CollectionResult{Created: now.Add(-2 * time.Hour), RuleResults: []*RuleResult{{Items: []*hubbub.Conversation{old, crossed, fresh}}}}
+	newer := &CollectionResult{Created: now, RuleResults: []*RuleResult{{Items: []*hubbub.Conversation{old, crossed, fresh, late}}}}
+
+	assert.Equal(t, []*hubbub.Conversation{crossed, late}, NewSLABreaches(s, older, newer))
+	assert.Nil(t, NewSLABreaches(&Collection{}, older, newer))
+}
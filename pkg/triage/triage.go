@@ -16,11 +16,17 @@ package triage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"sync"
 	"time"
 
+	"github.com/google/triage-party/pkg/constants"
 	"github.com/google/triage-party/pkg/provider"
 
 	"github.com/google/triage-party/pkg/hubbub"
@@ -32,22 +38,67 @@ import (
 type Config struct {
 	Cache persist.Cacher
 	Repos []string
+
+	// ReposFile lists additional override repositories, one per line
+	ReposFile string
+
 	// DebugNumber is useful when you want to debug why a single issue is or is-not appearing
 	DebugNumbers []int
 
 	GitHubAPIURL string
 	GitHubToken  string
 	GitLabToken  string
+
+	// GitHubTokens are rotated between as each is rate limited, overriding GitHubToken if set
+	GitHubTokens []string
+
+	// UserAgent is sent with each GitHub API request
+	UserAgent string
+
+	// GitHubConcurrency is the maximum number of in-flight GitHub requests (0 for unlimited)
+	GitHubConcurrency int
+
+	// Transport tunes connection reuse for GitHub API requests
+	Transport provider.Transport
+
+	// Offline serves results purely from the cache, without requiring a token or making API requests
+	Offline bool
+
+	// Now returns the current time, and may be overridden for deterministic tests (default: time.Now)
+	Now func() time.Time
+
+	// Provider replaces the GitHub provider, such as with a fake for tests
+	Provider provider.Provider
 }
 
 type Party struct {
 	engine        *hubbub.Engine
+	runtime       Config
 	settings      Settings
 	collections   []Collection
 	cache         persist.Cacher
 	rules         map[string]Rule
 	reposOverride []string
 	debug         map[int]bool
+	now           func() time.Time
+	itemLinks     []itemLinkTemplate
+	excluded      map[string]bool
+	labelColors   map[string]string
+
+	// providers for additional GitHub hosts, by hostname
+	hosts map[string]provider.Provider
+
+	pools []*assigneePool
+
+	// configDir is the directory of the config file, which repos_file is relative to
+	configDir string
+	// inlineRepos are the repos listed within the config file, excluding repos_file
+	inlineRepos []string
+	// reposMu guards settings.Repos and reposOverride, which ReloadRepos replaces, and the config which Reload replaces
+	reposMu sync.RWMutex
+
+	// httpClient is shared by all GitHub providers, so that connections are reused
+	httpClient *http.Client
 
 	github provider.Provider
 	gitlab provider.Provider
@@ -55,24 +106,57 @@ type Party struct {
 
 func New(cfg Config) (*Party, error) {
 	p := &Party{
+		runtime:       cfg,
 		cache:         cfg.Cache,
 		reposOverride: cfg.Repos,
 		debug:         map[int]bool{},
+		now:           cfg.Now,
+		httpClient:    provider.NewHTTPClient(cfg.Transport),
+	}
+
+	if p.now == nil {
+		p.now = time.Now
 	}
 
 	var err error
-	if cfg.GitLabToken != "" {
+	p.reposOverride, err = withRepoList(cfg.Repos, cfg.ReposFile)
+	if err != nil {
+		return nil, fmt.Errorf("repos file: %w", err)
+	}
+
+	if cfg.GitLabToken != "" && !cfg.Offline {
 		p.gitlab, err = provider.NewGitLab(cfg.GitLabToken)
 		if err != nil {
 			return p, fmt.Errorf("gitlab: %v", err)
 		}
+		p.gitlab = provider.WithRetries(p.gitlab)
 	}
 
-	if cfg.GitHubToken != "" {
-		p.github, err = provider.NewGitHub(context.Background(), cfg.GitHubToken, cfg.GitHubAPIURL)
+	tokens := cfg.GitHubTokens
+	if len(tokens) == 0 && cfg.GitHubToken != "" {
+		tokens = []string{cfg.GitHubToken}
+	}
+	if len(tokens) > 0 {
+		// Enterprise hosts without a token of their own use the first
+		p.runtime.GitHubToken = tokens[0]
+	}
+
+	if len(tokens) > 0 && !cfg.Offline {
+		p.github, err = provider.NewGitHubTokens(provider.WithHTTPClient(context.Background(), p.httpClient), tokens, cfg.GitHubAPIURL, cfg.UserAgent)
 		if err != nil {
 			return p, fmt.Errorf("github: %v", err)
 		}
+		p.github = provider.WithRetries(provider.WithConcurrencyLimit(p.github, cfg.GitHubConcurrency))
+	}
+
+	if cfg.Offline {
+		klog.Warningf("offline mode: results will only be served from the cache")
+		p.github = provider.Offline()
+		p.gitlab = provider.Offline()
+	}
+
+	if cfg.Provider != nil {
+		p.github = cfg.Provider
 	}
 
 	if p.gitlab == nil && p.github == nil {
@@ -94,6 +178,56 @@ type Settings struct {
 	MinSimilarity float64  `yaml:"min_similarity"`
 	MemberRoles   []string `yaml:"member-roles"`
 	Members       []string `yaml:"members"`
+	Bots          []string `yaml:"bots"`
+	HiddenLabels  []string `yaml:"hidden_labels,omitempty"`
+	Exclude       []string `yaml:"exclude,omitempty"`
+
+	SimilarityExcludeBots   bool `yaml:"similarity_exclude_bots,omitempty"`
+	SimilarityExcludeDrafts bool `yaml:"similarity_exclude_drafts,omitempty"`
+	SimilaritySameRepo      bool `yaml:"similarity_same_repo,omitempty"`
+	SimilarityWorkers       int  `yaml:"similarity_workers,omitempty"`
+
+	CountedReactions []string            `yaml:"counted_reactions,omitempty"`
+	LabelAliases     map[string][]string `yaml:"label_aliases,omitempty"`
+
+	ItemLinks []ItemLink `yaml:"item_links,omitempty"`
+
+	Heat HeatWeights `yaml:"heat,omitempty"`
+
+	GitHubHosts []GitHubHost `yaml:"github_hosts,omitempty"`
+
+	AssigneePools []AssigneePool `yaml:"assignee_pools,omitempty"`
+
+	// NonWorkingDays and Holidays are skipped by business day durations, such as 3bd
+	NonWorkingDays []string `yaml:"non_working_days,omitempty"`
+	Holidays       []string `yaml:"holidays,omitempty"`
+
+	// Boards serve subsets of collections under their own paths
+	Boards []Board `yaml:"boards,omitempty"`
+
+	// Limit is how many items each rule shows within a collection, unless overridden (0 for unlimited)
+	Limit int `yaml:"limit,omitempty"`
+
+	// RepoListRefresh is how often the repositories of an owner listed as owner/* are refreshed, such as 6h
+	RepoListRefresh string `yaml:"repo_list_refresh,omitempty"`
+
+	// LabelColors overrides the color labels are shown with, by label name
+	LabelColors map[string]string `yaml:"label_colors,omitempty"`
+
+	// MaxComments limits the comments fetched per item to the most recent, unless a rule's filters need them all
+	MaxComments int `yaml:"max_comments,omitempty"`
+
+	// DuplicateRules is how rule IDs defined more than once are handled: error, last-wins, or merge-filters
+	DuplicateRules string `yaml:"duplicate_rules,omitempty"`
+
+	// ExcludeBaseRefs are base-ref filters for pull requests to leave out of rules, such as release-.* or !default
+	ExcludeBaseRefs []string `yaml:"exclude_base_refs,omitempty"`
+
+	// FetchOrder orders the rules of a collection when fetching, by state, type, or repo
+	FetchOrder []string `yaml:"fetch_order,omitempty"`
+
+	// ReposFile lists additional repositories to query by default, one per line
+	ReposFile string `yaml:"repos_file,omitempty"`
 }
 
 // diskConfig is the on-disk configuration
@@ -101,6 +235,9 @@ type diskConfig struct {
 	Settings       Settings        `yaml:"settings"`
 	RawCollections []Collection    `yaml:"collections"`
 	RawRules       map[string]Rule `yaml:"rules"`
+	Generators     []Generator     `yaml:"generate-collections,omitempty"`
+	// Include lists configuration files whose collections and rules are added to this one
+	Include []string `yaml:"include,omitempty"`
 }
 
 // newEngine configures a new search engine based on our loaded configs
@@ -125,16 +262,27 @@ func (p *Party) newEngine() *hubbub.Engine {
 	}
 
 	hc := hubbub.Config{
-		Cache:              p.cache,
-		Repos:              p.reposOverride,
-		DebugNumbers:       p.debug,
-		MaxClosedUpdateAge: maxClosedUpdateAge,
-		MinSimilarity:      p.settings.MinSimilarity,
-		MemberRoles:        roles,
-		Members:            p.settings.Members,
+		Cache:                   p.cache,
+		Repos:                   p.reposOverride,
+		DebugNumbers:            p.debug,
+		MaxClosedUpdateAge:      maxClosedUpdateAge,
+		MinSimilarity:           p.settings.MinSimilarity,
+		SimilarityExcludeBots:   p.settings.SimilarityExcludeBots,
+		SimilarityExcludeDrafts: p.settings.SimilarityExcludeDrafts,
+		SimilaritySameRepo:      p.settings.SimilaritySameRepo,
+		SimilarityWorkers:       p.settings.SimilarityWorkers,
+		CountedReactions:        p.settings.CountedReactions,
+		LabelAliases:            p.settings.LabelAliases,
+		MemberRoles:             roles,
+		Members:                 p.settings.Members,
+		Bots:                    p.settings.Bots,
+		MaxComments:             p.settings.MaxComments,
+		RepoListMaxAge:          repoListMaxAge(p.settings.RepoListRefresh),
+		Now:                     p.now,
 
 		GitLab: p.gitlab,
 		GitHub: p.github,
+		Hosts:  p.hosts,
 	}
 
 	klog.Infof("New hubbub with config: %+v", hc)
@@ -149,33 +297,159 @@ func (p *Party) Load(r io.Reader) error {
 	}
 	klog.Infof("%d bytes read from config", len(bs))
 
+	file := ""
+	if n, ok := r.(interface{ Name() string }); ok {
+		file = n.Name()
+		p.configDir = filepath.Dir(file)
+	}
+
+	if err := p.load(bs); err != nil {
+		var errs ConfigErrors
+		if errors.As(err, &errs) {
+			errs.locate(file, bs)
+		}
+		return err
+	}
+	return nil
+}
+
+// load parses and validates a YAML config, returning ConfigErrors for all problems found
+func (p *Party) load(bs []byte) error {
 	dc := &diskConfig{}
-	err = yaml.Unmarshal(bs, &dc)
+	if err := yaml.Unmarshal(bs, &dc); err != nil {
+		return decodeErrors(err)
+	}
+
+	mode, err := loadDuplicateRules(dc.Settings.DuplicateRules)
+	if err != nil {
+		return ConfigErrors{}.add("settings.duplicate_rules", err)
+	}
+
+	// Included rules are loaded first, so that the main configuration defines rules last
+	rawRules := map[string]Rule{}
+	included, err := loadIncludes(p.configDir, dc.Include, mode, rawRules, map[string]bool{})
 	if err != nil {
-		return fmt.Errorf("unmarshal: %w", err)
+		return ConfigErrors{}.add("include", err)
 	}
+	if err := loadRules(bs, mode, rawRules); err != nil {
+		return err
+	}
+	if dc.RawRules != nil || len(rawRules) > 0 {
+		dc.RawRules = rawRules
+	}
+	dc.RawCollections = append(dc.RawCollections, included...)
+
+	if len(dc.Generators) > 0 {
+		gcs, grs, err := p.generateCollections(context.Background(), dc.Generators)
+		if err != nil {
+			return ConfigErrors{}.add("generate-collections", err)
+		}
+
+		if dc.RawRules == nil {
+			dc.RawRules = map[string]Rule{}
+		}
+
+		for id, r := range grs {
+			if _, ok := dc.RawRules[id]; ok {
+				return ConfigErrors{}.add("rules."+id, fmt.Errorf("conflicts with a generated rule"))
+			}
+			dc.RawRules[id] = r
+		}
+
+		ids := map[string]bool{}
+		for _, c := range dc.RawCollections {
+			ids[c.ID] = true
+		}
+		for _, c := range gcs {
+			if ids[c.ID] {
+				return ConfigErrors{}.add("collections."+c.ID, fmt.Errorf("conflicts with a generated collection"))
+			}
+		}
+		dc.RawCollections = append(dc.RawCollections, gcs...)
+	}
+
+	dc.RawCollections, dc.RawRules = removeDisabled(dc.RawCollections, dc.RawRules)
 
 	if len(dc.RawCollections) == 0 {
-		return fmt.Errorf("no collections found after unmarshal")
+		return ConfigErrors{}.add("collections", fmt.Errorf("no collections found after unmarshal"))
 	}
 
 	if len(dc.RawRules) == 0 {
-		return fmt.Errorf("no rules found after unmarshal")
+		return ConfigErrors{}.add("rules", fmt.Errorf("no rules found after unmarshal"))
 	}
 
-	rules, err := processRules(dc.RawRules)
-	if err != nil {
-		return fmt.Errorf("rule processing: %w", err)
+	var errs ConfigErrors
+	errs = errs.add("settings.exclude_base_refs", loadBaseRefExclusions(dc.Settings.ExcludeBaseRefs))
+	errs = errs.add("settings.fetch_order", loadFetchOrder(dc.Settings.FetchOrder))
+	rules, err := processRules(excludeBaseRefs(dc.RawRules, dc.Settings.ExcludeBaseRefs))
+	errs = errs.add("rules", err)
+
+	for i, c := range dc.RawCollections {
+		key := "collections." + c.ID
+		dc.RawCollections[i].Type, err = itemType(c.Type)
+		errs = errs.add(key, err)
+		errs = errs.add(key, dc.RawCollections[i].loadAgeFilters())
+		errs = errs.add(key+".count_filter", dc.RawCollections[i].loadCountFilter())
+		errs = errs.add(key, dc.RawCollections[i].loadLimit())
+		errs = errs.add(key+".pinned", dc.RawCollections[i].loadPins())
+		errs = errs.add(key+".access", loadAccess(c.Access))
+	}
+
+	links, err := loadItemLinks(dc.Settings.ItemLinks)
+	errs = errs.add("settings.item_links", err)
+
+	excluded, err := loadExcludes(dc.Settings.Exclude)
+	errs = errs.add("settings.exclude", err)
+
+	labelColors, err := loadLabelColors(dc.Settings.LabelColors)
+	errs = errs.add("settings.label_colors", err)
+
+	if s := dc.Settings.RepoListRefresh; s != "" && repoListMaxAge(s) <= 0 {
+		errs = errs.add("settings.repo_list_refresh", fmt.Errorf("unable to parse %q as a duration", s))
+	}
+
+	hosts, err := p.loadGitHubHosts(dc.Settings.GitHubHosts)
+	errs = errs.add("settings.github_hosts", err)
+
+	pools, err := loadAssigneePools(dc.Settings.AssigneePools)
+	errs = errs.add("settings.assignee_pools", err)
+
+	cal, err := hubbub.NewBusinessCalendar(dc.Settings.NonWorkingDays, dc.Settings.Holidays)
+	errs = errs.add("settings.holidays", err)
+
+	errs = errs.add("settings.boards", loadBoards(dc.Settings.Boards, dc.RawCollections))
+
+	for _, r := range dc.Settings.CountedReactions {
+		if !hubbub.IsReaction(r) {
+			errs = errs.add("settings.counted_reactions", fmt.Errorf("unknown reaction %q", r))
+		}
+	}
+
+	inline := dc.Settings.Repos
+	if dc.Settings.ReposFile != "" {
+		dc.Settings.Repos, err = withRepoList(inline, p.relativePath(dc.Settings.ReposFile))
+		errs = errs.add("settings.repos_file", err)
+	}
+
+	if len(errs) > 0 {
+		return errs
 	}
 
 	p.collections = dc.RawCollections
 	p.rules = rules
+	p.inlineRepos = inline
 	p.settings = dc.Settings
+	p.itemLinks = links
+	p.excluded = excluded
+	p.labelColors = labelColors
+	p.hosts = hosts
+	p.pools = pools
 
 	p.logLoaded()
 	if err := p.validateLoadedConfig(); err != nil {
-		return fmt.Errorf("validate config: %w", err)
+		return err
 	}
+	hubbub.SetBusinessCalendar(cal)
 	p.engine = p.newEngine()
 	return nil
 }
@@ -188,7 +462,7 @@ func closedAge(fs []provider.Filter) time.Duration {
 	}
 
 	for _, f := range fs {
-		for _, fd := range []string{f.Created, f.Updated, f.Closed, f.Responded} {
+		for _, fd := range []string{f.Created, f.Updated, f.Closed, f.Responded, f.HumanActivity, f.AgeInState, f.Opened} {
 			if fd == "" {
 				continue
 			}
@@ -212,12 +486,13 @@ func closedAge(fs []provider.Filter) time.Duration {
 	return oldest
 }
 
+// validateLoadedConfig checks the loaded configuration, returning ConfigErrors for all problems found
 func (p *Party) validateLoadedConfig() error {
 	if len(p.collections) == 0 {
-		return fmt.Errorf("no 'collections' defined")
+		return ConfigErrors{}.add("collections", fmt.Errorf("no 'collections' defined"))
 	}
 	if len(p.rules) == 0 {
-		return fmt.Errorf("no 'rules' defined")
+		return ConfigErrors{}.add("rules", fmt.Errorf("no 'rules' defined"))
 	}
 
 	cols, err := p.ListCollections()
@@ -225,18 +500,46 @@ func (p *Party) validateLoadedConfig() error {
 		return fmt.Errorf("list collections: %w", err)
 	}
 
+	var errs ConfigErrors
 	filters := 0
+	badRules := map[string]bool{}
 	for _, c := range cols {
+		key := "collections." + c.ID
+		if c.SLA != "" && slaDuration(&c) <= 0 {
+			errs = errs.add(key, fmt.Errorf("invalid sla: %q", c.SLA))
+		}
+		if c.SLAAge != "" && c.SLAAge != SLACreatedAge && c.SLAAge != SLARespondedAge && c.SLAAge != SLAReopenedAge {
+			errs = errs.add(key, fmt.Errorf("invalid sla_age: %q, expected created, responded, or reopened", c.SLAAge))
+		}
+		if err := validateRefreshBounds(&c); err != nil {
+			errs = errs.add(key, err)
+		}
+
 		seenRule := map[string]*Rule{}
 
 		for _, tid := range c.RuleIDs {
 			if seenRule[tid] != nil {
-				return fmt.Errorf("%q has a duplicate rule: %q", c.ID, tid)
+				errs = errs.add(key, fmt.Errorf("duplicate rule: %q", tid))
+				continue
 			}
 
 			r, err := p.LookupRule(tid)
 			if err != nil {
-				return fmt.Errorf("lookup rule %q: %w", tid, err)
+				errs = errs.add(key, fmt.Errorf("lookup rule %q: %w", tid, err))
+				continue
+			}
+
+			if c.Type != "" && r.Type != "" && c.Type != r.Type {
+				errs = errs.add(key, fmt.Errorf("only shows %s items, but rule %q is for %s items", c.Type, tid, r.Type))
+			}
+
+			if usesSLA(r.Filters) && c.SLA == "" {
+				errs = errs.add(key, fmt.Errorf("rule %q uses an sla filter, but the collection has no sla", tid))
+			}
+
+			if r.Sample < 0 && !badRules[tid] {
+				errs = errs.add("rules."+tid, fmt.Errorf("negative sample: %d", r.Sample))
+				badRules[tid] = true
 			}
 
 			seenRule[tid] = &r
@@ -244,23 +547,31 @@ func (p *Party) validateLoadedConfig() error {
 		}
 	}
 
-	if filters == 0 {
-		return fmt.Errorf("No 'filters' found in the configuration")
+	if filters == 0 && len(errs) == 0 {
+		errs = errs.add("rules", fmt.Errorf("No 'filters' found in the configuration"))
 	}
 
 	// validate that requested repos map to known providers
-	repos := p.settings.Repos
-	if len(p.reposOverride) > 0 {
-		repos = p.reposOverride
+	repos := p.defaultRepos()
+	if o := p.overrideRepos(); len(o) > 0 {
+		repos = o
+	}
+
+	for _, c := range cols {
+		repos = append(repos, c.Repos...)
 	}
 
 	for _, repo := range repos {
 		_, err := parseRepo(repo)
 		if err != nil {
-			return fmt.Errorf("invalid repo URL %q", repo)
+			errs = errs.add("settings.repos", fmt.Errorf("invalid repo URL %q", repo))
 		}
 	}
 
+	if len(errs) > 0 {
+		return errs
+	}
+
 	klog.Infof("configuration defines %d filters - looking good!", filters)
 	return nil
 }
@@ -285,65 +596,231 @@ func (p *Party) logLoaded() {
 	klog.V(2).Infof("Loaded Rules:\n%s", s)
 }
 
-// processRules precaches regular expressions
+// processRules precaches regular expressions, returning ConfigErrors for every invalid rule
 func processRules(raw map[string]Rule) (map[string]Rule, error) {
 	rules := map[string]Rule{}
+	var errs ConfigErrors
+
+	ids := []string{}
+	for id := range raw {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		r, err := processRule(raw[id])
+		if err != nil {
+			errs = append(errs, &ConfigError{Key: "rules." + id, Err: err})
+			r = raw[id]
+		}
+		rules[id] = r
+	}
+
+	if len(errs) > 0 {
+		return rules, errs
+	}
+	return rules, nil
+}
+
+// processRule precaches the regular expressions for a single rule
+func processRule(t Rule) (Rule, error) {
+	newfs := []provider.Filter{}
+
+	rt, err := itemType(t.Type)
+	if err != nil {
+		return t, err
+	}
+
+	for _, f := range t.Filters {
+		if f.RawLabel != "" {
+			err := f.LoadLabelRegex()
+			if err != nil {
+				return t, fmt.Errorf("label: %w", err)
+			}
+		}
 
-	for id, t := range raw {
-		rules[id] = t
-		newfs := []provider.Filter{}
+		if f.RawTag != "" {
+			err := f.LoadTagRegex()
+			if err != nil {
+				return t, fmt.Errorf("tag: %w", err)
+			}
+		}
 
-		for _, f := range raw[id].Filters {
-			if f.RawLabel != "" {
-				err := f.LoadLabelRegex()
-				if err != nil {
-					return rules, fmt.Errorf("%q label: %w", id, err)
-				}
+		if f.RawTitle != "" {
+			err := f.LoadTitleRegex()
+			if err != nil {
+				return t, fmt.Errorf("title: %w", err)
 			}
+		}
 
-			if f.RawTag != "" {
-				err := f.LoadTagRegex()
-				if err != nil {
-					return rules, fmt.Errorf("%q tag: %w", id, err)
-				}
+		if f.RawBodyMatches != "" || f.RawBodyMissing != "" {
+			err := f.LoadBodyRegex()
+			if err != nil {
+				return t, err
 			}
+		}
+
+		if f.RawSection != "" {
+			if err := f.LoadSectionRegex(); err != nil {
+				return t, fmt.Errorf("section: %w", err)
+			}
+		}
+
+		if f.RawBaseRef != "" {
+			if err := f.LoadBaseRefRegex(); err != nil {
+				return t, fmt.Errorf("base-ref: %w", err)
+			}
+		}
+
+		if f.RawMilestone != "" {
+			err := f.LoadMilestoneRegex()
+			if err != nil {
+				return t, fmt.Errorf("milestone: %w", err)
+			}
+		}
 
-			if f.RawTitle != "" {
-				err := f.LoadTitleRegex()
-				if err != nil {
-					return rules, fmt.Errorf("%q title: %w", id, err)
-				}
+		if f.RawNumber != "" {
+			err := f.LoadNumbers()
+			if err != nil {
+				return t, fmt.Errorf("number: %w", err)
 			}
+		}
+
+		if f.MilestoneState != "" && f.MilestoneState != constants.OpenState && f.MilestoneState != constants.ClosedState {
+			return t, fmt.Errorf("milestone-state: unknown value %q, expected open or closed", f.MilestoneState)
+		}
+
+		if f.Review != "" && !hubbub.IsReviewDecision(f.Review) {
+			return t, fmt.Errorf("review: unknown value %q, expected approved, changes-requested, or review-required", f.Review)
+		}
+
+		if f.Mergeable != "" && f.Mergeable != hubbub.MergeableTrue && f.Mergeable != hubbub.MergeableFalse && f.Mergeable != hubbub.MergeableUnknown {
+			return t, fmt.Errorf("mergeable: unknown value %q, expected true, false, or unknown", f.Mergeable)
+		}
+
+		if f.NeedsRebase != "" && f.NeedsRebase != hubbub.MergeableTrue && f.NeedsRebase != hubbub.MergeableFalse && f.NeedsRebase != hubbub.MergeableUnknown {
+			return t, fmt.Errorf("needs-rebase: unknown value %q, expected true, false, or unknown", f.NeedsRebase)
+		}
+
+		if f.Locked != "" && f.Locked != "true" && f.Locked != "false" {
+			return t, fmt.Errorf("locked: unknown value %q, expected true or false", f.Locked)
+		}
+
+		if f.ClosesIssue != "" && f.ClosesIssue != "true" && f.ClosesIssue != "false" {
+			return t, fmt.Errorf("closes-issue: unknown value %q, expected true or false", f.ClosesIssue)
+		}
+
+		if f.Discussion != "" && f.Discussion != "true" && f.Discussion != "false" {
+			return t, fmt.Errorf("discussion: unknown value %q, expected true or false", f.Discussion)
+		}
+
+		if f.MissingRequiredChecks != "" && f.MissingRequiredChecks != "true" && f.MissingRequiredChecks != "false" {
+			return t, fmt.Errorf("missing-required-checks: unknown value %q, expected true or false", f.MissingRequiredChecks)
+		}
+
+		if f.TasksComplete != "" && f.TasksComplete != "true" && f.TasksComplete != "false" {
+			return t, fmt.Errorf("tasks-complete: unknown value %q, expected true or false", f.TasksComplete)
+		}
+
+		if f.TasksRemaining != "" && !hubbub.ValidRange(f.TasksRemaining) {
+			return t, fmt.Errorf("tasks-remaining: unknown value %q, expected a range such as >0", f.TasksRemaining)
+		}
+
+		if f.References != "" && !hubbub.ValidReference(f.References) {
+			return t, fmt.Errorf("references: unknown value %q, expected a number, org/project#number, or an issue or PR URL", f.References)
+		}
+
+		if f.Size != "" && !hubbub.ValidSize(f.Size) {
+			return t, fmt.Errorf("size: unknown value %q, expected a range such as <50, or XS, S, M, L, XL, or XXL", f.Size)
+		}
 
-			if f.RawMilestone != "" {
-				err := f.LoadMilestoneRegex()
-				if err != nil {
-					return rules, fmt.Errorf("%q milestone: %w", id, err)
-				}
+		if f.SLA != "" {
+			if err := validSLAFilter(f.SLA); err != nil {
+				return t, fmt.Errorf("sla: %w", err)
 			}
+		}
 
-			newfs = append(newfs, f)
+		if f.AuthorType != "" && f.AuthorType != provider.AuthorTypeBot && f.AuthorType != provider.AuthorTypeUser {
+			return t, fmt.Errorf("author-type: unknown value %q, expected bot or user", f.AuthorType)
 		}
 
-		rules[id] = Rule{
-			ID:         t.ID,
-			Resolution: t.Resolution,
-			Name:       t.Name,
-			Repos:      t.Repos,
-			Type:       t.Type,
-			Filters:    newfs,
+		if f.Awaiting != "" && f.Awaiting != provider.AwaitingReporter && f.Awaiting != provider.AwaitingMaintainer {
+			return t, fmt.Errorf("awaiting: unknown value %q", f.Awaiting)
 		}
+
+		newfs = append(newfs, f)
 	}
 
-	return rules, nil
+	t.Type = rt
+	t.Filters = newfs
+	return t, nil
+}
+
+// redacted replaces a secret with a placeholder, preserving whether it was set
+func redacted(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "<redacted>"
+}
+
+// EffectiveConfig returns the fully resolved configuration the party is running with, as YAML.
+// Only the given collections, the rules they show, and the given boards are included.
+func (p *Party) EffectiveConfig(sts []Collection, boards []Board) ([]byte, error) {
+	rules := map[string]Rule{}
+	for _, s := range sts {
+		for _, id := range s.RuleIDs {
+			r, err := p.LookupRule(id)
+			if err != nil {
+				return nil, err
+			}
+			rules[id] = r
+		}
+	}
+
+	p.reposMu.RLock()
+	settings := p.settings
+	if len(p.reposOverride) > 0 {
+		settings.Repos = p.reposOverride
+	}
+	settings.Boards = boards
+	p.reposMu.RUnlock()
+
+	ec := struct {
+		Runtime     map[string]interface{} `yaml:"runtime"`
+		Settings    Settings               `yaml:"settings"`
+		Collections []Collection           `yaml:"collections"`
+		Rules       map[string]Rule        `yaml:"rules"`
+	}{
+		Runtime: map[string]interface{}{
+			"cache":          p.cache.String(),
+			"debug-numbers":  p.runtime.DebugNumbers,
+			"github-api-url": p.runtime.GitHubAPIURL,
+			"github-token":   redacted(p.runtime.GitHubToken),
+			"gitlab-token":   redacted(p.runtime.GitLabToken),
+			"user-agent":     p.runtime.UserAgent,
+		},
+		Settings:    settings,
+		Collections: sts,
+		Rules:       rules,
+	}
+
+	return yaml.Marshal(ec)
 }
 
 // ConversationsTotal returns the number of conversations we've seen so far
 func (p *Party) ConversationsTotal() int {
-	return p.engine.ConversationsTotal()
+	return p.loadedEngine().ConversationsTotal()
+}
+
+// LookupItem returns the conversation last seen for an item URL, or nil if it has not been seen
+func (p *Party) LookupItem(url string) *hubbub.Conversation {
+	return p.loadedEngine().Lookup(url)
 }
 
 // Name returns the configured site name
 func (p *Party) Name() string {
+	p.reposMu.RLock()
+	defer p.reposMu.RUnlock()
 	return p.settings.Name
 }
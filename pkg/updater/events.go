@@ -0,0 +1,58 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package updater
+
+import (
+	"github.com/google/triage-party/pkg/events"
+	"github.com/google/triage-party/pkg/hubbub"
+	"github.com/google/triage-party/pkg/triage"
+)
+
+// collectionEvents returns the events between the previous and latest results of a collection
+func collectionEvents(s *triage.Collection, prev *triage.CollectionResult, r *triage.CollectionResult) []events.Event {
+	if r == nil {
+		return nil
+	}
+
+	evs := []events.Event{}
+	if r.SLA != nil && r.SLA.SLA > 0 && (prev == nil || prev.SLA == nil || prev.SLA.Breached != r.SLA.Breached) {
+		evs = append(evs, events.Event{Type: events.SLAStatus, Time: r.Created, Collection: s.ID, Breached: r.SLA.Breached})
+	}
+
+	if prev == nil {
+		return evs
+	}
+
+	add := func(kind string, cs []*hubbub.Conversation) {
+		for _, co := range cs {
+			evs = append(evs, events.Event{
+				Type:         kind,
+				Time:         r.Created,
+				Collection:   s.ID,
+				URL:          co.URL,
+				Organization: co.Organization,
+				Project:      co.Project,
+				ID:           co.ID,
+				Title:        co.Title,
+			})
+		}
+	}
+
+	c := triage.CompareCollectionResults(prev, r)
+	add(events.ItemEntered, c.Added)
+	add(events.ItemLeft, c.Removed)
+	add(events.SLABreached, triage.NewSLABreaches(s, prev, r))
+	return evs
+}
@@ -0,0 +1,67 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package updater
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/triage-party/pkg/events"
+	"github.com/google/triage-party/pkg/hubbub"
+	"github.com/google/triage-party/pkg/triage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectionEvents(t *testing.T) {
+	now := time.Now()
+	day := 24 * time.Hour
+	s := &triage.Collection{ID: "soup", SLA: "10d"}
+
+	kept := &hubbub.Conversation{URL: "kept", ID: 1, Created: now.Add(-10*day - time.Hour)}
+	gone := &hubbub.Conversation{URL: "gone", ID: 2, Created: now.Add(-2 * day)}
+	added := &hubbub.Conversation{URL: "added", ID: 3, Created: now.Add(-1 * day)}
+
+	prev := &triage.CollectionResult{
+		Created:     now.Add(-2 * time.Hour),
+		RuleResults: []*triage.RuleResult{{Items: []*hubbub.Conversation{kept, gone}}},
+		SLA:         &triage.SLAReport{SLA: 10 * day},
+	}
+	r := &triage.CollectionResult{
+		Created:     now,
+		RuleResults: []*triage.RuleResult{{Items: []*hubbub.Conversation{kept, added}}},
+		SLA:         &triage.SLAReport{SLA: 10 * day, Breached: 1},
+	}
+
+	got := map[string][]string{}
+	for _, ev := range collectionEvents(s, prev, r) {
+		assert.Equal(t, "soup", ev.Collection)
+		got[ev.Type] = append(got[ev.Type], ev.URL)
+	}
+	assert.Equal(t, map[string][]string{
+		events.SLAStatus:   {""},
+		events.ItemEntered: {"added"},
+		events.ItemLeft:    {"gone"},
+		events.SLABreached: {"kept"},
+	}, got)
+
+	// The first result has nothing to compare against, so only the SLA status is sent
+	first := collectionEvents(s, nil, r)
+	assert.Len(t, first, 1)
+	assert.Equal(t, events.SLAStatus, first[0].Type)
+	assert.Equal(t, 1, first[0].Breached)
+
+	assert.Empty(t, collectionEvents(s, r, r))
+	assert.Nil(t, collectionEvents(s, prev, nil))
+}
@@ -0,0 +1,119 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package updater
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/triage-party/pkg/hubbub"
+	"github.com/google/triage-party/pkg/provider"
+	"github.com/google/triage-party/pkg/triage"
+
+	"k8s.io/klog/v2"
+)
+
+// historyKey is the cache key of a retained result, by collection ID and position
+func historyKey(id string, n int) string {
+	return fmt.Sprintf("history-%s-%d", id, n)
+}
+
+// saveHistory writes the retained results of a collection to the cache, so that they survive restarts
+func (u *Updater) saveHistory(id string, hs []*triage.CollectionResult) {
+	if u.historyCache == nil {
+		return
+	}
+
+	for n, r := range hs {
+		if err := u.historyCache.Set(historyKey(id, n), historyThing(r)); err != nil {
+			klog.Errorf("save history for %s: %v", id, err)
+			return
+		}
+	}
+}
+
+// loadHistory reads the retained results of a collection from the cache, once. cacheMu must be held.
+func (u *Updater) loadHistory(id string) {
+	if u.historyCache == nil || u.historyLoaded[id] {
+		return
+	}
+	u.historyLoaded[id] = true
+
+	hs := []*triage.CollectionResult{}
+	for n := 0; n < maxHistory; n++ {
+		th := u.historyCache.GetNewerThan(historyKey(id, n), time.Time{})
+		if th == nil {
+			break
+		}
+		hs = append(hs, historyResult(th))
+	}
+
+	if len(hs) > 0 {
+		klog.Infof("loaded %d previous results for %s from the cache", len(hs), id)
+		u.history[id] = append(hs, u.history[id]...)
+	}
+}
+
+// historyThing stores the items of a result as issues, keeping only what the changes view shows
+func historyThing(r *triage.CollectionResult) *provider.Thing {
+	th := &provider.Thing{Created: r.Created}
+	seen := map[string]bool{}
+	for _, rr := range r.RuleResults {
+		for _, co := range rr.Items {
+			if seen[co.URL] {
+				continue
+			}
+			seen[co.URL] = true
+
+			url, id, title := co.URL, co.ID, co.Title
+			created, updated := co.Created, co.Updated
+			th.Issues = append(th.Issues, &provider.Issue{
+				HTMLURL:   &url,
+				Number:    &id,
+				Title:     &title,
+				User:      co.Author,
+				Assignees: co.Assignees,
+				Labels:    co.Labels,
+				CreatedAt: &created,
+				UpdatedAt: &updated,
+			})
+		}
+	}
+	return th
+}
+
+// historyResult restores a result stored by historyThing, as a single rule result
+func historyResult(th *provider.Thing) *triage.CollectionResult {
+	rr := &triage.RuleResult{}
+	for _, i := range th.Issues {
+		co := &hubbub.Conversation{
+			URL:       i.GetHTMLURL(),
+			ID:        i.GetNumber(),
+			Title:     i.GetTitle(),
+			Author:    i.GetUser(),
+			Assignees: i.Assignees,
+			Labels:    i.Labels,
+			Created:   i.GetCreatedAt(),
+			Updated:   i.GetUpdatedAt(),
+		}
+		if parts := strings.Split(co.URL, "/"); len(parts) > 4 {
+			co.Organization = parts[3]
+			co.Project = parts[4]
+		}
+		rr.Items = append(rr.Items, co)
+	}
+	return &triage.CollectionResult{Created: th.Created, RuleResults: []*triage.RuleResult{rr}}
+}
@@ -0,0 +1,40 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package updater
+
+import (
+	"sort"
+
+	"github.com/google/triage-party/pkg/triage"
+)
+
+// byPriority returns collections ordered by descending priority, retaining config order for ties
+func byPriority(sts []triage.Collection) []triage.Collection {
+	ordered := make([]triage.Collection, len(sts))
+	copy(ordered, sts)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Priority > ordered[j].Priority
+	})
+	return ordered
+}
+
+// collectionIDs returns the IDs of a list of collections
+func collectionIDs(sts []triage.Collection) []string {
+	ids := []string{}
+	for _, s := range sts {
+		ids = append(ids, s.ID)
+	}
+	return ids
+}
@@ -0,0 +1,64 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package updater
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// RefreshRepo refreshes the collections which include a repository, which changed at t, without waiting
+// for the next update cycle. Requests for a repository which arrive while its refresh is waiting to start
+// are merged into it. It returns the IDs of the affected collections.
+func (u *Updater) RefreshRepo(ctx context.Context, repoURL string, t time.Time) []string {
+	ids := u.party.RepoUpdated(repoURL, t)
+	if len(ids) == 0 {
+		klog.Infof("%s is not part of any collection, ignoring update", repoURL)
+		return ids
+	}
+
+	u.repoMu.Lock()
+	if u.repoPending[repoURL] {
+		u.repoMu.Unlock()
+		klog.V(1).Infof("refresh of %s is already pending", repoURL)
+		return ids
+	}
+	u.repoPending[repoURL] = true
+	u.repoMu.Unlock()
+
+	// Wait for any refresh in progress, so that changes which arrive meanwhile are picked up by this one
+	u.mutex.Lock()
+	u.repoMu.Lock()
+	delete(u.repoPending, repoURL)
+	u.repoMu.Unlock()
+	u.mutex.Unlock()
+
+	for _, id := range ids {
+		cr := u.Cached(id)
+		if cr == nil {
+			klog.Infof("%s has no results yet, leaving it for the update cycle", id)
+			continue
+		}
+
+		// Other repositories may be served from the cache as they were for the latest results
+		klog.Infof("refreshing %s, as %s changed at %s", id, repoURL, t)
+		if _, err := u.RefreshCollection(ctx, id, cr.NewerThan, true); err != nil {
+			klog.Errorf("refresh %s for %s: %v", id, repoURL, err)
+		}
+	}
+	return ids
+}
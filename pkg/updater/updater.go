@@ -20,10 +20,15 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/google/triage-party/pkg/events"
 	"github.com/google/triage-party/pkg/logu"
+	"github.com/google/triage-party/pkg/metrics"
+	"github.com/google/triage-party/pkg/persist"
 	"github.com/google/triage-party/pkg/triage"
 
 	"k8s.io/klog/v2"
@@ -32,6 +37,13 @@ import (
 // Minimum age to flush to avoid bad behavior
 const minFlushAge = 5 * time.Second
 
+const (
+	// maxHistory is how many previous results to retain per collection, covering the 14 day changes view
+	maxHistory = 30
+	// historyInterval is the minimum time between retained results
+	historyInterval = 12 * time.Hour
+)
+
 type PFunc = func() error
 
 type Config struct {
@@ -39,6 +51,18 @@ type Config struct {
 	MinRefresh  time.Duration
 	MaxRefresh  time.Duration
 	PersistFunc PFunc
+
+	// PersistInterval is the minimum time between saves of new data, defaulting to MaxRefresh
+	PersistInterval time.Duration
+
+	// NoRefresh serves results built once from the cache, rather than refreshing them
+	NoRefresh bool
+
+	// CycleTimeout cancels an update cycle which runs this long. The initial cycle is not limited.
+	CycleTimeout time.Duration
+
+	// HistoryCache retains previous results across restarts, for the changes view
+	HistoryCache persist.Cacher
 }
 
 func New(cfg Config) *Updater {
@@ -48,12 +72,19 @@ func New(cfg Config) *Updater {
 		minRefresh:        cfg.MinRefresh,
 		idleDuration:      5 * time.Minute,
 		cache:             map[string]*triage.CollectionResult{},
+		history:           map[string][]*triage.CollectionResult{},
 		lastRequest:       sync.Map{},
 		secondLastRequest: sync.Map{},
 		loopEvery:         250 * time.Millisecond,
 		mutex:             &sync.Mutex{},
 		persistFunc:       cfg.PersistFunc,
+		persistInterval:   cfg.PersistInterval,
 		startTime:         time.Time{},
+		noRefresh:         cfg.NoRefresh,
+		cycleTimeout:      cfg.CycleTimeout,
+		historyCache:      cfg.HistoryCache,
+		historyLoaded:     map[string]bool{},
+		repoPending:       map[string]bool{},
 	}
 }
 
@@ -63,16 +94,43 @@ type Updater struct {
 	minRefresh        time.Duration
 	idleDuration      time.Duration
 	cache             map[string]*triage.CollectionResult
+	history           map[string][]*triage.CollectionResult
 	lastRequest       sync.Map
 	secondLastRequest sync.Map
-	lastPersist       time.Time
 	lastRun           time.Time
 	startTime         time.Time
 	loopEvery         time.Duration
 	mutex             *sync.Mutex
+	// cacheMu guards the cache and history, along with updateCycles, lastRun, and startTime
+	cacheMu           sync.RWMutex
 	persistFunc       PFunc
-	persistStart      time.Time
+	persistInterval   time.Duration
 	updateCycles      int
+	noRefresh         bool
+	cycleTimeout      time.Duration
+
+	// historyCache persists history, which historyLoaded records as read for each collection
+	historyCache  persist.Cacher
+	historyLoaded map[string]bool
+
+	// emptyRules are the rules which matched nothing when last reported
+	emptyRules string
+
+	// saveMu is held while saving, so that saves never overlap
+	saveMu sync.Mutex
+
+	// persistMu guards the save state below, which request goroutines may read
+	persistMu    sync.Mutex
+	persistStart time.Time
+	lastPersist  time.Time
+	dirty        bool
+
+	// draining is set once the server begins shutting down
+	draining int32
+
+	// repoPending are repositories with a refresh waiting to start
+	repoPending map[string]bool
+	repoMu      sync.Mutex
 
 	state string
 }
@@ -88,16 +146,81 @@ func (u *Updater) recordAccess(id string) {
 
 // State returns a basic state
 func (u *Updater) Status() string {
-	if !u.persistStart.IsZero() {
-		return fmt.Sprintf("%s - persisting since %s (%d cycles, %s uptime)", u.state, u.persistStart, u.updateCycles, time.Since(u.startTime))
+	u.persistMu.Lock()
+	started := u.persistStart
+	u.persistMu.Unlock()
+
+	cycles, _, startTime := u.cycleState()
+	if !started.IsZero() {
+		return fmt.Sprintf("%s - persisting since %s (%d cycles, %s uptime)", u.state, started, cycles, time.Since(startTime))
 	}
-	return fmt.Sprintf("%s (%d cycles, %s uptime)", u.state, u.updateCycles, time.Since(u.startTime))
+	return fmt.Sprintf("%s (%d cycles, %s uptime)", u.state, cycles, time.Since(startTime))
+}
+
+// cycleState returns how many update cycles have completed, when the loop last ran, and when updates started
+func (u *Updater) cycleState() (int, time.Time, time.Time) {
+	u.cacheMu.RLock()
+	defer u.cacheMu.RUnlock()
+	return u.updateCycles, u.lastRun, u.startTime
+}
+
+// markRun records that the update loop has run
+func (u *Updater) markRun() {
+	u.cacheMu.Lock()
+	u.lastRun = time.Now()
+	u.cacheMu.Unlock()
+}
+
+// Ready returns an error if no results are available, or if the update loop has not run within maxAge
+func (u *Updater) Ready(maxAge time.Duration) error {
+	if atomic.LoadInt32(&u.draining) == 1 {
+		return fmt.Errorf("shutting down")
+	}
+	cycles, lastRun, _ := u.cycleState()
+	if cycles == 0 || lastRun.IsZero() {
+		return fmt.Errorf("initial update has not completed")
+	}
+	if maxAge > 0 && !u.noRefresh && time.Since(lastRun) > maxAge {
+		return fmt.Errorf("update loop last ran %s ago", time.Since(lastRun).Round(time.Second))
+	}
+	return nil
+}
+
+// Reload runs fn, such as to replace the configuration, once no collection is being refreshed
+func (u *Updater) Reload(fn func() error) error {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	return fn()
+}
+
+// Drain marks the updater as shutting down, so that it is no longer reported as ready
+func (u *Updater) Drain() {
+	atomic.StoreInt32(&u.draining, 1)
+}
+
+// Cached returns the latest results for a collection, if any, without refreshing or recording access
+func (u *Updater) Cached(id string) *triage.CollectionResult {
+	u.cacheMu.RLock()
+	defer u.cacheMu.RUnlock()
+	return u.cache[id]
+}
+
+// Results returns the latest results for every collection, as of a single moment
+func (u *Updater) Results() map[string]*triage.CollectionResult {
+	u.cacheMu.RLock()
+	defer u.cacheMu.RUnlock()
+
+	crs := make(map[string]*triage.CollectionResult, len(u.cache))
+	for id, cr := range u.cache {
+		crs[id] = cr
+	}
+	return crs
 }
 
 // Lookup results for a given metric
 func (u *Updater) Lookup(ctx context.Context, id string, blocking bool) *triage.CollectionResult {
 	defer u.recordAccess(id)
-	r := u.cache[id]
+	r := u.Cached(id)
 	if r == nil {
 		if blocking {
 			klog.Warningf("%s is not available in the cache, blocking page load!", id)
@@ -108,8 +231,43 @@ func (u *Updater) Lookup(ctx context.Context, id string, blocking bool) *triage.
 			klog.Warningf("%s unavailable, but not blocking: happily returning nil", id)
 		}
 	}
-	r = u.cache[id]
-	return r
+	return u.Cached(id)
+}
+
+// recordHistory retains a bounded set of previous results for a collection. cacheMu must be held.
+func (u *Updater) recordHistory(id string, r *triage.CollectionResult) {
+	u.loadHistory(id)
+	hs := u.history[id]
+	if len(hs) > 0 && r.Created.Sub(hs[len(hs)-1].Created) < historyInterval {
+		return
+	}
+
+	hs = append(hs, r)
+	if len(hs) > maxHistory {
+		hs = hs[len(hs)-maxHistory:]
+	}
+	u.history[id] = hs
+	u.saveHistory(id, hs)
+}
+
+// Previous returns the newest retained result for a collection created before a timestamp.
+// If no result is that old, the oldest retained result is returned.
+func (u *Updater) Previous(id string, since time.Time) *triage.CollectionResult {
+	u.cacheMu.Lock()
+	defer u.cacheMu.Unlock()
+
+	u.loadHistory(id)
+	hs := u.history[id]
+	if len(hs) == 0 {
+		return nil
+	}
+
+	for i := len(hs) - 1; i >= 0; i-- {
+		if !hs[i].Created.After(since) {
+			return hs[i]
+		}
+	}
+	return hs[0]
 }
 
 func (u *Updater) ForceRefresh(ctx context.Context, id string) *triage.CollectionResult {
@@ -131,26 +289,63 @@ func (u *Updater) ForceRefresh(ctx context.Context, id string) *triage.Collectio
 		klog.Errorf("update failed: %v", err)
 	}
 	klog.Infof("refresh complete for %s after %s", id, time.Since(start))
-	return u.cache[id]
+	return u.Cached(id)
+}
+
+// RefreshItem fetches a single issue or PR, updating it within every cached result which shows it
+func (u *Updater) RefreshItem(ctx context.Context, url string) error {
+	start := time.Now()
+	co, err := u.party.RefreshItem(ctx, url)
+	if err != nil {
+		return fmt.Errorf("refresh %s: %w", url, err)
+	}
+
+	u.cacheMu.Lock()
+	defer u.cacheMu.Unlock()
+
+	for id, r := range u.cache {
+		if _, ok := r.MatchedBy[co.URL]; ok {
+			u.cache[id] = triage.ReplaceItem(r, co)
+		}
+	}
+
+	klog.Infof("refreshed %s after %s", url, time.Since(start))
+	return nil
+}
+
+// refreshBounds returns the refresh bounds of a collection, which may override --min-refresh and --max-refresh
+func (u *Updater) refreshBounds(s *triage.Collection) (time.Duration, time.Duration) {
+	minRefresh, maxRefresh := s.RefreshBounds()
+	if minRefresh == 0 {
+		minRefresh = u.minRefresh
+	}
+	if maxRefresh == 0 {
+		maxRefresh = u.maxRefresh
+	}
+	if maxRefresh < minRefresh {
+		maxRefresh = minRefresh
+	}
+	return minRefresh, maxRefresh
 }
 
 // shouldUpdate returns an error if a collection needs an update
-func (u *Updater) shouldUpdate(id string, usedForStats bool, force bool) error {
+func (u *Updater) shouldUpdate(s *triage.Collection, force bool) error {
+	id := s.ID
 	// The first cycle is based on a pared down set of results for faster initial load
-	if u.updateCycles < 2 {
-		return fmt.Errorf("cycle count is only %d", u.updateCycles)
+	if cycles, _, _ := u.cycleState(); cycles < 2 {
+		return fmt.Errorf("cycle count is only %d", cycles)
 	}
 
-	result, ok := u.cache[id]
-	if !ok {
+	result := u.Cached(id)
+	if result == nil {
 		return fmt.Errorf("results are not cached")
 	}
 
 	resultAge := time.Since(result.Created)
-	maxRefresh := u.maxRefresh
+	minRefresh, maxRefresh := u.refreshBounds(s)
 
 	// stats-based metrics can wait longer to refresh
-	if usedForStats {
+	if s.UsedForStats {
 		maxRefresh *= 3
 	}
 
@@ -168,7 +363,7 @@ func (u *Updater) shouldUpdate(id string, usedForStats bool, force bool) error {
 		return nil
 	}
 
-	if resultAge < u.minRefresh {
+	if resultAge < minRefresh {
 		klog.V(4).Infof("too soon since %q was refreshed (%s)", id, resultAge)
 		return nil
 	}
@@ -176,8 +371,8 @@ func (u *Updater) shouldUpdate(id string, usedForStats bool, force bool) error {
 	// Back-off based on average of time since last two requests
 	requestAge := time.Since(u.lastRequested(id))
 	secondRequestDiff := u.lastRequested(id).Sub(u.secondLastRequested(id))
-	needAge := ((requestAge + secondRequestDiff) / 2) + u.minRefresh
-	if resultAge > needAge && !usedForStats {
+	needAge := ((requestAge + secondRequestDiff) / 2) + minRefresh
+	if resultAge > needAge && !s.UsedForStats {
 		return fmt.Errorf("result age (%s) too old based on popularity", resultAge)
 	}
 
@@ -202,14 +397,15 @@ func (u *Updater) lastRequested(id string) time.Time {
 
 // secondLastRequested is the second last time someone requested to view a collection
 func (u *Updater) secondLastRequested(id string) time.Time {
+	_, _, startTime := u.cycleState()
 	x, ok := u.secondLastRequest.Load(id)
 	if !ok {
-		return u.startTime
+		return startTime
 	}
 
 	lr, ok := x.(time.Time)
 	if !ok {
-		return u.startTime
+		return startTime
 	}
 
 	return lr
@@ -221,12 +417,79 @@ func (u *Updater) update(ctx context.Context, s triage.Collection, newerThan tim
 
 	klog.Infof(">>> updating %q with data newer than %s >>>", s.ID, logu.STime(newerThan))
 	r, err := u.party.ExecuteCollection(ctx, s, newerThan)
-	if err != nil {
+	metrics.Timing(metrics.RefreshDuration, time.Since(start), metrics.Tag("collection", s.ID))
+	metrics.Count(metrics.RefreshRuns, 1, metrics.Tag("collection", s.ID))
+
+	var failed triage.RuleErrors
+	if err != nil && !errors.As(err, &failed) {
+		metrics.Count(metrics.RefreshFailures, 1, metrics.Tag("collection", s.ID))
 		return err
 	}
-	u.cache[s.ID] = r
+
+	if len(failed) > 0 {
+		klog.Warningf("%q partially updated, keeping previous results for %d failed rules", s.ID, len(failed))
+	}
+
+	if len(failed) > 0 {
+		metrics.Count(metrics.RefreshFailures, 1, metrics.Tag("collection", s.ID))
+	}
+
+	prev := u.Cached(s.ID)
+	r = u.storeResult(&s, r, failed)
+	if events.Enabled() {
+		events.Emit(collectionEvents(&s, prev, r))
+	}
+	metrics.Gauge(metrics.CollectionSize, float64(r.Total), metrics.Tag("collection", s.ID))
 	klog.Infof("<<< updated %q to %s (oldest input: %s, duration: %s) <<<", s.ID, logu.STime(r.Created), logu.STime(r.OldestInput), time.Since(start))
-	return nil
+	return err
+}
+
+// storeResult caches a new result for a collection, keeping previous results for failed rules
+func (u *Updater) storeResult(s *triage.Collection, r *triage.CollectionResult, failed triage.RuleErrors) *triage.CollectionResult {
+	u.cacheMu.Lock()
+	defer u.cacheMu.Unlock()
+
+	if len(failed) > 0 {
+		r = mergeStale(s, r, u.cache[s.ID])
+	} else {
+		u.recordHistory(s.ID, r)
+	}
+
+	u.cache[s.ID] = r
+	return r
+}
+
+// mergeStale replaces failed rule results with those from a previous result, marking them as stale
+func mergeStale(s *triage.Collection, r *triage.CollectionResult, prev *triage.CollectionResult) *triage.CollectionResult {
+	old := map[string]*triage.RuleResult{}
+	if prev != nil {
+		for _, rr := range prev.RuleResults {
+			if !rr.Stale || len(rr.Items) > 0 {
+				old[rr.Rule.ID] = rr
+			}
+		}
+	}
+
+	oldest := r.OldestInput
+	rrs := []*triage.RuleResult{}
+	for _, rr := range r.RuleResults {
+		if rr.Stale && old[rr.Rule.ID] != nil {
+			kept := *old[rr.Rule.ID]
+			kept.Stale = true
+			kept.Error = rr.Error
+			if kept.OldestInput.Before(oldest) {
+				oldest = kept.OldestInput
+			}
+			rr = &kept
+		}
+		rrs = append(rrs, rr)
+	}
+
+	merged := triage.SummarizeCollectionResult(s, rrs)
+	merged.Created = r.Created
+	merged.NewerThan = r.NewerThan
+	merged.OldestInput = oldest
+	return merged
 }
 
 // Run a single collection, optionally forcing an update
@@ -240,7 +503,12 @@ func (u *Updater) RefreshCollection(ctx context.Context, id string, newerThan ti
 		return false, err
 	}
 
-	err = u.shouldUpdate(s.ID, s.UsedForStats, force)
+	if u.noRefresh && u.Cached(s.ID) != nil {
+		klog.V(1).Infof("refreshes are disabled, keeping cached results for %q", s.ID)
+		return false, nil
+	}
+
+	err = u.shouldUpdate(&s, force)
 	if err == nil {
 		return false, nil
 	}
@@ -250,32 +518,58 @@ func (u *Updater) RefreshCollection(ctx context.Context, id string, newerThan ti
 	return true, err
 }
 
-// Persist saves results to the persistence layer
+// Persist saves results to the persistence layer, unless a save is already in progress
 func (u *Updater) Persist() error {
-	if !u.persistStart.IsZero() {
+	u.persistMu.Lock()
+	busy := !u.persistStart.IsZero()
+	u.persistMu.Unlock()
+
+	if busy {
 		return errors.New("already persisting")
 	}
+	return u.save()
+}
+
+// Flush waits for any save in progress to complete, then saves, so that no new data is lost on shutdown
+func (u *Updater) Flush() error {
+	return u.save()
+}
+
+// save runs the persistence function, once any other save has completed
+func (u *Updater) save() error {
+	u.saveMu.Lock()
+	defer u.saveMu.Unlock()
 
-	// advisory lock
-	u.persistStart = time.Now()
+	start := time.Now()
+	u.persistMu.Lock()
+	u.persistStart = start
+	u.dirty = false
+	u.persistMu.Unlock()
 	klog.Infof("*** Started to persist ...")
 
 	defer func() {
-		klog.Infof("*** Persist complete! Took %s", time.Since(u.persistStart))
+		klog.Infof("*** Persist complete! Took %s", time.Since(start))
+		u.persistMu.Lock()
 		u.persistStart = time.Time{}
 		u.lastPersist = time.Now()
+		u.persistMu.Unlock()
 	}()
 
-	if err := u.persistFunc(); err != nil {
-		return err
-	}
-
-	return nil
+	return u.persistFunc()
 }
 
 func (u *Updater) shouldPersist(updated bool) bool {
+	// Shutdown saves with Flush
+	if atomic.LoadInt32(&u.draining) == 1 {
+		return false
+	}
+
+	u.persistMu.Lock()
+	started, last := u.persistStart, u.lastPersist
+	u.persistMu.Unlock()
+
 	// Already running
-	if !u.persistStart.IsZero() {
+	if !started.IsZero() {
 		return false
 	}
 
@@ -287,8 +581,12 @@ func (u *Updater) shouldPersist(updated bool) bool {
 	// Avoid write contention by fuzzing
 	fuzz := time.Duration(rand.Intn(int(u.maxRefresh.Seconds()))) * time.Second
 	cutoff := u.maxRefresh + fuzz
+	if u.persistInterval > 0 {
+		fuzz = time.Duration(rand.Intn(int(u.persistInterval.Seconds()/10)+1)) * time.Second
+		cutoff = u.persistInterval + fuzz
+	}
 
-	sinceSave := time.Since(u.lastPersist)
+	sinceSave := time.Since(last)
 	if sinceSave > cutoff {
 		klog.Infof("Should persist: we have new data, and it's been %s since the last run", sinceSave)
 		return true
@@ -297,6 +595,20 @@ func (u *Updater) shouldPersist(updated bool) bool {
 	return false
 }
 
+// pending returns the collections which a cycle will update, in priority order
+func (u *Updater) pending(sts []triage.Collection, force bool) []triage.Collection {
+	ps := []triage.Collection{}
+	for i := range sts {
+		if u.noRefresh && u.Cached(sts[i].ID) != nil {
+			continue
+		}
+		if u.shouldUpdate(&sts[i], force) != nil {
+			ps = append(ps, sts[i])
+		}
+	}
+	return ps
+}
+
 // Run once, optionally forcing an update
 func (u *Updater) RunOnce(ctx context.Context, force bool) (bool, error) {
 	updated := false
@@ -304,8 +616,10 @@ func (u *Updater) RunOnce(ctx context.Context, force bool) (bool, error) {
 
 	defer func() {
 		if updated {
+			u.cacheMu.Lock()
 			klog.Infof("update cycle #%d took %s", u.updateCycles, time.Since(start))
 			u.updateCycles++
+			u.cacheMu.Unlock()
 		}
 	}()
 
@@ -322,7 +636,9 @@ func (u *Updater) RunOnce(ctx context.Context, force bool) (bool, error) {
 	}
 
 	if u.lastRun.IsZero() {
+		u.cacheMu.Lock()
 		u.startTime = time.Now()
+		u.cacheMu.Unlock()
 		force = true
 	}
 
@@ -332,27 +648,80 @@ func (u *Updater) RunOnce(ctx context.Context, force bool) (bool, error) {
 		newerThan = time.Time{}
 	}
 
+	sts = byPriority(sts)
+
+	if u.updateCycles > 0 && u.cycleTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, u.cycleTimeout)
+		defer cancel()
+	}
+
+	u.party.Prefetch(ctx, u.pending(sts, force), newerThan)
+
 	var failed []string
-	for _, s := range sts {
+	for i, s := range sts {
+		// Once a cycle overruns, leave the remaining lower priority collections for the next cycle
+		if u.updateCycles > 0 && time.Since(start) > u.maxRefresh {
+			klog.Warningf("update cycle exceeded %s, skipping until next cycle: %v", u.maxRefresh, collectionIDs(sts[i:]))
+			break
+		}
+
 		// Run all collections with the same timestamp for maximum cache sharing
 		runUpdated, err := u.RefreshCollection(ctx, s.ID, newerThan, force)
-		if err != nil {
-			klog.Errorf("%s failed to update: %v", s.ID, err)
-			failed = append(failed, s.ID)
-			continue
-		}
 		if runUpdated {
 			updated = true
 		}
+
+		if ctx.Err() != nil {
+			klog.Warningf("update cycle cancelled after %s (%v), did not finish: %v", time.Since(start), ctx.Err(), collectionIDs(sts[i:]))
+			failed = append(failed, fmt.Sprintf("cycle cancelled: %v", ctx.Err()))
+			break
+		}
+
+		if err != nil {
+			klog.Errorf("%s failed to update: %v", s.ID, err)
+			failed = append(failed, fmt.Sprintf("%s: %v", s.ID, err))
+		}
 	}
 
 	if len(failed) > 0 {
-		return updated, fmt.Errorf("collections failed: %v", failed)
+		return updated, fmt.Errorf("%d collections failed: %s", len(failed), strings.Join(failed, "; "))
 	}
+	metrics.Gauge(metrics.LastRefresh, float64(time.Now().Unix()))
 
+	if updated {
+		u.reportEmptyRules(ctx, sts)
+	}
 	return updated, nil
 }
 
+// reportEmptyRules warns about rules which matched no items in any collection, whenever that set changes
+func (u *Updater) reportEmptyRules(ctx context.Context, sts []triage.Collection) {
+	crs := []*triage.CollectionResult{}
+	for _, s := range sts {
+		crs = append(crs, u.Cached(s.ID))
+	}
+
+	ids := triage.EmptyRules(crs)
+	if strings.Join(ids, ",") == u.emptyRules {
+		return
+	}
+	u.emptyRules = strings.Join(ids, ",")
+
+	for _, id := range ids {
+		klog.Warning(u.party.DescribeEmptyRule(ctx, id))
+	}
+}
+
+// Snapshot builds results for every collection once, for use instead of Loop when refreshes are disabled
+func (u *Updater) Snapshot(ctx context.Context) error {
+	u.state = "building snapshot"
+	_, err := u.RunOnce(ctx, true)
+	u.markRun()
+	u.state = "serving snapshot"
+	return err
+}
+
 // Update loop
 func (u *Updater) Loop(ctx context.Context) error {
 	u.state = "starting loop"
@@ -368,9 +737,17 @@ func (u *Updater) Loop(ctx context.Context) error {
 		}
 
 		u.state = fmt.Sprintf("idle, waiting %s", u.loopEvery)
-		u.lastRun = time.Now()
+		u.markRun()
+
+		// Data from earlier cycles may not have been saved yet
+		u.persistMu.Lock()
+		if updated {
+			u.dirty = true
+		}
+		dirty := u.dirty
+		u.persistMu.Unlock()
 
-		if u.shouldPersist(updated) {
+		if u.shouldPersist(dirty) {
 			go func() {
 				if err := u.Persist(); err != nil {
 					klog.Errorf("persist failed: %v", err)
@@ -0,0 +1,188 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package updater
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/triage-party/pkg/hubbub"
+	"github.com/google/triage-party/pkg/persist"
+	"github.com/google/triage-party/pkg/provider"
+	"github.com/google/triage-party/pkg/triage"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestConcurrentCacheAccess is most useful with -race
+func TestConcurrentCacheAccess(t *testing.T) {
+	u := New(Config{})
+	s := &triage.Collection{ID: "soup"}
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 500; j++ {
+				u.Lookup(ctx, s.ID, false)
+				u.Cached(s.ID)
+				u.Previous(s.ID, time.Now())
+			}
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < 500; j++ {
+			created := time.Now().Add(time.Duration(j) * historyInterval)
+			u.storeResult(s, &triage.CollectionResult{Collection: s, Created: created}, nil)
+		}
+	}()
+
+	wg.Wait()
+	assert.NotNil(t, u.Cached(s.ID))
+	assert.Len(t, u.history[s.ID], maxHistory)
+}
+
+// TestFlushWaitsForSave is most useful with -race
+func TestFlushWaitsForSave(t *testing.T) {
+	var mu sync.Mutex
+	saving, overlapped, saves := false, false, 0
+	u := New(Config{PersistFunc: func() error {
+		mu.Lock()
+		overlapped = overlapped || saving
+		saving = true
+		saves++
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		saving = false
+		mu.Unlock()
+		return nil
+	}})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			u.Persist()
+			u.Status()
+		}()
+	}
+
+	assert.NoError(t, u.Flush())
+	wg.Wait()
+	assert.False(t, overlapped)
+	assert.GreaterOrEqual(t, saves, 1)
+
+	u.Drain()
+	assert.False(t, u.shouldPersist(true))
+}
+
+func TestHistoryPersists(t *testing.T) {
+	c, err := persist.NewMemory(persist.Config{})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	assert.Nil(t, c.Initialize())
+
+	s := &triage.Collection{ID: "soup"}
+	login := "author"
+	co := &hubbub.Conversation{
+		URL:     "https://github.com/org/project/issues/1",
+		ID:      1,
+		Title:   "soup is cold",
+		Author:  &provider.User{Login: &login},
+		Created: time.Now().Add(-48 * time.Hour),
+		Updated: time.Now().Add(-24 * time.Hour),
+	}
+	created := time.Now().Add(-3 * historyInterval)
+	u := New(Config{HistoryCache: c})
+	u.storeResult(s, &triage.CollectionResult{Collection: s, Created: created, RuleResults: []*triage.RuleResult{{Items: []*hubbub.Conversation{co}}}}, nil)
+
+	// A restarted updater finds the previous result in the cache
+	restarted := New(Config{HistoryCache: c})
+	prev := restarted.Previous(s.ID, time.Now())
+	if assert.NotNil(t, prev) {
+		assert.True(t, created.Equal(prev.Created))
+		got := prev.RuleResults[0].Items[0]
+		assert.Equal(t, co.URL, got.URL)
+		assert.Equal(t, "org", got.Organization)
+		assert.Equal(t, co.Title, got.Title)
+		assert.Equal(t, login, got.Author.GetLogin())
+		assert.True(t, co.Updated.Equal(got.Updated))
+	}
+}
+
+func TestByPriority(t *testing.T) {
+	sts := []triage.Collection{{ID: "a"}, {ID: "b", Priority: 10}, {ID: "c", Priority: -1}, {ID: "d"}, {ID: "e", Priority: 10}}
+	assert.Equal(t, []string{"b", "e", "a", "d", "c"}, collectionIDs(byPriority(sts)))
+}
+
+func TestReady(t *testing.T) {
+	u := New(Config{})
+	assert.Error(t, u.Ready(time.Hour))
+
+	u.updateCycles = 1
+	u.lastRun = time.Now()
+	assert.NoError(t, u.Ready(time.Hour))
+
+	// Readiness is polled while the update loop runs
+	done := make(chan bool)
+	go func() {
+		for i := 0; i < 100; i++ {
+			u.markRun()
+		}
+		close(done)
+	}()
+	for i := 0; i < 100; i++ {
+		assert.NoError(t, u.Ready(time.Hour))
+	}
+	<-done
+
+	u.Drain()
+	assert.EqualError(t, u.Ready(time.Hour), "shutting down")
+}
+
+func TestShouldUpdateRefreshBounds(t *testing.T) {
+	u := New(Config{MinRefresh: time.Minute, MaxRefresh: time.Hour})
+	u.updateCycles = 2
+
+	busy := &triage.Collection{ID: "busy", MaxRefresh: "5m"}
+	archived := &triage.Collection{ID: "archived", MinRefresh: "1d"}
+	for _, s := range []*triage.Collection{busy, archived} {
+		u.storeResult(s, &triage.CollectionResult{Collection: s, Created: time.Now().Add(-2 * time.Hour)}, nil)
+		u.recordAccess(s.ID)
+	}
+
+	min, max := u.refreshBounds(busy)
+	assert.Equal(t, time.Minute, min)
+	assert.Equal(t, 5*time.Minute, max)
+
+	// max_refresh is raised to min_refresh
+	min, max = u.refreshBounds(archived)
+	assert.Equal(t, 24*time.Hour, min)
+	assert.Equal(t, 24*time.Hour, max)
+
+	assert.Error(t, u.shouldUpdate(busy, false))
+	assert.NoError(t, u.shouldUpdate(archived, false))
+}